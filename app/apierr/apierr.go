@@ -0,0 +1,158 @@
+package apierr
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Code API 오류 구분. 클라이언트는 message 대신 code로 분기
+type Code string
+
+const (
+	CodeBadRequest   Code = "BAD_REQUEST"
+	CodeUnauthorized Code = "UNAUTHORIZED"
+	CodeForbidden    Code = "FORBIDDEN"
+	CodeNotFound     Code = "NOT_FOUND"
+	CodeConflict     Code = "CONFLICT"
+	CodeTooMany      Code = "TOO_MANY_REQUESTS"
+	CodeInternal     Code = "INTERNAL"
+	CodeUpstream     Code = "UPSTREAM" // 시세 제공처 등 외부 연동 실패
+)
+
+var statuses = map[Code]int{
+	CodeBadRequest:   fiber.StatusBadRequest,
+	CodeUnauthorized: fiber.StatusUnauthorized,
+	CodeForbidden:    fiber.StatusForbidden,
+	CodeNotFound:     fiber.StatusNotFound,
+	CodeConflict:     fiber.StatusConflict,
+	CodeTooMany:      fiber.StatusTooManyRequests,
+	CodeInternal:     fiber.StatusInternalServerError,
+	CodeUpstream:     fiber.StatusBadGateway,
+}
+
+func (c Code) Status() int {
+	if s, ok := statuses[c]; ok {
+		return s
+	}
+	return fiber.StatusInternalServerError
+}
+
+// Error handler가 반환하는 API 오류. middleware가 Code로 상태 코드를 정하고 envelope으로 변환
+type Error struct {
+	Code    Code
+	Message string
+	Details any
+	err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap 원인 오류와 함께 *fiber.Error도 반환. envelope을 쓰지 않는 fiber 기본 error handler에서도 상태 코드 유지
+func (e *Error) Unwrap() []error {
+	return []error{e.err, fiber.NewError(e.Status(), e.Message)}
+}
+
+// Cause 원인 오류. 응답하지 않는 내부 오류 내용 기록용
+func (e *Error) Cause() error {
+	return e.err
+}
+
+func (e *Error) Status() int {
+	return e.Code.Status()
+}
+
+// New err가 Details를 가진 *Error를 감싸고 있으면 Details 유지
+func New(code Code, err error) *Error {
+	e := &Error{Code: code, Message: err.Error(), err: err}
+	var inner *Error
+	if errors.As(err, &inner) {
+		e.Details = inner.Details
+	}
+	return e
+}
+
+// FromStatus handler가 상태 코드와 함께 직접 작성한 응답을 오류로 변환
+func FromStatus(status int, message string) *Error {
+	return &Error{Code: codeOf(status), Message: message}
+}
+
+func codeOf(status int) Code {
+	for code, s := range statuses {
+		if s == status {
+			return code
+		}
+	}
+	if status >= fiber.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// WithDetails 필드별 오류 등 부가 정보
+func (e *Error) WithDetails(details any) *Error {
+	e.Details = details
+	return e
+}
+
+func BadRequest(err error) error {
+	return New(CodeBadRequest, err)
+}
+
+func Unauthorized(err error) error {
+	return New(CodeUnauthorized, err)
+}
+
+func Forbidden() error {
+	return New(CodeForbidden, errors.New("Forbidden"))
+}
+
+func NotFound(err error) error {
+	return New(CodeNotFound, err)
+}
+
+func Conflict(err error) error {
+	return New(CodeConflict, err)
+}
+
+func TooMany(err error) error {
+	return New(CodeTooMany, err)
+}
+
+func Upstream(err error) error {
+	return New(CodeUpstream, err)
+}
+
+/*
+From 오류 분류
+  - *Error는 그대로 사용
+  - 레코드 미존재는 NOT_FOUND, unique 제약 위반은 CONFLICT
+  - 그 외는 INTERNAL. 내부 오류 내용은 응답하지 않음
+*/
+func From(err error) *Error {
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return New(codeOf(fe.Code), err)
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return New(CodeNotFound, err)
+	}
+
+	var me *mysql.MySQLError
+	if errors.Is(err, gorm.ErrDuplicatedKey) || (errors.As(err, &me) && me.Number == 1062) {
+		return New(CodeConflict, err)
+	}
+
+	return &Error{Code: CodeInternal, Message: "내부 오류 발생", err: err}
+}
@@ -5,36 +5,112 @@ import (
 	investind "investindicator"
 	"investindicator/app/handler"
 	"investindicator/app/middleware"
+	"investindicator/app/openapi"
+	"investindicator/bot"
+	"investindicator/internal/apikey"
+	"investindicator/internal/audit"
+	"investindicator/internal/chart"
 	"investindicator/internal/db"
+	"investindicator/internal/export"
+	"investindicator/internal/importer"
+	"investindicator/internal/push"
+	"investindicator/internal/quote"
+	"investindicator/internal/session"
+	"investindicator/notify"
 	"investindicator/scrape"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
 )
 
 // todo. 결국 app 패키지가 구현체에 의존하는 구조 개선 필요
 // todo. 비지니스 로직을 밖으로 빼는 작업이 필요. 로직이 handler에 가니 불필요하게 객체들이 많이 넘어감
-func Run(port int, authKey, passKey string, allowIp []string, stg *db.Storage, scraper *scrape.Scraper, eh *investind.InvestIndicator) {
+func Run(port int, authKey string, allowIp []string, guardOpts []middleware.GuardOption, stg *db.Storage, keys *apikey.Manager, scraper *scrape.Scraper, qc *quote.Cache, prompts *notify.Prompts, eh *investind.InvestIndicator, im *importer.Importer, au *audit.Recorder, hub *push.Hub, bots *bot.TeleBotGroup) {
+
+	app := newApp(authKey, allowIp, guardOpts, stg, keys, scraper, qc, prompts, eh, im, au, hub, bots)
+	app.Listen(fmt.Sprintf(":%d", port))
+}
+
+// newApp 경로 등록. memo. 인증 없이 조회하는 경로는 인증 middleware를 등록하는 group보다 먼저 등록
+func newApp(authKey string, allowIp []string, guardOpts []middleware.GuardOption, stg *db.Storage, keys *apikey.Manager, scraper *scrape.Scraper, qc *quote.Cache, prompts *notify.Prompts, eh *investind.InvestIndicator, im *importer.Importer, au *audit.Recorder, hub *push.Hub, bots *bot.TeleBotGroup) *fiber.App {
 
 	app := fiber.New()
 
-	middleware.SetupMiddleware(app, allowIp)
+	guard := middleware.NewGuard(append(guardOpts, middleware.WithUserKey(handler.RequestKey))...)
+	middleware.SetupMiddleware(app, allowIp, guard)
+
+	sessions := session.NewManager(stg)
+	validator := openapi.NewValidator()
+
+	routes := []interface{ InitRoute(fiber.Router) }{
+		handler.NewAuthHandler(stg, stg, sessions, keys, authKey), // memo. 인증 middleware 등록. 이후 경로만 인증 적용
+		guard,                       // memo. 사용자별 요청 한도 등록. 인증 이후 요청 주체 확인 가능
+		v1Only(validator.Handler),   // memo. /v1 요청 검증 등록. 인증, 요청 한도 이후라 미인증 요청은 검증 오류(400) 대신 401
+		handler.NewAuditHandler(au), // memo. 변경 이력 기록 middleware 등록. 인증 이후 요청 주체 확인 가능
+		handler.NewUserHandler(stg, sessions),
+		handler.NewApiKeyHandler(keys),
+		handler.NewAssetHandler(stg, stg, scraper, qc),
+		handler.NewFundHandler(stg, stg, stg, scraper, eh),
+		handler.NewInvestHandler(stg, eh, scraper),
+		handler.NewMarketHandler(stg, stg),
+		handler.NewCategoryHandler(),
+		handler.NewEventHandler(eh, eh, eh),
+		handler.NewAlertHandler(eh),
+		handler.NewStreamHandler(hub),
+		handler.NewProviderHandler(scraper),
+		handler.NewPromptHandler(prompts),
+		handler.NewChartHandler(chart.NewService(stg)),
+		handler.NewImportHandler(im),
+		handler.NewExportHandler(export.NewService(stg, scraper)),
+		handler.NewBlackholeHandler(stg, nil), // todo. swap executor 구현 후, nil 제거
+	}
+
+	handler.NewHealthHandler(stg, scraper, eh, bots).InitRoute(app) // memo. 인증 없이 조회. guard의 허용 대역으로 접근 제한
+	app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))    // memo. 인증 없이 scrape. guard의 허용 대역으로 접근 제한
 
-	handler.NewAuthHandler(stg, authKey, passKey).InitRoute(app)
-	handler.NewAssetHandler(stg, stg, scraper).InitRoute(app)
-	handler.NewFundHandler(stg, stg, stg, scraper, eh).InitRoute(app)
-	handler.NewInvestHandler(stg, eh, scraper).InitRoute(app)
-	handler.NewMarketHandler(stg, stg).InitRoute(app)
-	handler.NewCategoryHandler().InitRoute(app)
-	handler.NewEventHandler(eh, eh, eh).InitRoute(app)
-	handler.NewBlackholeHandler(stg, nil).InitRoute(app) // todo. swap executor 구현 후, nil 제거
+	docs := handler.NewDocsHandler()
+	docs.InitRoute(app)
 
+	// /v1은 응답 envelope, 오류 코드, 요청 검증 적용. 기존 경로는 deprecated alias로 유지
+	// todo. 클라이언트(web, bot) 이전 후 기존 경로 제거
+	for _, r := range []fiber.Router{
+		app.Group(middleware.V1Prefix, middleware.Envelope),
+		app.Group("", middleware.Deprecated),
+	} {
+		for _, h := range routes {
+			h.InitRoute(r)
+		}
+	}
+
+	doc, undocumented := handler.OpenAPI(app.GetRoutes(true), middleware.V1Prefix)
+	if len(undocumented) > 0 {
+		log.Warn().Strs("routes", undocumented).Msg("OpenAPI 문서 미등록 경로")
+	}
+	docs.Load(doc)
+	validator.Load(doc)
+
+	// memo. 기존 경로 group의 인증 middleware 적용. 로그인한 admin만 가능(API 키 불가)
 	app.Get("/shutdown", func(c *fiber.Ctx) error {
 
 		fmt.Println("Shutting Down")
 		panic("SHUTDOWN")
 	})
 
-	app.Listen(fmt.Sprintf(":%d", port))
+	return app
+}
+
+// v1Only 경로 등록 순서에 /v1 요청 전용 middleware 삽입. 기존 경로 그룹에 등록되어도 /v1 요청에만 적용
+type v1Only fiber.Handler
+
+func (h v1Only) InitRoute(r fiber.Router) {
+	r.Use(func(c *fiber.Ctx) error {
+		if !middleware.IsV1(c) {
+			return c.Next()
+		}
+		return h(c)
+	})
 }
 
 /*
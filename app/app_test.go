@@ -0,0 +1,32 @@
+package app
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewApp(t *testing.T) {
+
+	app := newApp("authkey", []string{"http://localhost"}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	request := func(path string) int {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusOK, request("/metrics"), "인증 없이 scrape")
+	assert.Equal(t, fiber.StatusOK, request("/openapi.json"))
+	assert.Equal(t, fiber.StatusUnauthorized, request("/assets"))
+	assert.Equal(t, fiber.StatusUnauthorized, request("/v1/assets"))
+
+	req := httptest.NewRequest("POST", "/v1/invest", strings.NewReader(`{"fund_id":"abc"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode, "미인증 요청은 요청 검증 전에 거부")
+}
@@ -0,0 +1,581 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"investindicator/app/handler"
+	"investindicator/bot"
+	"investindicator/internal/audit"
+	"investindicator/internal/export"
+	"investindicator/internal/importer"
+	m "investindicator/internal/model"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*
+telegram 쓰기 명령. REST handler와 같은 서비스를 호출
+  - 인자가 부족하면 버튼 선택지로 입력받고, 실행 전 확인 단계를 거침
+  - 조회 명령과 자금 권한(editor 이상)으로 확인하는 invest, import 외에는 관리자 chat에서만 실행 가능
+*/
+
+type storage interface {
+	handler.FundRetriever
+	handler.AssetRetriever
+	handler.AssetInfoSaver
+	handler.MarketSaver
+	RetrieveFundRolesOfUser(userId int) (map[uint]m.FundRole, error)
+}
+
+type indicator interface {
+	handler.InvestSaver
+	handler.EventRetriever
+	handler.EventStatusChanger
+	handler.EventLauncher
+	handler.InvestStatusIndicator
+}
+
+// memo. audit.Recorder가 구현
+type auditor interface {
+	Record(actor m.Actor, entries ...audit.Entry)
+}
+
+// memo. bot.TeleBot, bot.TeleBotGroup이 구현
+type registrar interface {
+	Handle(name string, admin bool, usage string, h bot.CommandHandler)
+}
+
+type Commander struct {
+	stg storage
+	eh  indicator
+	p   handler.PriceGetter
+	ch  handler.ChartRenderer
+	im  handler.InvestImporter
+	ex  handler.Exporter
+	au  auditor
+}
+
+func NewCommander(stg storage, eh indicator, p handler.PriceGetter, ch handler.ChartRenderer, im handler.InvestImporter, ex handler.Exporter, au auditor) *Commander {
+	return &Commander{
+		stg: stg,
+		eh:  eh,
+		p:   p,
+		ch:  ch,
+		im:  im,
+		ex:  ex,
+		au:  au,
+	}
+}
+
+func (c *Commander) InitCommands(r registrar) {
+	r.Handle("invest", false, "/invest <자산 코드|이름> <가격> <수량> [자금 id]", c.Invest)
+	r.Handle("event", true, "/event on|off|run [event id]", c.Event)
+	r.Handle("asset", true, "/asset add <이름> [코드] [category=] [currency=] [top=] [bottom=] [sell=] [buy=] [providers=]", c.Asset)
+	r.Handle("market", true, "/market set [MAJOR_BEAR|BEAR|VOLATILIY|BULL|MAJOR_BULL]", c.Market)
+	r.Handle("plan", false, "/plan [자금 id]", c.Plan)
+	r.Handle("import", false, "/import [kis|upbit|bithumb] [자금 id] (거래 내역 csv/xlsx 파일의 캡션으로 입력)", c.Import)
+	r.Handle("export", false, "/export ledger|holdings|nav|indicators [csv|json|xlsx] [자금 id]", c.Export)
+	r.Handle("chart", false, "/chart fund [자금 id] | asset <자산 코드|이름> | indicators | premium <자산 코드|이름>", c.Chart)
+}
+
+// Invest 투자 이력 기록. 자금 id 미입력 시 선택지로 입력
+func (c *Commander) Invest(s bot.Session, cmd bot.Command) error {
+
+	if len(cmd.Args) < 3 {
+		return errors.New("인자 부족")
+	}
+
+	asset := cmd.Args[0]
+	assetId, err := c.assetId(asset)
+	if err != nil {
+		return err
+	}
+
+	price, err := strconv.ParseFloat(cmd.Args[1], 64)
+	if err != nil {
+		return fmt.Errorf("가격 변환 시 오류 발생. %w", err)
+	}
+	count, err := strconv.ParseFloat(cmd.Args[2], 64)
+	if err != nil {
+		return fmt.Errorf("수량 변환 시 오류 발생. %w", err)
+	}
+
+	fundId, err := c.fundId(s, cmd, cmd.Args[3:], true)
+	if err != nil {
+		return err
+	}
+
+	err = confirm(s, fmt.Sprintf("투자 이력을 기록합니다.\n 자금: %d\n 자산: %s\n 가격: %.3f\n 수량: %.3f", fundId, asset, price, count))
+	if err != nil {
+		return err
+	}
+
+	invest := m.Invest{
+		FundID:  fundId,
+		AssetID: assetId,
+		Price:   price,
+		Count:   count,
+	}
+	err = c.eh.RecordInvest(invest)
+	if err != nil {
+		return fmt.Errorf("RecordInvest 시 오류 발생. %w", err)
+	}
+	c.audit(cmd, audit.Entry{Action: m.AuditCreate, Entity: "invest", EntityID: fundId, After: invest})
+
+	s.Reply("Invest 이력 저장 성공")
+	return nil
+}
+
+// Event 이벤트 활성화/비활성화/즉시 실행. event id 미입력 시 선택지로 입력
+func (c *Commander) Event(s bot.Session, cmd bot.Command) error {
+
+	if len(cmd.Args) < 1 {
+		return errors.New("인자 부족")
+	}
+	action := cmd.Args[0]
+	if action != "on" && action != "off" && action != "run" {
+		return fmt.Errorf("지원하지 않는 동작. %s", action)
+	}
+
+	var id uint
+	if len(cmd.Args) > 1 {
+		n, err := strconv.ParseUint(cmd.Args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("event id 변환 시 오류 발생. %w", err)
+		}
+		id = uint(n)
+	} else {
+		events := c.eh.Events()
+		options := make([]string, len(events))
+		for i, e := range events {
+			options[i] = fmt.Sprintf("%d %s", e.Id, e.Title)
+		}
+		ans, err := s.Choose("대상 event를 선택하세요.", options...)
+		if err != nil {
+			return err
+		}
+		id, err = leadingId(ans)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := confirm(s, fmt.Sprintf("event %d %s", id, action))
+	if err != nil {
+		return err
+	}
+
+	entry := audit.Entry{Entity: "event", EntityID: id}
+	switch action {
+	case "on", "off":
+		err = c.eh.SetEventStatus(id, action == "on")
+		entry.Action, entry.After = m.AuditUpdate, map[string]bool{"active": action == "on"}
+	case "run":
+		err = c.eh.LaunchEvent(id)
+		entry.Action = m.AuditRun
+	}
+	if err != nil {
+		return fmt.Errorf("event %s 시 오류 발생. %w", action, err)
+	}
+	c.audit(cmd, entry)
+
+	s.Reply(fmt.Sprintf("event %d %s 성공", id, action))
+	return nil
+}
+
+// Asset 자산 등록. 카테고리, 통화 미입력 시 선택지로 입력
+func (c *Commander) Asset(s bot.Session, cmd bot.Command) error {
+
+	if len(cmd.Args) < 2 || cmd.Args[0] != "add" {
+		return errors.New("인자 부족")
+	}
+
+	var param handler.AddAssetReq
+	positional := make([]string, 0, 2)
+	for _, arg := range cmd.Args[1:] {
+		k, v, ok := strings.Cut(arg, "=")
+		if !ok {
+			positional = append(positional, arg)
+			continue
+		}
+		var err error
+		switch k {
+		case "category":
+			param.Category = v
+		case "currency":
+			param.Currency = v
+		case "providers":
+			param.Providers = v
+		case "top":
+			param.Top, err = strconv.ParseFloat(v, 64)
+		case "bottom":
+			param.Bottom, err = strconv.ParseFloat(v, 64)
+		case "sell":
+			param.SellPrice, err = strconv.ParseFloat(v, 64)
+		case "buy":
+			param.BuyPrice, err = strconv.ParseFloat(v, 64)
+		default:
+			return fmt.Errorf("지원하지 않는 인자. %s", k)
+		}
+		if err != nil {
+			return fmt.Errorf("%s 변환 시 오류 발생. %w", k, err)
+		}
+	}
+	if len(positional) == 0 {
+		return errors.New("자산 이름 미입력")
+	}
+	param.Name = positional[0]
+	if len(positional) > 1 {
+		param.Code = positional[1]
+	}
+
+	var err error
+	if param.Category == "" {
+		param.Category, err = s.Choose("카테고리를 선택하세요.", m.CategoryList()...)
+		if err != nil {
+			return err
+		}
+	}
+	if param.Currency == "" {
+		param.Currency, err = s.Choose("통화를 선택하세요.", m.CurrencyList()...)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = confirm(s, fmt.Sprintf("자산을 등록합니다.\n 이름: %s\n 코드: %s\n 카테고리: %s\n 통화: %s", param.Name, param.Code, param.Category, param.Currency))
+	if err != nil {
+		return err
+	}
+
+	id, err := handler.SaveAsset(c.stg, c.p, param)
+	if err != nil {
+		return err
+	}
+	c.audit(cmd, audit.Entry{Action: m.AuditCreate, Entity: "asset", EntityID: id, After: param})
+
+	s.Reply(fmt.Sprintf("자산 정보 저장 성공. id: %d", id))
+	return nil
+}
+
+// Market 시장 단계 변경. 단계 미입력 시 선택지로 입력
+func (c *Commander) Market(s bot.Session, cmd bot.Command) error {
+
+	if len(cmd.Args) < 1 || cmd.Args[0] != "set" {
+		return errors.New("인자 부족")
+	}
+
+	levels := make([]string, 0, m.MAJOR_BULL)
+	for l := m.MAJOR_BEAR; l <= m.MAJOR_BULL; l++ {
+		levels = append(levels, l.String())
+	}
+
+	var level string
+	if len(cmd.Args) > 1 {
+		level = strings.ToUpper(cmd.Args[1])
+	} else {
+		ans, err := s.Choose("시장 단계를 선택하세요.", levels...)
+		if err != nil {
+			return err
+		}
+		level = ans
+	}
+
+	status := 0
+	for i, l := range levels {
+		if l == level {
+			status = i + 1
+		}
+	}
+	if status == 0 {
+		return fmt.Errorf("존재하지 않는 시장 단계. %s", level)
+	}
+
+	err := confirm(s, fmt.Sprintf("시장 단계를 %s로 변경합니다.", level))
+	if err != nil {
+		return err
+	}
+
+	err = c.stg.SaveMarketStatus(uint(status))
+	if err != nil {
+		return fmt.Errorf("SaveMarketStatus 시 오류 발생. %w", err)
+	}
+	c.audit(cmd, audit.Entry{Action: m.AuditUpdate, Entity: "market", After: map[string]int{"status": status}})
+
+	s.Reply("시장 상태 저장 성공")
+	return nil
+}
+
+// Plan 시장 단계 기준 자금의 변동성 자산 추가 투자 가능 금액 조회
+func (c *Commander) Plan(s bot.Session, cmd bot.Command) error {
+
+	fundId, err := c.fundId(s, cmd, cmd.Args, false)
+	if err != nil {
+		return err
+	}
+
+	amount, err := c.eh.InvestAvailableAmount(int(fundId))
+	if err != nil {
+		return fmt.Errorf("InvestAvailableAmount 시 오류 발생. %w", err)
+	}
+
+	s.Reply(fmt.Sprintf("자금 %d 변동성 자산 투자 가능 금액: %.0f", fundId, amount))
+	return nil
+}
+
+// Chart 자금 평가 총액, 자산 가격, 시장 지표, 김치 프리미엄 차트 전송
+func (c *Commander) Chart(s bot.Session, cmd bot.Command) error {
+
+	if len(cmd.Args) < 1 {
+		return errors.New("인자 부족")
+	}
+
+	var png []byte
+	var err error
+	switch cmd.Args[0] {
+	case "fund":
+		var fundId uint
+		fundId, err = c.fundId(s, cmd, cmd.Args[1:], false)
+		if err != nil {
+			return err
+		}
+		png, err = c.ch.FundNav(fundId, 0)
+	case "asset", "premium":
+		if len(cmd.Args) < 2 {
+			return errors.New("자산 미입력")
+		}
+		var assetId uint
+		assetId, err = c.assetId(cmd.Args[1])
+		if err != nil {
+			return err
+		}
+		if cmd.Args[0] == "asset" {
+			png, err = c.ch.Asset(assetId, 0)
+		} else {
+			png, err = c.ch.Premium(assetId, 0)
+		}
+	case "indicators":
+		png, err = c.ch.Indicators(0)
+	default:
+		return fmt.Errorf("지원하지 않는 차트. %s", cmd.Args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("차트 생성 시 오류 발생. %w", err)
+	}
+
+	s.ReplyPhoto(cmd.Args[0]+".png", png, strings.Join(cmd.Args, " "))
+	return nil
+}
+
+// Export 투자 이력, 보유 자산, 자금 평가 총액, 지표 이력을 파일로 전송. 형식 미입력 시 csv
+func (c *Commander) Export(s bot.Session, cmd bot.Command) error {
+
+	if len(cmd.Args) < 1 {
+		return errors.New("인자 부족")
+	}
+
+	format := export.CSV
+	args := make([]string, 0, 1)
+	for _, arg := range cmd.Args[1:] {
+		if f, err := export.ParseFormat(arg); err == nil {
+			format = f
+			continue
+		}
+		args = append(args, arg)
+	}
+
+	var t *export.Table
+	var err error
+	switch cmd.Args[0] {
+	case "ledger", "holdings":
+		var q m.InvestQuery
+		if len(args) > 0 {
+			q.FundID, err = c.fundId(s, cmd, args, false)
+		} else if !cmd.IsAdmin() {
+			var roles map[uint]m.FundRole
+			roles, err = c.stg.RetrieveFundRolesOfUser(cmd.User.ID)
+			q.Funds = make([]uint, 0, len(roles))
+			for id := range roles {
+				q.Funds = append(q.Funds, id)
+			}
+			slices.Sort(q.Funds)
+		}
+		if err != nil {
+			return err
+		}
+		if cmd.Args[0] == "ledger" {
+			t, err = c.ex.Ledger(q)
+		} else {
+			t, err = c.ex.Holdings(q)
+		}
+	case "nav":
+		var fundId uint
+		fundId, err = c.fundId(s, cmd, args, false)
+		if err != nil {
+			return err
+		}
+		t, err = c.ex.FundNavs(fundId, 0)
+	case "indicators":
+		t, err = c.ex.Indicators(0)
+	default:
+		return fmt.Errorf("지원하지 않는 내보내기. %s", cmd.Args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("내보내기 조회 시 오류 발생. %w", err)
+	}
+
+	b, err := export.Encode(t, format)
+	if err != nil {
+		return fmt.Errorf("Encode 시 오류 발생. %w", err)
+	}
+
+	s.ReplyDocument(t.FileName(format), b, fmt.Sprintf("%s %d건", t.Name, len(t.Rows)))
+	return nil
+}
+
+// Import 첨부한 거래 내역 파일을 미리보기 후 확인 시 기록. 중복 행은 제외
+func (c *Commander) Import(s bot.Session, cmd bot.Command) error {
+
+	if cmd.File == nil {
+		return errors.New("거래 내역 파일 미첨부")
+	}
+
+	var broker importer.Broker
+	args := make([]string, 0, 1)
+	for _, arg := range cmd.Args {
+		if b, err := importer.ParseBroker(arg); err == nil {
+			broker = b
+			continue
+		}
+		args = append(args, arg)
+	}
+
+	fundId, err := c.fundId(s, cmd, args, true)
+	if err != nil {
+		return err
+	}
+
+	b, err := c.im.Preview(cmd.File.Name, bytes.NewReader(cmd.File.Data), broker, fundId, actor(cmd).Key())
+	if err != nil {
+		return fmt.Errorf("Preview 시 오류 발생. %w", err)
+	}
+
+	err = confirm(s, fmt.Sprintf("거래 내역을 자금 %d에 기록합니다.\n%s", fundId, b.Summary()))
+	if err != nil {
+		c.im.Discard(b.ID)
+		return err
+	}
+
+	n, err := c.im.Commit(b.ID, nil, false)
+	if err != nil {
+		c.im.Discard(b.ID)
+		return fmt.Errorf("Commit 시 오류 발생. %w", err)
+	}
+	c.audit(cmd, audit.Entry{Action: m.AuditCreate, Entity: "import", EntityID: b.ID, After: map[string]any{"name": b.Name, "broker": b.Broker, "fund_id": fundId, "recorded": n}})
+
+	s.Reply(fmt.Sprintf("거래 내역 %d건 기록 성공", n))
+	return nil
+}
+
+// audit 명령 실행 결과 변경 이력 기록
+func (c *Commander) audit(cmd bot.Command, entries ...audit.Entry) {
+	c.au.Record(actor(cmd), entries...)
+}
+
+// actor 명령 실행 주체. 연동된 사용자가 없으면 chat 기준
+func actor(cmd bot.Command) m.Actor {
+	actor := m.Actor{Type: m.ActorChat, ID: strconv.FormatInt(cmd.ChatId, 10), Source: m.SourceBot}
+	if cmd.User != nil {
+		actor.Type, actor.ID = m.ActorUser, strconv.Itoa(cmd.User.ID)
+	}
+	return actor
+}
+
+// assetId 자산 코드 혹은 이름으로 자산 id 조회
+func (c *Commander) assetId(asset string) (uint, error) {
+	assetId := c.stg.RetrieveAssetIdByCode(asset)
+	if assetId == 0 {
+		assetId = c.stg.RetrieveAssetIdByName(asset)
+	}
+	if assetId == 0 {
+		return 0, fmt.Errorf("미등록 자산. %s", asset)
+	}
+	return assetId, nil
+}
+
+/*
+fundId 인자로 입력된 자금 id. 미입력 시 자금 목록 선택지로 입력
+  - admin이 아니면 조회 가능한 자금만 허용
+  - edit이면 editor 이상 권한이 있는 자금만 허용. 투자 이력 기록용
+*/
+func (c *Commander) fundId(s bot.Session, cmd bot.Command, args []string, edit bool) (uint, error) {
+
+	visible := func(uint) bool { return true }
+	if !cmd.IsAdmin() {
+		roles, err := c.stg.RetrieveFundRolesOfUser(cmd.User.ID)
+		if err != nil {
+			return 0, fmt.Errorf("RetrieveFundRolesOfUser 시 오류 발생. %w", err)
+		}
+		visible = func(id uint) bool {
+			role, ok := roles[id]
+			return ok && (!edit || role.CanEdit())
+		}
+	}
+
+	if len(args) > 0 {
+		n, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("자금 id 변환 시 오류 발생. %w", err)
+		}
+		if !visible(uint(n)) {
+			return 0, fmt.Errorf("권한이 없는 자금. %d", n)
+		}
+		return uint(n), nil
+	}
+
+	summaries, err := c.stg.RetreiveFundsSummaryOrderByFundId()
+	if err != nil {
+		return 0, fmt.Errorf("RetreiveFundsSummary 시 오류 발생. %w", err)
+	}
+	options := make([]string, 0)
+	var prev uint
+	for _, is := range summaries {
+		if is.FundID == prev || !visible(is.FundID) {
+			continue
+		}
+		prev = is.FundID
+		options = append(options, fmt.Sprintf("%d %s", is.FundID, is.Fund.Name))
+	}
+	if len(options) == 0 {
+		return 0, errors.New("권한이 있는 자금 미존재")
+	}
+
+	ans, err := s.Choose("자금을 선택하세요.", options...)
+	if err != nil {
+		return 0, err
+	}
+	return leadingId(ans)
+}
+
+func confirm(s bot.Session, prompt string) error {
+	ok, err := s.Confirm(prompt)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return bot.ErrCancelled
+	}
+	return nil
+}
+
+// leadingId "3 title" 형식 선택지에서 id 추출
+func leadingId(option string) (uint, error) {
+	id, _, _ := strings.Cut(option, " ")
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("id 변환 시 오류 발생. %w", err)
+	}
+	return uint(n), nil
+}
@@ -0,0 +1,272 @@
+package command
+
+import (
+	investind "investindicator"
+	"investindicator/app/handler"
+	"investindicator/bot"
+	"investindicator/internal/audit"
+	"investindicator/internal/export"
+	"investindicator/internal/importer"
+	m "investindicator/internal/model"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type storageMock struct {
+	storage // 미사용 메서드
+	status  uint
+}
+
+func (s *storageMock) RetrieveAssetIdByCode(code string) uint {
+	if code == "BTC" {
+		return 1
+	}
+	return 0
+}
+
+func (s *storageMock) RetrieveAssetIdByName(name string) uint { return 0 }
+
+func (s *storageMock) RetreiveFundsSummaryOrderByFundId() ([]m.InvestSummary, error) {
+	return []m.InvestSummary{
+		{FundID: 1, Fund: m.Fund{Name: "개인"}},
+		{FundID: 1, Fund: m.Fund{Name: "개인"}},
+		{FundID: 2, Fund: m.Fund{Name: "연금"}},
+	}, nil
+}
+
+// RetrieveFundRolesOfUser 사용자 8은 자금 1 viewer, 자금 2 editor. 그 외 사용자는 자금 1 viewer
+func (s *storageMock) RetrieveFundRolesOfUser(userId int) (map[uint]m.FundRole, error) {
+	if userId == 8 {
+		return map[uint]m.FundRole{1: m.FundViewer, 2: m.FundEditor}, nil
+	}
+	return map[uint]m.FundRole{1: m.FundViewer}, nil
+}
+
+func (s *storageMock) SaveMarketStatus(status uint) error {
+	s.status = status
+	return nil
+}
+
+type indicatorMock struct {
+	indicator // 미사용 메서드
+	invests   []m.Invest
+	active    map[uint]bool
+}
+
+func (i *indicatorMock) RecordInvest(invest m.Invest) error {
+	i.invests = append(i.invests, invest)
+	return nil
+}
+
+func (i *indicatorMock) InvestAvailableAmount(fundId int) (float64, error) {
+	return 1000, nil
+}
+
+func (i *indicatorMock) Events() []*investind.EnrolledEvent {
+	return []*investind.EnrolledEvent{{Id: 3, Title: "EMA"}}
+}
+
+func (i *indicatorMock) SetEventStatus(id uint, active bool) error {
+	i.active[id] = active
+	return nil
+}
+
+type chartMock struct {
+	handler.ChartRenderer // 미사용 메서드
+}
+
+func (chartMock) Asset(assetId uint, days int) ([]byte, error) {
+	return []byte("png"), nil
+}
+
+type importerMock struct {
+	handler.InvestImporter // 미사용 메서드
+	committed              []string
+	discarded              []string
+}
+
+func (im *importerMock) Preview(name string, r io.Reader, broker importer.Broker, fundId uint, owner string) (*importer.Batch, error) {
+	return &importer.Batch{ID: name, Name: name, Owner: owner, Broker: broker}, nil
+}
+
+func (im *importerMock) Commit(id string, funds map[int]uint, includeDuplicates bool) (int, error) {
+	im.committed = append(im.committed, id)
+	return 2, nil
+}
+
+func (im *importerMock) Discard(id string) error {
+	im.discarded = append(im.discarded, id)
+	return nil
+}
+
+type exporterMock struct {
+	handler.Exporter // 미사용 메서드
+	queries          []m.InvestQuery
+}
+
+func (e *exporterMock) Holdings(q m.InvestQuery) (*export.Table, error) {
+	e.queries = append(e.queries, q)
+	return &export.Table{Name: "holdings", Header: []string{"fund_id"}, Rows: [][]any{{uint(1)}}}, nil
+}
+
+type auditorMock struct {
+	actors  []m.Actor
+	entries []audit.Entry
+}
+
+func (a *auditorMock) Record(actor m.Actor, entries ...audit.Entry) {
+	for _, e := range entries {
+		a.actors = append(a.actors, actor)
+		a.entries = append(a.entries, e)
+	}
+}
+
+// sessionMock 선택지 요청 시 answers를 순서대로 응답
+type sessionMock struct {
+	answers   []string
+	options   [][]string
+	replies   []string
+	photos    []string
+	documents []string
+}
+
+func (s *sessionMock) Reply(msg string) {
+	s.replies = append(s.replies, msg)
+}
+
+func (s *sessionMock) ReplyPhoto(name string, png []byte, caption string) {
+	s.photos = append(s.photos, name)
+}
+
+func (s *sessionMock) ReplyDocument(name string, data []byte, caption string) {
+	s.documents = append(s.documents, name)
+}
+
+func (s *sessionMock) Choose(prompt string, options ...string) (string, error) {
+	s.options = append(s.options, options)
+	ans := s.answers[0]
+	s.answers = s.answers[1:]
+	return ans, nil
+}
+
+func (s *sessionMock) Confirm(prompt string) (bool, error) {
+	ans, err := s.Choose(prompt, "확인", "취소")
+	return ans == "확인", err
+}
+
+func TestCommander(t *testing.T) {
+
+	stg := &storageMock{}
+	eh := &indicatorMock{active: make(map[uint]bool)}
+	im := &importerMock{}
+	ex := &exporterMock{}
+	au := &auditorMock{}
+	c := NewCommander(stg, eh, nil, chartMock{}, im, ex, au)
+
+	t.Run("Invest", func(t *testing.T) {
+		s := &sessionMock{answers: []string{"2 연금", "확인"}}
+		err := c.Invest(s, bot.Command{Name: "invest", Args: []string{"BTC", "1000", "0.5"}})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1 개인", "2 연금"}, s.options[0])
+		assert.Equal(t, []m.Invest{{FundID: 2, AssetID: 1, Price: 1000, Count: 0.5}}, eh.invests)
+		assert.Equal(t, m.Actor{Type: m.ActorChat, ID: "0", Source: m.SourceBot}, au.actors[0], "연동 사용자 없으면 chat 기준")
+		assert.Equal(t, "invest", au.entries[0].Entity)
+	})
+
+	t.Run("Invest Cancelled", func(t *testing.T) {
+		s := &sessionMock{answers: []string{"취소"}}
+		err := c.Invest(s, bot.Command{Name: "invest", Args: []string{"BTC", "1000", "0.5", "1"}})
+		assert.ErrorIs(t, err, bot.ErrCancelled)
+		assert.Len(t, eh.invests, 1)
+	})
+
+	t.Run("Invest Unknown Asset", func(t *testing.T) {
+		err := c.Invest(&sessionMock{}, bot.Command{Name: "invest", Args: []string{"ETH", "1000", "0.5", "1"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("Plan Viewer", func(t *testing.T) {
+		viewer := &m.User{ID: 7}
+		s := &sessionMock{answers: []string{"1 개인"}}
+		err := c.Plan(s, bot.Command{Name: "plan", User: viewer})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1 개인"}, s.options[0]) // 조회 가능한 자금만 선택지로 제공
+
+		err = c.Plan(&sessionMock{}, bot.Command{Name: "plan", Args: []string{"2"}, User: viewer})
+		assert.Error(t, err)
+	})
+
+	t.Run("Invest Editor", func(t *testing.T) {
+		editor := &m.User{ID: 8}
+		s := &sessionMock{answers: []string{"2 연금", "확인"}}
+		err := c.Invest(s, bot.Command{Name: "invest", Args: []string{"BTC", "1000", "0.5"}, User: editor})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2 연금"}, s.options[0]) // 기록 권한이 있는 자금만 선택지로 제공
+
+		err = c.Invest(&sessionMock{}, bot.Command{Name: "invest", Args: []string{"BTC", "1000", "0.5", "1"}, User: editor})
+		assert.Error(t, err, "viewer 자금에는 기록 불가")
+		assert.Len(t, eh.invests, 2)
+		assert.Equal(t, m.Actor{Type: m.ActorUser, ID: "8", Source: m.SourceBot}, au.actors[1])
+		assert.Len(t, au.entries, 2, "실패한 명령은 미기록")
+	})
+
+	t.Run("Event", func(t *testing.T) {
+		s := &sessionMock{answers: []string{"3 EMA", "확인"}}
+		err := c.Event(s, bot.Command{Name: "event", Args: []string{"off"}})
+		assert.NoError(t, err)
+		assert.False(t, eh.active[3])
+		assert.Contains(t, eh.active, uint(3))
+		assert.Equal(t, audit.Entry{Action: m.AuditUpdate, Entity: "event", EntityID: uint(3), After: map[string]bool{"active": false}}, au.entries[2])
+	})
+
+	t.Run("Market", func(t *testing.T) {
+		s := &sessionMock{answers: []string{"BULL", "확인"}}
+		err := c.Market(s, bot.Command{Name: "market", Args: []string{"set"}})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(m.BULL), stg.status)
+
+		err = c.Market(&sessionMock{}, bot.Command{Name: "market", Args: []string{"set", "crash"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("Chart", func(t *testing.T) {
+		s := &sessionMock{}
+		err := c.Chart(s, bot.Command{Name: "chart", Args: []string{"asset", "BTC"}})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"asset.png"}, s.photos)
+
+		err = c.Chart(s, bot.Command{Name: "chart", Args: []string{"asset"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		s := &sessionMock{}
+		err := c.Export(s, bot.Command{Name: "export", Args: []string{"holdings", "xlsx", "2"}})
+		assert.NoError(t, err)
+		assert.Equal(t, m.InvestQuery{FundID: 2}, ex.queries[0])
+		assert.Len(t, s.documents, 1)
+		assert.True(t, strings.HasSuffix(s.documents[0], ".xlsx"))
+
+		err = c.Export(s, bot.Command{Name: "export", Args: []string{"trades"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		err := c.Import(&sessionMock{}, bot.Command{Name: "import", Args: []string{"upbit", "1"}})
+		assert.Error(t, err)
+
+		file := &bot.File{Name: "upbit.csv", Data: []byte("체결시간,코인")}
+		s := &sessionMock{answers: []string{"확인"}}
+		err = c.Import(s, bot.Command{Name: "import", Args: []string{"upbit", "1"}, File: file})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"upbit.csv"}, im.committed)
+
+		s = &sessionMock{answers: []string{"취소"}}
+		err = c.Import(s, bot.Command{Name: "import", Args: []string{"1"}, File: file})
+		assert.ErrorIs(t, err, bot.ErrCancelled)
+		assert.Equal(t, []string{"upbit.csv"}, im.discarded)
+	})
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type AlertHandler struct {
+	r SuppressedAlertRetriever
+}
+
+func NewAlertHandler(r SuppressedAlertRetriever) *AlertHandler {
+	return &AlertHandler{
+		r: r,
+	}
+}
+
+func (h *AlertHandler) InitRoute(r fiber.Router) {
+	router := r.Group("/alerts")
+	router.Get("/suppressed", h.Suppressed)
+}
+
+// 중복 억제로 현재 전송되지 않는 알림 목록
+func (h *AlertHandler) Suppressed(c *fiber.Ctx) error {
+
+	alerts := h.r.SuppressedAlerts()
+
+	resp := make([]suppressedAlertResponse, len(alerts))
+	for i, a := range alerts {
+		resp[i] = suppressedAlertResponse{
+			Type:      string(a.Type),
+			Id:        a.Id,
+			Price:     a.Price,
+			SentAt:    a.SentAt.Format("2006-01-02 15:04:05"),
+			ExpiresAt: a.ExpiresAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
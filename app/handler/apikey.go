@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"fmt"
+	"investindicator/app/apierr"
+	"investindicator/internal/audit"
+	m "investindicator/internal/model"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ApiKeyHandler bot, script용 API 키 관리. 로그인한 admin만 사용 가능
+type ApiKeyHandler struct {
+	ak ApiKeyManager
+}
+
+func NewApiKeyHandler(ak ApiKeyManager) *ApiKeyHandler {
+	return &ApiKeyHandler{
+		ak: ak,
+	}
+}
+
+func (h *ApiKeyHandler) InitRoute(r fiber.Router) {
+
+	router := r.Group("/apikeys")
+	router.Get("/", h.Keys)
+	router.Post("/", h.Issue)
+	router.Delete("/:id<\\d+>", h.Revoke)
+}
+
+// Keys 폐기, 만료된 키 포함 전체 목록
+func (h *ApiKeyHandler) Keys(c *fiber.Ctx) error {
+
+	if !isAdmin(c) {
+		return forbidden(c)
+	}
+
+	keys, err := h.ak.Keys()
+	if err != nil {
+		return fmt.Errorf("Keys 시 오류 발생. %w", err)
+	}
+
+	resp := make([]ApiKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		resp = append(resp, apiKeyResponse(k))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+func (h *ApiKeyHandler) Issue(c *fiber.Ctx) error {
+
+	var req AddApiKeyReq
+	err := c.BodyParser(&req)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
+	}
+
+	err = validCheck(&req)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
+	}
+
+	scopes, err := m.ParseScopes(req.Scopes)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("scope 변환 시 오류 발생. %w", err))
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays > 0 {
+		t := time.Now().AddDate(0, 0, int(req.ExpiresInDays))
+		expiresAt = &t
+	}
+
+	key, raw, err := h.ak.Issue(req.Name, scopes, expiresAt)
+	if err != nil {
+		return fmt.Errorf("Issue 시 오류 발생. %w", err)
+	}
+	addAudit(c, audit.Entry{Action: m.AuditCreate, Entity: "apikey", EntityID: key.ID, After: apiKeyResponse(*key)})
+
+	return c.Status(fiber.StatusOK).JSON(IssuedApiKeyResponse{
+		ApiKeyResponse: apiKeyResponse(*key),
+		Key:            raw,
+	})
+}
+
+func (h *ApiKeyHandler) Revoke(c *fiber.Ctx) error {
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
+	}
+
+	err = h.ak.Revoke(uint(id))
+	if err != nil {
+		return fmt.Errorf("Revoke 시 오류 발생. %w", err)
+	}
+	addAudit(c, audit.Entry{Action: m.AuditDelete, Entity: "apikey", EntityID: id})
+
+	return c.Status(fiber.StatusOK).SendString("API 키 폐기 성공")
+}
+
+func apiKeyResponse(k m.ApiKey) ApiKeyResponse {
+
+	scopes := make([]string, 0)
+	for _, s := range k.ScopeList() {
+		scopes = append(scopes, string(s))
+	}
+
+	return ApiKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
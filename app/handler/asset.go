@@ -2,7 +2,10 @@ package handler
 
 import (
 	"fmt"
+	"investindicator/app/apierr"
+	"investindicator/internal/audit"
 	m "investindicator/internal/model"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -11,19 +14,21 @@ type AssetHandler struct {
 	r AssetRetriever
 	w AssetInfoSaver
 	p PriceGetter
+	q QuoteGetter
 }
 
-func NewAssetHandler(r AssetRetriever, w AssetInfoSaver, p PriceGetter) *AssetHandler {
+func NewAssetHandler(r AssetRetriever, w AssetInfoSaver, p PriceGetter, q QuoteGetter) *AssetHandler {
 	return &AssetHandler{
 		r: r,
 		w: w,
 		p: p,
+		q: q,
 	}
 }
 
-func (h *AssetHandler) InitRoute(app *fiber.App) {
+func (h *AssetHandler) InitRoute(r fiber.Router) {
 
-	router := app.Group("/assets")
+	router := r.Group("/assets")
 
 	router.Post("/", h.AddAsset)
 	router.Put("/", h.UpdateAsset)
@@ -34,25 +39,33 @@ func (h *AssetHandler) InitRoute(app *fiber.App) {
 	router.Get("/:id<\\d+>/hist", h.AssetHist)
 }
 
+// max_age(초) 쿼리로 허용할 시세 나이 지정. 미지정 시 카테고리 TTL 사용
+// query: category, currency, q(이름, 코드 부분 일치), sort(id|name|code|category), 페이지
 func (h *AssetHandler) Assets(c *fiber.Ctx) error {
 
-	assets, err := h.r.RetrieveAssetList()
+	maxAge := c.QueryInt("max_age", 0)
+	if maxAge < 0 {
+		return apierr.BadRequest(fmt.Errorf("파라미터 max_age 오류. 입력 값 : %d", maxAge))
+	}
+
+	aq, err := assetQuery(c)
 	if err != nil {
-		return fmt.Errorf("RetrieveAssetList 오류 발생. %w", err)
+		return err
 	}
 
-	rtn := make([]assetResponse, len(assets))
+	list, err := h.r.RetrieveAssets(aq)
+	if err != nil {
+		return fmt.Errorf("RetrieveAssets 오류 발생. %w", err)
+	}
 
-	for i, a := range assets {
-		asset, err := h.r.RetrieveAsset(a.ID)
-		if err != nil {
-			return fmt.Errorf("RetrieveAsset 오류 발생. %w", err)
-		}
+	rtn := make([]assetResponse, len(list.Items))
 
-		pp, err := h.p.PresentPrice(asset.Category, asset.Code)
+	for i, asset := range list.Items {
+
+		q, err := h.q.Quote(asset.Category, asset.Code, time.Duration(maxAge)*time.Second, asset.ProviderChain()...)
 		if err != nil {
 			// todo. log
-			pp = 0
+			q = m.Quote{}
 		}
 
 		rtn[i] = assetResponse{
@@ -65,11 +78,14 @@ func (h *AssetHandler) Assets(c *fiber.Ctx) error {
 			Bottom:    asset.Bottom,
 			SellPrice: asset.SellPrice,
 			BuyPrice:  asset.BuyPrice,
-			Price:     pp,
+			Price:     q.Price,
+			Providers: m.JoinProviders(asset.ProviderChain()),
+			Quote:     newQuoteResponse(q),
 		}
 
 	}
 
+	setPage(c, list.Total, list.NextCursor)
 	return c.Status(fiber.StatusOK).JSON(rtn)
 }
 
@@ -78,21 +94,43 @@ func (h *AssetHandler) AddAsset(c *fiber.Ctx) error {
 	var param AddAssetReq
 	err := c.BodyParser(&param)
 	if err != nil {
-		return fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
 	}
 
-	err = validCheck(&param)
+	id, err := SaveAsset(h.w, h.p, param)
 	if err != nil {
-		return fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err)
+		return err
+	}
+	addAudit(c, audit.Entry{Action: m.AuditCreate, Entity: "asset", EntityID: id, After: param})
+
+	return c.Status(fiber.StatusOK).SendString("자산 정보 저장 성공")
+}
+
+/*
+SaveAsset 자산 등록. REST, telegram 명령에서 공통 사용
+  - top/bottom 미입력 시 조회한 최고/최저가 사용
+  - buy 미입력 시 bottom 사용
+  - ema 미입력 시 조회한 평균가로 EMA 이력 저장
+*/
+func SaveAsset(w AssetInfoSaver, p PriceGetter, param AddAssetReq) (uint, error) {
+
+	err := validCheck(&param)
+	if err != nil {
+		return 0, apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
 	}
 	category, err := m.ToCategory(param.Category)
 	if err != nil {
-		return fmt.Errorf("카테고리 변환 시 오류 발생. %w", err)
+		return 0, apierr.BadRequest(fmt.Errorf("카테고리 변환 시 오류 발생. %w", err))
+	}
+
+	providers, err := providerChain(category, param.Providers)
+	if err != nil {
+		return 0, apierr.BadRequest(err)
 	}
 
 	top, bottom := param.Top, param.Bottom
 	if top == 0 || bottom == 0 {
-		_top, _bottom, _ := h.p.TopBottomPrice(category, param.Code) // 오류 처리 불필요. 오류 발생 시 0,0 값으로 사용
+		_top, _bottom, _ := p.TopBottomPrice(category, param.Code) // 오류 처리 불필요. 오류 발생 시 0,0 값으로 사용
 		if top == 0 {
 			top = _top
 		}
@@ -105,7 +143,7 @@ func (h *AssetHandler) AddAsset(c *fiber.Ctx) error {
 		param.BuyPrice = bottom
 	}
 
-	id, err := h.w.SaveAssetInfo(m.Asset{
+	id, err := w.SaveAssetInfo(m.Asset{
 		Name:      param.Name,
 		Category:  category,
 		Code:      param.Code,
@@ -114,31 +152,32 @@ func (h *AssetHandler) AddAsset(c *fiber.Ctx) error {
 		Bottom:    bottom,
 		SellPrice: param.SellPrice,
 		BuyPrice:  param.BuyPrice,
+		Providers: providers,
 	})
 	if err != nil {
-		return fmt.Errorf("SaveAssetInfo 시 오류 발생. %w", err)
+		return 0, fmt.Errorf("SaveAssetInfo 시 오류 발생. %w", err)
 	}
 
 	var ema float64
 	var n uint
 
 	if param.Ema == 0 {
-		ema, n, _ = h.p.AvgPrice(category, param.Code)
+		ema, n, _ = p.AvgPrice(category, param.Code)
 	} else {
 		ema = param.Ema
 		n = param.Ndays
 	}
 
-	err = h.w.SaveEmaHist(&m.EmaHist{
+	err = w.SaveEmaHist(&m.EmaHist{
 		AssetID: id,
 		Ema:     ema,
 		NDays:   n,
 	})
 	if err != nil {
-		return fmt.Errorf("SaveEmaHist 시 오류 발생. %w", err)
+		return 0, fmt.Errorf("SaveEmaHist 시 오류 발생. %w", err)
 	}
 
-	return c.Status(fiber.StatusOK).SendString("자산 정보 저장 성공")
+	return id, nil
 }
 
 func (h *AssetHandler) UpdateAsset(c *fiber.Ctx) error {
@@ -146,20 +185,30 @@ func (h *AssetHandler) UpdateAsset(c *fiber.Ctx) error {
 	var param UpdateAssetReq
 	err := c.BodyParser(&param)
 	if err != nil {
-		return fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
 	}
 
 	err = validCheck(&param) // 포인터로 들어가도 validation 체크 되는지 확인
 	if err != nil {
-		return fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
 	}
 
 	category, err := m.ToCategory(param.Category)
 	if err != nil {
-		return fmt.Errorf("카테고리 변환 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("카테고리 변환 시 오류 발생. %w", err))
 	}
 
-	err = h.w.UpdateAssetInfo(m.Asset{
+	providers, err := providerChain(category, param.Providers)
+	if err != nil {
+		return apierr.BadRequest(err)
+	}
+
+	before, err := h.r.RetrieveAsset(param.ID)
+	if err != nil {
+		return fmt.Errorf("RetrieveAsset 시 오류 발생. %w", err)
+	}
+
+	after := m.Asset{
 		ID:        param.ID,
 		Name:      param.Name,
 		Category:  category,
@@ -169,10 +218,13 @@ func (h *AssetHandler) UpdateAsset(c *fiber.Ctx) error {
 		Bottom:    param.Bottom,
 		SellPrice: param.SellPrice,
 		BuyPrice:  param.BuyPrice,
-	})
+		Providers: providers,
+	}
+	err = h.w.UpdateAssetInfo(after)
 	if err != nil {
 		return fmt.Errorf("UpdateAssetInfo 시 오류 발생. %w", err)
 	}
+	addAudit(c, audit.Entry{Action: m.AuditUpdate, Entity: "asset", EntityID: param.ID, Before: before, After: after})
 
 	return c.Status(fiber.StatusOK).SendString("자산 정보 갱신 성공")
 }
@@ -182,18 +234,24 @@ func (h *AssetHandler) DeleteAsset(c *fiber.Ctx) error {
 	var param DeleteAssetReq
 	err := c.BodyParser(&param)
 	if err != nil {
-		return fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
 	}
 
 	err = validCheck(&param) // 포인터로 들어가도 validation 체크 되는지 확인
 	if err != nil {
-		return fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
+	}
+
+	before, err := h.r.RetrieveAsset(param.ID)
+	if err != nil {
+		return fmt.Errorf("RetrieveAsset 시 오류 발생. %w", err)
 	}
 
 	err = h.w.DeleteAssetInfo(param.ID)
 	if err != nil {
 		return fmt.Errorf("DeleteAssetInfo 시 오류 발생. %w", err)
 	}
+	addAudit(c, audit.Entry{Action: m.AuditDelete, Entity: "asset", EntityID: param.ID, Before: before})
 
 	return c.Status(fiber.StatusOK).SendString("자산 정보 삭제 성공")
 }
@@ -202,7 +260,7 @@ func (h *AssetHandler) Asset(c *fiber.Ctx) error {
 
 	id, err := c.ParamsInt("id")
 	if err != nil {
-		return fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
 	}
 
 	asset, err := h.r.RetrieveAsset(uint(id))
@@ -246,29 +304,56 @@ func (h *AssetHandler) AssetList(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(rtn)
 }
 
+// 자산별 투자 이력. query: fund_id, side, min_amount, max_amount, start, end, sort, 페이지
 func (h *AssetHandler) AssetHist(c *fiber.Ctx) error {
 	id, err := c.ParamsInt("id")
 	if err != nil {
-		return fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
 	}
 
-	hist, err := h.r.RetrieveAssetHist(uint(id))
+	q, err := investQuery(c)
 	if err != nil {
-		return fmt.Errorf("RetrieveAssetHist 오류 발생. %w", err)
+		return err
 	}
+	q.AssetID = uint(id)
 
-	resp := make([]HistResponse, len(hist))
-	for i, h := range hist {
-		resp[i] = HistResponse{
+	list, err := h.r.RetrieveInvests(q)
+	if err != nil {
+		return fmt.Errorf("RetrieveInvests 오류 발생. %w", err)
+	}
+
+	resp := make([]HistResponse, 0, len(list.Items))
+	for _, h := range list.Items {
+		resp = append(resp, HistResponse{
 			FundId:    h.FundID,
 			AssetId:   h.AssetID,
 			AssetName: h.Asset.Name,
 			Count:     h.Count,
 			Price:     h.Price,
 			CreatedAt: h.CreatedAt.Format("20060102"),
-		}
+		})
 	}
 
+	setPage(c, list.Total, list.NextCursor)
 	return c.Status(fiber.StatusOK).JSON(resp)
+}
 
+// providerChain 제공처 체인 표기 정규화. 제공처 이름은 validator에서, 카테고리 조회 가능 여부는 여기서 확인
+func providerChain(category m.Category, s string) (string, error) {
+	chain, _ := m.ParseProviders(s)
+	if err := category.CheckProviders(chain); err != nil {
+		return "", fmt.Errorf("제공처 체인 확인 시 오류 발생. %w", err)
+	}
+	return m.JoinProviders(chain), nil
+}
+
+func newQuoteResponse(q m.Quote) *quoteResponse {
+	if q.FetchedAt.IsZero() {
+		return nil
+	}
+	return &quoteResponse{
+		Source:    string(q.Source),
+		FetchedAt: formatTime(q.FetchedAt),
+		Age:       q.Age().Seconds(),
+	}
 }
@@ -21,13 +21,13 @@ import (
 func TestAssetHandler(t *testing.T) {
 
 	app := fiber.New()
-	middleware.SetupMiddleware(app, nil)
+	middleware.SetupMiddleware(app, nil, nil)
 
 	readerMock := AssetRetrieverMock{}
 	writerMock := &AssetInfoSaverMock{}
 	PriceGetterMock := PriceGetterMock{}
 
-	f := NewAssetHandler(readerMock, writerMock, PriceGetterMock)
+	f := NewAssetHandler(readerMock, writerMock, PriceGetterMock, PriceGetterMock)
 	f.InitRoute(app)
 	go func() {
 		app.Listen(":3000")
@@ -83,6 +83,23 @@ func TestAssetHandler(t *testing.T) {
 			}
 			t.Log(err)
 		})
+
+		t.Run("실패 테스트 - 카테고리를 조회할 수 없는 제공처", func(t *testing.T) {
+			param := AddAssetReq{
+				Name:      "종목",
+				Category:  model.DomesticStock.String(),
+				Code:      "code",
+				Currency:  "WON",
+				SellPrice: 480,
+				BuyPrice:  450,
+				Providers: "alpaca",
+			}
+			err := sendReqeust(app, "/assets/", "POST", param, nil)
+			if err == nil {
+				t.Error()
+			}
+			t.Log(err)
+		})
 	})
 
 	t.Run("종목 갱신 테스트", func(t *testing.T) {
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"investindicator/internal/audit"
+	m "investindicator/internal/model"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+)
+
+// AuditHandler 상태 변경 이력 기록, 조회. 인증 이후 경로에 기록 middleware 등록을 위해 AuthHandler 다음에 등록
+type AuditHandler struct {
+	au AuditRecorder
+}
+
+func NewAuditHandler(au AuditRecorder) *AuditHandler {
+	return &AuditHandler{
+		au: au,
+	}
+}
+
+func (h *AuditHandler) InitRoute(r fiber.Router) {
+	r.Use(h.record)
+	r.Get("/audit", h.Logs)
+}
+
+const (
+	auditKey     = "audit"
+	recordingKey = "audit_recording"
+)
+
+// methodActions handler가 변경 내용을 지정하지 않은 요청의 기본 동작
+var methodActions = map[string]m.AuditAction{
+	fiber.MethodPost:   m.AuditCreate,
+	fiber.MethodPut:    m.AuditUpdate,
+	fiber.MethodPatch:  m.AuditUpdate,
+	fiber.MethodDelete: m.AuditDelete,
+}
+
+/*
+record 성공한 쓰기 요청의 변경 이력 기록
+  - handler가 addAudit으로 지정한 변경 내용 기록. 미지정 시 경로, 메서드 기준으로 대상만 기록
+  - 실패한 요청은 상태 변경이 없으므로 미기록
+  - /v1, 기존 경로 group에 모두 등록되므로 요청당 한 번만 기록
+*/
+func (h *AuditHandler) record(c *fiber.Ctx) error {
+
+	action, ok := methodActions[c.Method()]
+	if !ok || c.Locals(recordingKey) != nil {
+		return c.Next()
+	}
+	c.Locals(recordingKey, true)
+	entity := pathSegment(c) // memo. c.Next() 이후에는 Route가 최종 경로로 바뀌므로 먼저 계산
+
+	err := c.Next()
+	if err != nil || c.Response().StatusCode() >= fiber.StatusBadRequest {
+		return err
+	}
+
+	entries, _ := c.Locals(auditKey).([]audit.Entry)
+	if len(entries) == 0 {
+		entries = []audit.Entry{{Action: action, Entity: entity, EntityID: c.Params("id"), After: auditBody(c)}}
+	}
+	h.au.Record(requestActor(c), entries...)
+
+	return nil
+}
+
+// Logs 상태 변경 이력. admin만 조회 가능. query: actor_type, actor_id, entity, entity_id, action, request_id, start, end, 페이지
+func (h *AuditHandler) Logs(c *fiber.Ctx) error {
+
+	if !isAdmin(c) {
+		return forbidden(c)
+	}
+
+	q := m.AuditQuery{
+		ActorType: m.ActorType(c.Query("actor_type")),
+		ActorID:   c.Query("actor_id"),
+		Entity:    c.Query("entity"),
+		EntityID:  c.Query("entity_id"),
+		Action:    m.AuditAction(c.Query("action")),
+		RequestID: c.Query("request_id"),
+	}
+	var err error
+	q.Start, err = dateParam(c, "start", false)
+	if err != nil {
+		return err
+	}
+	q.End, err = dateParam(c, "end", true)
+	if err != nil {
+		return err
+	}
+	q.Page, err = pageParam(c)
+	if err != nil {
+		return err
+	}
+
+	list, err := h.au.Logs(q)
+	if err != nil {
+		return fmt.Errorf("Logs 시 오류 발생. %w", err)
+	}
+
+	resp := make([]AuditLogResponse, 0, len(list.Items))
+	for _, l := range list.Items {
+		resp = append(resp, AuditLogResponse{
+			ID:        l.ID,
+			ActorType: string(l.ActorType),
+			ActorID:   l.ActorID,
+			Source:    string(l.Source),
+			Action:    string(l.Action),
+			Entity:    l.Entity,
+			EntityID:  l.EntityID,
+			Before:    rawJSON(l.Before),
+			After:     rawJSON(l.After),
+			RequestID: l.RequestID,
+			CreatedAt: l.CreatedAt,
+		})
+	}
+
+	setPage(c, list.Total, list.NextCursor)
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// addAudit 요청의 변경 대상과 변경 전후 값 지정. 요청 성공 시 AuditHandler가 기록
+func addAudit(c *fiber.Ctx, entries ...audit.Entry) {
+	prev, _ := c.Locals(auditKey).([]audit.Entry)
+	c.Locals(auditKey, append(prev, entries...))
+}
+
+/*
+requestActor 요청 주체
+  - 로그인 사용자, bot이 위임한 telegram 사용자는 user
+  - 사용자 위임 없는 API 키 요청은 apikey
+*/
+func requestActor(c *fiber.Ctx) m.Actor {
+
+	actor := m.Actor{Source: m.SourceApi}
+	if id, ok := c.Locals(requestid.ConfigDefault.ContextKey).(string); ok {
+		actor.RequestID = id
+	}
+
+	if claims, ok := c.Locals(claimsKey).(*Claims); ok {
+		actor.Type, actor.ID = m.ActorUser, strconv.Itoa(claims.UserID)
+		if _, ok := c.Locals(apiKeyKey).(*m.ApiKey); ok {
+			actor.Source = m.SourceBot
+		}
+	} else if key, ok := c.Locals(apiKeyKey).(*m.ApiKey); ok {
+		actor.Type, actor.ID = m.ActorApiKey, strconv.FormatUint(uint64(key.ID), 10)
+	}
+	return actor
+}
+
+// RequestKey 요청 주체 구분 값. ex) user:1, apikey:3. 인증 전이면 빈 값. 사용자별 요청 한도 key
+func RequestKey(c *fiber.Ctx) string {
+	return requestActor(c).Key()
+}
+
+// auditBody 기본 기록용 요청 body. JSON이 아니거나(파일 업로드 등) 비밀번호가 포함될 수 있는 경우 미기록
+func auditBody(c *fiber.Ctx) any {
+	body := c.Body()
+	if len(body) == 0 || !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) ||
+		strings.Contains(strings.ToLower(string(body)), "password") {
+		return nil
+	}
+	return json.RawMessage(body)
+}
+
+// rawJSON 저장된 JSON 문자열을 응답에 그대로 포함. JSON이 아니면 문자열로 응답
+func rawJSON(s string) json.RawMessage {
+	if s == "" {
+		return nil
+	}
+	if !json.Valid([]byte(s)) {
+		b, _ := json.Marshal(s)
+		return b
+	}
+	return json.RawMessage(s)
+}
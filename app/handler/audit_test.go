@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"investindicator/app/apierr"
+	m "investindicator/internal/model"
+	"investindicator/internal/session"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditHandler(t *testing.T) {
+
+	hash, _ := hashPassword("password1")
+	users := &UserRetrieverMock{
+		users: []m.User{
+			{ID: 1, Username: "admin", Password: hash, IsAdmin: true},
+			{ID: 2, Username: "viewer", Password: hash},
+		},
+	}
+	sessions := session.NewManager(nil)
+	recorder := &AuditRecorderMock{}
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			e := apierr.From(err)
+			return c.Status(e.Status()).SendString(e.Message)
+		},
+	})
+	app.Use(requestid.New())
+	for _, r := range []fiber.Router{app.Group("/v1"), app.Group("")} {
+		NewAuthHandler(users, UserManagerMock{users}, sessions, ApiKeyManagerMock{}, "authkey").InitRoute(r)
+		NewAuditHandler(recorder).InitRoute(r)
+		NewUserHandler(UserManagerMock{users}, sessions).InitRoute(r)
+	}
+
+	request := func(method, path, token, body string) (int, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("X-Request-ID", "req-"+method)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+	login := func(username, password string) string {
+		_, body := request("POST", "/login", "", fmt.Sprintf(`{"username":"%s","password":"%s"}`, username, password))
+		var tk JWTResponse
+		json.Unmarshal([]byte(body), &tk)
+		return tk.Token
+	}
+
+	admin, viewer := login("admin", "password1"), login("viewer", "password1")
+	assert.Empty(t, recorder.logs, "로그인은 미기록")
+
+	t.Run("Record", func(t *testing.T) {
+		code, _ := request("POST", "/v1/users", admin, `{"username":"new","password":"password1"}`)
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Len(t, recorder.logs, 1, "/v1, 기존 경로 middleware 중복 기록 없음")
+
+		log := recorder.logs[0]
+		assert.Equal(t, m.ActorUser, log.ActorType)
+		assert.Equal(t, "1", log.ActorID)
+		assert.Equal(t, m.SourceApi, log.Source)
+		assert.Equal(t, m.AuditCreate, log.Action)
+		assert.Equal(t, "user", log.Entity)
+		assert.Equal(t, "3", log.EntityID)
+		assert.Equal(t, "req-POST", log.RequestID)
+
+		code, _ = request("DELETE", "/users/3", admin, "")
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Len(t, recorder.logs, 2)
+		assert.Equal(t, m.AuditDelete, recorder.logs[1].Action)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		request("POST", "/users", viewer, `{"username":"new","password":"password1"}`)
+		request("DELETE", "/users/1", admin, "")
+		assert.Len(t, recorder.logs, 2, "실패한 요청은 미기록")
+	})
+
+	t.Run("Logs", func(t *testing.T) {
+		code, _ := request("GET", "/audit", viewer, "")
+		assert.Equal(t, fiber.StatusForbidden, code)
+
+		code, body := request("GET", "/audit?entity=user", admin, "")
+		assert.Equal(t, fiber.StatusOK, code)
+		var logs []AuditLogResponse
+		assert.NoError(t, json.Unmarshal([]byte(body), &logs))
+		assert.Len(t, logs, 2)
+	})
+}
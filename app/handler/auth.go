@@ -3,6 +3,11 @@ package handler
 import (
 	"errors"
 	"fmt"
+	"investindicator/app/apierr"
+	m "investindicator/internal/model"
+	"investindicator/internal/session"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,57 +18,209 @@ import (
 
 type AuthHandler struct {
 	us      UserRetrierver
+	um      UserManager
+	ss      SessionStore
+	ak      ApiKeyManager
 	authKey []byte
-	passKey string
 }
 
-func NewAuthHandler(us UserRetrierver, authKey string, passKey string) *AuthHandler {
+func NewAuthHandler(us UserRetrierver, um UserManager, ss SessionStore, ak ApiKeyManager, authKey string) *AuthHandler {
 	return &AuthHandler{
 		authKey: []byte(authKey),
-		passKey: passKey,
 		us:      us,
+		um:      um,
+		ss:      ss,
+		ak:      ak,
 	}
 }
 
-func (h *AuthHandler) InitRoute(app *fiber.App) {
-	router := app.Group("/login")
+func (h *AuthHandler) InitRoute(r fiber.Router) {
+	router := r.Group("/login")
 	router.Post("/", h.Login)
-	app.Use(h.AuthMiddleware) // todo. 위치 이동 시키자
+	router.Post("/refresh", h.Refresh)
+	// memo. 본인 계정 요청은 viewer도 가능해야 하므로 AuthMiddleware(쓰기는 admin, 자금 member만 허용) 이전에 등록
+	r.Post("/logout", h.authenticate, h.Logout)
+	r.Put("/users/me/password", h.authenticate, h.ChangePassword)
+	r.Use(h.AuthMiddleware)
 }
 
-// Claims represents the JWT claims
+// Claims represents the JWT claims. ID(jti)는 로그인 세션 id
 type Claims struct {
-	UserID  int    `json:"user_id"`
-	Email   string `json:"email"`
-	IsAdmin bool   `json:"is_admin"`
+	UserID  int                 `json:"user_id"`
+	Email   string              `json:"email"`
+	IsAdmin bool                `json:"is_admin"`
+	Funds   map[uint]m.FundRole `json:"-"` // 자금별 권한. 요청마다 조회, admin은 미사용
 	jwt.RegisteredClaims
 }
 
+// TelegramUserHeader bot이 API 키와 함께 전달하는 telegram user id. 해당 사용자의 권한으로 요청 처리
+const TelegramUserHeader = "X-Telegram-User"
+
+// ApiKeyScheme API 키 인증 Authorization header. ex) "ApiKey ik_xxxx.yyyy"
+const ApiKeyScheme = "ApiKey"
+
+const (
+	claimsKey = "claims"
+	apiKeyKey = "apikey"
+)
+
+// writeScopes API 키 쓰기 요청에 필요한 scope. 경로 첫 segment 기준, 미등록 경로는 API 키로 쓰기 불가
+var writeScopes = map[string]m.Scope{
+	"assets":    m.ScopeInvest,
+	"funds":     m.ScopeInvest,
+	"invest":    m.ScopeInvest,
+	"market":    m.ScopeInvest,
+	"imports":   m.ScopeInvest,
+	"events":    m.ScopeEvent,
+	"prompts":   m.ScopeEvent,
+	"blackhole": m.ScopeTrading,
+}
+
+// jwtOnly 사용자, API 키 관리, 변경 이력 조회, 서버 종료는 로그인한 admin만 가능. API 키로는 조회도 불가
+var jwtOnly = []string{"users", "apikeys", "audit", "shutdown"}
+
+// adminOnly 조회(GET) 요청이라도 admin만 가능한 경로
+var adminOnly = []string{"shutdown"}
+
+// memberWrites admin이 아니어도 쓰기 요청 가능한 경로. 자금별 권한(editor, owner)은 handler에서 확인
+var memberWrites = []string{"funds", "invest", "imports"}
+
+// accessTTL access token 유효 시간. 만료 후 refresh token으로 재발급
+const accessTTL = time.Hour
+
+const loginFailMsg = "사용자 혹은 비밀번호 불일치"
+
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
 
 	var req LoginRequest
 	err := c.BodyParser(&req)
 	if err != nil {
-		return err
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
+	}
+
+	if until, locked := h.ss.Locked(req.Username); locked {
+		return apierr.TooMany(fmt.Errorf("로그인 실패 횟수 초과. %s 이후 재시도", until.Format(time.DateTime)))
 	}
 
 	user, err := h.us.User(req.Username)
 	if err != nil {
-		return err
+		h.ss.Failed(req.Username)
+		return apierr.Unauthorized(errors.New(loginFailMsg))
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
+	if err != nil {
+		h.ss.Failed(req.Username)
+		return apierr.Unauthorized(errors.New(loginFailMsg))
+	}
+
+	if user.Disabled {
+		return apierr.Unauthorized(errors.New("비활성화된 사용자"))
+	}
+	h.ss.Reset(req.Username)
+
+	tk, err := h.ss.Create(user.ID)
+	if err != nil {
+		return fmt.Errorf("세션 생성 시 오류 발생. %w", err)
+	}
+	return h.token(c, user, tk)
+}
+
+// Refresh refresh token으로 access token 재발급. refresh token도 새로 발급되며 이전 값은 사용 불가
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+
+	var req RefreshRequest
+	err := c.BodyParser(&req)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
+	}
+
+	err = validCheck(&req)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
+	}
+
+	tk, err := h.ss.Refresh(req.RefreshToken)
+	if err != nil {
+		return apierr.Unauthorized(err)
+	}
+
+	// memo. 권한이 바뀌었을 수 있으므로 사용자 정보 다시 조회
+	user, err := h.um.UserById(tk.UserId)
+	if err != nil {
+		return fmt.Errorf("UserById 시 오류 발생. %w", err)
+	}
+	if user.Disabled {
+		h.ss.Revoke(tk.UserId, tk.Id)
+		return apierr.Unauthorized(errors.New("비활성화된 사용자"))
+	}
+
+	return h.token(c, user, tk)
+}
+
+// Logout 현재 세션 폐기. 해당 세션의 access token, refresh token 모두 사용 불가
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+
+	claims := c.Locals(claimsKey).(*Claims)
+	h.ss.Revoke(claims.UserID, claims.ID)
+
+	return c.Status(fiber.StatusOK).SendString("로그아웃 성공")
+}
+
+// ChangePassword 비밀번호 변경 후 모든 세션 폐기. 요청한 클라이언트에는 새 token 발급
+func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
+
+	claims := c.Locals(claimsKey).(*Claims)
+
+	var req ChangePasswordReq
+	err := c.BodyParser(&req)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
+	}
+
+	err = validCheck(&req)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
+	}
+
+	user, err := h.um.UserById(claims.UserID)
+	if err != nil {
+		return fmt.Errorf("UserById 시 오류 발생. %w", err)
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword))
+	if err != nil {
+		h.ss.Failed(user.Username)
+		return apierr.Unauthorized(errors.New("현재 비밀번호 불일치"))
+	}
+
+	hash, err := hashPassword(req.NewPassword)
 	if err != nil {
 		return err
 	}
+	err = h.um.UpdatePassword(user.ID, hash)
+	if err != nil {
+		return fmt.Errorf("UpdatePassword 시 오류 발생. %w", err)
+	}
+	h.ss.RevokeUser(user.ID)
+
+	tk, err := h.ss.Create(user.ID)
+	if err != nil {
+		return fmt.Errorf("세션 생성 시 오류 발생. %w", err)
+	}
+	return h.token(c, user, tk)
+}
 
-	// Create token expiration time (24 hours from now)
-	expirationTime := time.Now().Add(24 * time.Hour)
+// token 세션의 access token 발급
+func (h *AuthHandler) token(c *fiber.Ctx, user *m.User, tk *session.Token) error {
+
+	expirationTime := time.Now().Add(accessTTL)
 	claims := &Claims{
 		UserID:  user.ID,
 		Email:   user.Email,
 		IsAdmin: user.IsAdmin,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tk.Id,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Subject:   fmt.Sprintf("%d", user.ID),
@@ -77,23 +234,104 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	}
 
 	return c.Status(fiber.StatusOK).JSON(JWTResponse{
-		Token:  tokenString,
-		Expiry: expirationTime.Unix(),
+		Token:         tokenString,
+		Expiry:        expirationTime.Unix(),
+		RefreshToken:  tk.Refresh,
+		RefreshExpiry: tk.ExpiresAt.Unix(),
 	})
 }
 
 func (h *AuthHandler) AuthMiddleware(c *fiber.Ctx) error {
 
 	authHeader := c.Get("Authorization")
+	if token := c.Query("access_token"); authHeader == "" && token != "" && isStream(c) {
+		authHeader = "Bearer " + token // memo. 브라우저 EventSource는 header 지정 불가. 실시간 push 구독만 query로 token 허용
+	}
 	if authHeader == "" {
-		return errors.New("authorization header missing")
-	} else if authHeader == h.passKey { // bot 혹은 HTTP Req 테스트용 키
+		return apierr.Unauthorized(errors.New("authorization header missing"))
+	}
+	if raw, ok := strings.CutPrefix(authHeader, ApiKeyScheme+" "); ok { // bot, script용 키
+		return h.authorizeApiKey(c, raw)
+	}
+
+	claims, err := h.parseToken(authHeader)
+	if err != nil {
+		return err
+	}
+
+	return h.authorize(c, claims)
+}
+
+/*
+authorizeApiKey API 키 요청. 조회는 read, 쓰기는 경로별 scope 필요
+  - telegram user header가 있으면 telegram scope 필요. 이후 해당 사용자 권한으로 처리
+  - header가 없으면 scope 내에서 admin과 같이 처리
+*/
+func (h *AuthHandler) authorizeApiKey(c *fiber.Ctx, raw string) error {
+
+	key, err := h.ak.Verify(raw)
+	if err != nil {
+		return apierr.Unauthorized(err)
+	}
+
+	scope, ok := requiredScope(c)
+	if !ok {
+		return apierr.New(apierr.CodeForbidden, errors.New("API 키로 사용할 수 없는 경로"))
+	}
+	if !key.HasScope(scope) {
+		return apierr.New(apierr.CodeForbidden, fmt.Errorf("API 키 scope 부족. 필요 scope : %s", scope))
+	}
+	c.Locals(apiKeyKey, key)
+
+	tgUser := c.Get(TelegramUserHeader)
+	if tgUser == "" {
 		return c.Next()
 	}
+	if !key.HasScope(m.ScopeTelegram) {
+		return apierr.New(apierr.CodeForbidden, fmt.Errorf("API 키 scope 부족. 필요 scope : %s", m.ScopeTelegram))
+	}
+	return h.authorizeTelegramUser(c, tgUser)
+}
+
+// requiredScope 요청 경로에 필요한 API 키 scope. /v1 등 group prefix 제외 후 첫 segment 기준
+func requiredScope(c *fiber.Ctx) (m.Scope, bool) {
+
+	segment := pathSegment(c)
+	if slices.Contains(jwtOnly, segment) {
+		return "", false
+	}
+	if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+		return m.ScopeRead, true
+	}
+	scope, ok := writeScopes[segment]
+	return scope, ok
+}
+
+// pathSegment /v1 등 group prefix를 제외한 요청 경로의 첫 segment
+func pathSegment(c *fiber.Ctx) string {
+	path := strings.TrimPrefix(c.Path(), c.Route().Path) // memo. middleware 실행 중에는 Route가 등록된 group 경로
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return segment
+}
+
+// authenticate JWT 확인만 하는 middleware. 로그아웃, 비밀번호 변경 등 본인 계정 요청용
+func (h *AuthHandler) authenticate(c *fiber.Ctx) error {
+
+	claims, err := h.parseToken(c.Get("Authorization"))
+	if err != nil {
+		return err
+	}
+	c.Locals(claimsKey, claims)
+
+	return c.Next()
+}
+
+// parseToken Bearer token 검증. 로그아웃, 비밀번호 변경 등으로 세션이 폐기되었으면 만료 전이라도 거부
+func (h *AuthHandler) parseToken(authHeader string) (*Claims, error) {
 
 	tokenParts := strings.Split(authHeader, " ")
 	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-		return errors.New("invalid authorization format")
+		return nil, apierr.Unauthorized(errors.New("invalid authorization format"))
 	}
 
 	tokenString := tokenParts[1]
@@ -108,18 +346,120 @@ func (h *AuthHandler) AuthMiddleware(c *fiber.Ctx) error {
 		return h.authKey, nil
 	})
 	if err != nil {
-		return err
+		return nil, apierr.Unauthorized(err)
 	}
 
 	if !token.Valid {
-		return errors.New("invalid token")
+		return nil, apierr.Unauthorized(errors.New("invalid token"))
 	}
 
-	if c.Method() != "GET" && !claims.IsAdmin {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"message": "Forbidden",
-		})
+	if claims.ID == "" || !h.ss.Active(claims.UserID, claims.ID) {
+		return nil, apierr.Unauthorized(errors.New("로그아웃 혹은 폐기된 token"))
 	}
 
+	return claims, nil
+}
+
+// authorizeTelegramUser bot 요청. telegram user id에 연동된 사용자 권한으로 처리
+func (h *AuthHandler) authorizeTelegramUser(c *fiber.Ctx, tgUser string) error {
+
+	telegramId, err := strconv.ParseInt(tgUser, 10, 64)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("telegram user id 변환 시 오류 발생. %w", err))
+	}
+	user, err := h.us.UserByTelegramId(telegramId)
+	if err != nil || user.Disabled {
+		return apierr.Unauthorized(errors.New("Unauthorized"))
+	}
+
+	return h.authorize(c, &Claims{
+		UserID:  user.ID,
+		Email:   user.Email,
+		IsAdmin: user.IsAdmin,
+	})
+}
+
+/*
+authorize admin이 아니면 자금별 권한을 조회하여 handler에 전달
+  - 쓰기 요청은 admin만 허용. 자금 관련 경로(memberWrites)는 handler에서 자금별 권한 확인
+  - adminOnly 경로는 조회 요청도 admin만 허용
+*/
+func (h *AuthHandler) authorize(c *fiber.Ctx, claims *Claims) error {
+
+	segment := pathSegment(c)
+	if !claims.IsAdmin && (slices.Contains(adminOnly, segment) || (c.Method() != "GET" && !slices.Contains(memberWrites, segment))) {
+		return forbidden(c)
+	}
+
+	if !claims.IsAdmin {
+		funds, err := h.us.RetrieveFundRolesOfUser(claims.UserID)
+		if err != nil {
+			return fmt.Errorf("RetrieveFundRolesOfUser 시 오류 발생. %w", err)
+		}
+		claims.Funds = funds
+	}
+	c.Locals(claimsKey, claims)
+
 	return c.Next()
 }
+
+// fundRole 요청 사용자의 자금 권한. API 키 요청(claims 미존재)과 admin은 모든 자금의 owner로 취급
+func fundRole(c *fiber.Ctx, fundId uint) (m.FundRole, bool) {
+	claims, ok := c.Locals(claimsKey).(*Claims)
+	if !ok || claims.IsAdmin {
+		return m.FundOwner, true
+	}
+	role, ok := claims.Funds[fundId]
+	return role, ok
+}
+
+// canViewFund 요청 사용자의 자금 조회 권한
+func canViewFund(c *fiber.Ctx, fundId uint) bool {
+	_, ok := fundRole(c, fundId)
+	return ok
+}
+
+// canEditFund 요청 사용자의 투자 이력 기록 권한. editor 이상
+func canEditFund(c *fiber.Ctx, fundId uint) bool {
+	role, ok := fundRole(c, fundId)
+	return ok && role.CanEdit()
+}
+
+// canManageFund 요청 사용자의 자금 공유 관리 권한. owner만 가능
+func canManageFund(c *fiber.Ctx, fundId uint) bool {
+	role, ok := fundRole(c, fundId)
+	return ok && role.CanManage()
+}
+
+// visibleFunds 조회 가능한 자금 id. admin이면 nil
+func visibleFunds(c *fiber.Ctx) []uint {
+	claims, ok := c.Locals(claimsKey).(*Claims)
+	if !ok || claims.IsAdmin {
+		return nil
+	}
+	funds := make([]uint, 0, len(claims.Funds))
+	for id := range claims.Funds {
+		funds = append(funds, id)
+	}
+	slices.Sort(funds)
+	return funds
+}
+
+// isAdmin 요청 사용자 admin 여부. API 키 요청(claims 미존재)은 scope 내에서 admin으로 취급
+func isAdmin(c *fiber.Ctx) bool {
+	claims, ok := c.Locals(claimsKey).(*Claims)
+	return !ok || claims.IsAdmin
+}
+
+// hashPassword 저장용 bcrypt hash
+func hashPassword(password string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("비밀번호 hash 생성 시 오류 발생. %w", err)
+	}
+	return string(b), nil
+}
+
+func forbidden(c *fiber.Ctx) error {
+	return apierr.Forbidden()
+}
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	m "investindicator/internal/model"
+	"investindicator/internal/session"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthTelegramUser(t *testing.T) {
+
+	viewerId, adminId := int64(100), int64(200)
+	users := UserRetrieverMock{
+		users: []m.User{
+			{ID: 1, Username: "viewer", TelegramId: &viewerId},
+			{ID: 2, Username: "admin", TelegramId: &adminId, IsAdmin: true},
+		},
+		funds: map[int]map[uint]m.FundRole{1: {2: m.FundViewer}},
+	}
+
+	keys := ApiKeyManagerMock{keys: map[string]m.ApiKey{
+		"botkey": {ID: 1, Name: "telegram", Scopes: "read,invest,telegram"},
+	}}
+
+	app := fiber.New()
+	NewAuthHandler(users, UserManagerMock{&users}, session.NewManager(nil), keys, "authkey").InitRoute(app)
+	readerMock := &FundRetrieverMock{
+		isli: []m.InvestSummary{
+			{ID: 1, FundID: 1, Fund: m.Fund{Name: "공용자금"}, Sum: 10000},
+			{ID: 2, FundID: 2, Fund: m.Fund{Name: "가족자금"}, Sum: 20000},
+		},
+	}
+	NewFundHandler(readerMock, &FundWriterMock{}, &InvestRetrieverMock{}, &ExchageRateGetterMock{}, &InvestStatusIndicatorMock{}).InitRoute(app)
+
+	request := func(method, path string, tgUser string) (int, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{"name":"신규자금"}`))
+		req.Header.Set("Authorization", "ApiKey botkey")
+		req.Header.Set("Content-Type", "application/json")
+		if tgUser != "" {
+			req.Header.Set(TelegramUserHeader, tgUser)
+		}
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("Unknown User", func(t *testing.T) {
+		code, _ := request("GET", "/funds", "999")
+		assert.Equal(t, fiber.StatusUnauthorized, code)
+	})
+
+	t.Run("Viewer Fund Visibility", func(t *testing.T) {
+		code, body := request("GET", "/funds", "100")
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Contains(t, body, "가족자금")
+		assert.NotContains(t, body, "공용자금")
+
+		code, _ = request("GET", "/funds/1/portion", "100")
+		assert.Equal(t, fiber.StatusForbidden, code)
+	})
+
+	t.Run("Viewer Write", func(t *testing.T) {
+		code, _ := request("POST", "/funds", "100")
+		assert.Equal(t, fiber.StatusForbidden, code)
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		code, body := request("GET", "/funds", "200")
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Contains(t, body, "공용자금")
+	})
+}
+
+func TestAuthSession(t *testing.T) {
+
+	hash, _ := hashPassword("password1")
+	users := UserRetrieverMock{
+		users: []m.User{
+			{ID: 1, Username: "viewer", Password: hash},
+			{ID: 2, Username: "disabled", Password: hash, Disabled: true},
+		},
+	}
+	sessions := session.NewManager(nil, session.WithLockout(3, time.Hour))
+
+	app := fiber.New()
+	NewAuthHandler(users, UserManagerMock{&users}, sessions, ApiKeyManagerMock{}, "authkey").InitRoute(app)
+	app.Get("/ping", func(c *fiber.Ctx) error { return c.SendString("pong") })
+	app.Get("/shutdown", func(c *fiber.Ctx) error { return c.SendString("shutdown") })
+
+	request := func(method, path, token, body string) (int, JWTResponse) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		var jwt JWTResponse
+		json.NewDecoder(resp.Body).Decode(&jwt)
+		return resp.StatusCode, jwt
+	}
+	login := func(username, password string) (int, JWTResponse) {
+		return request("POST", "/login", "", fmt.Sprintf(`{"username":"%s","password":"%s"}`, username, password))
+	}
+
+	t.Run("Refresh", func(t *testing.T) {
+		code, tk := login("viewer", "password1")
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.NotEmpty(t, tk.RefreshToken)
+
+		code, next := request("POST", "/login/refresh", "", fmt.Sprintf(`{"refresh_token":"%s"}`, tk.RefreshToken))
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.NotEqual(t, tk.RefreshToken, next.RefreshToken)
+
+		code, _ = request("GET", "/ping", next.Token, "")
+		assert.Equal(t, fiber.StatusOK, code)
+		code, _ = request("GET", "/shutdown", next.Token, "")
+		assert.Equal(t, fiber.StatusForbidden, code, "서버 종료는 admin만 가능")
+
+		code, _ = request("POST", "/login/refresh", "", fmt.Sprintf(`{"refresh_token":"%s"}`, tk.RefreshToken))
+		assert.Equal(t, fiber.StatusUnauthorized, code, "사용한 refresh token 재사용")
+	})
+
+	t.Run("Logout", func(t *testing.T) {
+		_, tk := login("viewer", "password1")
+
+		code, _ := request("POST", "/logout", tk.Token, "")
+		assert.Equal(t, fiber.StatusOK, code, "viewer도 로그아웃 가능")
+
+		code, _ = request("GET", "/ping", tk.Token, "")
+		assert.Equal(t, fiber.StatusUnauthorized, code)
+		code, _ = request("POST", "/login/refresh", "", fmt.Sprintf(`{"refresh_token":"%s"}`, tk.RefreshToken))
+		assert.Equal(t, fiber.StatusUnauthorized, code)
+	})
+
+	t.Run("Change Password", func(t *testing.T) {
+		_, old := login("viewer", "password1")
+		_, other := login("viewer", "password1")
+
+		code, _ := request("PUT", "/users/me/password", old.Token, `{"current_password":"wrong","new_password":"password2"}`)
+		assert.Equal(t, fiber.StatusUnauthorized, code)
+		sessions.Reset("viewer")
+
+		code, _ = request("PUT", "/users/me/password", old.Token, `{"current_password":"password1","new_password":"short"}`)
+		assert.Equal(t, fiber.StatusBadRequest, code)
+
+		code, tk := request("PUT", "/users/me/password", old.Token, `{"current_password":"password1","new_password":"password2"}`)
+		assert.Equal(t, fiber.StatusOK, code)
+
+		code, _ = request("GET", "/ping", other.Token, "")
+		assert.Equal(t, fiber.StatusUnauthorized, code, "비밀번호 변경 시 다른 세션 폐기")
+		code, _ = request("GET", "/ping", tk.Token, "")
+		assert.Equal(t, fiber.StatusOK, code)
+
+		code, _ = login("viewer", "password1")
+		assert.Equal(t, fiber.StatusUnauthorized, code)
+		code, _ = login("viewer", "password2")
+		assert.Equal(t, fiber.StatusOK, code)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		code, _ := login("disabled", "password1")
+		assert.Equal(t, fiber.StatusUnauthorized, code)
+	})
+
+	t.Run("Lockout", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			code, _ := login("viewer", "wrong")
+			assert.Equal(t, fiber.StatusUnauthorized, code)
+		}
+		code, _ := login("viewer", "password2")
+		assert.Equal(t, fiber.StatusTooManyRequests, code, "lockout 중에는 올바른 비밀번호도 거부")
+
+		code, _ = login("unknown", "wrong")
+		assert.Equal(t, fiber.StatusUnauthorized, code)
+	})
+}
+
+func TestAuthApiKey(t *testing.T) {
+
+	viewerId := int64(100)
+	users := UserRetrieverMock{
+		users: []m.User{{ID: 1, Username: "viewer", TelegramId: &viewerId}},
+	}
+	keys := ApiKeyManagerMock{keys: map[string]m.ApiKey{
+		"read":    {ID: 1, Scopes: "read"},
+		"invest":  {ID: 2, Scopes: "read,invest"},
+		"revoked": {ID: 3, Scopes: "read,invest,event,trading,telegram"},
+	}}
+	keys.Revoke(3)
+
+	app := fiber.New()
+	ok := func(c *fiber.Ctx) error { return c.SendString("ok") }
+	for _, r := range []fiber.Router{app.Group("/v1"), app.Group("")} {
+		NewAuthHandler(users, UserManagerMock{&users}, session.NewManager(nil), keys, "authkey").InitRoute(r)
+		r.Get("/funds", ok)
+		r.Post("/invest", ok)
+		r.Post("/events/switch", ok)
+		r.Get("/users", ok)
+		r.Get("/shutdown", ok)
+	}
+
+	request := func(method, path, key, tgUser string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "ApiKey "+key)
+		if tgUser != "" {
+			req.Header.Set(TelegramUserHeader, tgUser)
+		}
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	for _, prefix := range []string{"/v1", ""} {
+		t.Run("Scope "+prefix, func(t *testing.T) {
+			assert.Equal(t, fiber.StatusOK, request("GET", prefix+"/funds", "read", ""))
+			assert.Equal(t, fiber.StatusForbidden, request("POST", prefix+"/invest", "read", ""))
+			assert.Equal(t, fiber.StatusOK, request("POST", prefix+"/invest", "invest", ""))
+			assert.Equal(t, fiber.StatusForbidden, request("POST", prefix+"/events/switch", "invest", ""))
+			assert.Equal(t, fiber.StatusForbidden, request("GET", prefix+"/users", "invest", ""), "사용자 관리는 API 키 사용 불가")
+			assert.Equal(t, fiber.StatusForbidden, request("GET", prefix+"/shutdown", "read", ""), "서버 종료는 API 키 사용 불가")
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusUnauthorized, request("GET", "/funds", "unknown", ""))
+		assert.Equal(t, fiber.StatusUnauthorized, request("GET", "/funds", "revoked", ""))
+	})
+
+	t.Run("Telegram", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusForbidden, request("GET", "/funds", "read", "100"), "telegram scope 없이 사용자 위임 불가")
+	})
+}
@@ -1,7 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"investindicator/app/apierr"
+	"investindicator/internal/audit"
+	m "investindicator/internal/model"
 	"math/big"
 	"time"
 
@@ -20,8 +24,8 @@ func NewBlackholeHandler(r BlackholeSnapshotRetriever, s BlackholeSwapExecutor)
 	}
 }
 
-func (h *BlackholeHandler) InitRoute(app *fiber.App) {
-	router := app.Group("/blackhole")
+func (h *BlackholeHandler) InitRoute(r fiber.Router) {
+	router := r.Group("/blackhole")
 	router.Get("/profit", h.Profit)
 	router.Post("/swap", h.Swap)
 }
@@ -29,12 +33,12 @@ func (h *BlackholeHandler) InitRoute(app *fiber.App) {
 func (h *BlackholeHandler) Profit(c *fiber.Ctx) error {
 	baseDateStr := c.Query("baseDate")
 	if baseDateStr == "" {
-		return fmt.Errorf("baseDate query parameter is required")
+		return apierr.BadRequest(errors.New("baseDate query parameter is required"))
 	}
 
 	baseDate, err := time.Parse("2006-01-02", baseDateStr)
 	if err != nil {
-		return fmt.Errorf("invalid baseDate format, expected YYYY-MM-DD: %w", err)
+		return apierr.BadRequest(fmt.Errorf("invalid baseDate format, expected YYYY-MM-DD: %w", err))
 	}
 
 	baseSnapshot, err := h.r.GetSnapshotByDate(baseDate)
@@ -100,12 +104,12 @@ func (h *BlackholeHandler) Swap(c *fiber.Ctx) error {
 	var param SwapAllRequest
 	err := c.BodyParser(&param)
 	if err != nil {
-		return fmt.Errorf("failed to parse request body: %w", err)
+		return apierr.BadRequest(fmt.Errorf("failed to parse request body: %w", err))
 	}
 
 	err = validCheck(&param)
 	if err != nil {
-		return fmt.Errorf("parameter validation failed: %w", err)
+		return apierr.BadRequest(fmt.Errorf("parameter validation failed: %w", err))
 	}
 
 	if h.s == nil {
@@ -114,8 +118,9 @@ func (h *BlackholeHandler) Swap(c *fiber.Ctx) error {
 
 	err = h.s.SwapAll(param.SwapAll)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).SendString(fmt.Sprintf("swap failed: %s", err.Error()))
+		return apierr.Upstream(fmt.Errorf("swap failed: %w", err))
 	}
+	addAudit(c, audit.Entry{Action: m.AuditRun, Entity: "blackhole", After: param})
 
 	return c.Status(fiber.StatusOK).SendString("swap completed successfully")
 }
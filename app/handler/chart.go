@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"investindicator/app/apierr"
+	"investindicator/internal/chart"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ChartHandler struct {
+	r ChartRenderer
+}
+
+func NewChartHandler(r ChartRenderer) *ChartHandler {
+	return &ChartHandler{
+		r: r,
+	}
+}
+
+func (h *ChartHandler) InitRoute(r fiber.Router) {
+	router := r.Group("/charts")
+	router.Get("/funds/:id", h.FundNav)
+	router.Get("/assets/:id", h.Asset)
+	router.Get("/indicators", h.Indicators)
+	router.Get("/premium/:id", h.Premium)
+}
+
+// 자금 평가 총액 추이. days 미입력 시 최근 90일
+func (h *ChartHandler) FundNav(c *fiber.Ctx) error {
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
+	}
+	if !canViewFund(c, uint(id)) {
+		return forbidden(c)
+	}
+
+	png, err := h.r.FundNav(uint(id), c.QueryInt("days", 0))
+	return sendPng(c, png, err)
+}
+
+// 자산 종가, EMA 추이와 매수/매도 가격 기준선
+func (h *ChartHandler) Asset(c *fiber.Ctx) error {
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
+	}
+
+	png, err := h.r.Asset(uint(id), c.QueryInt("days", 0))
+	return sendPng(c, png, err)
+}
+
+// 공포 탐욕 지수, 하이일드 스프레드 추이
+func (h *ChartHandler) Indicators(c *fiber.Ctx) error {
+
+	png, err := h.r.Indicators(c.QueryInt("days", 0))
+	return sendPng(c, png, err)
+}
+
+// 김치 프리미엄 추이
+func (h *ChartHandler) Premium(c *fiber.Ctx) error {
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
+	}
+
+	png, err := h.r.Premium(uint(id), c.QueryInt("days", 0))
+	return sendPng(c, png, err)
+}
+
+func sendPng(c *fiber.Ctx, png []byte, err error) error {
+	if errors.Is(err, chart.ErrNoData) {
+		return apierr.NotFound(err)
+	}
+	if err != nil {
+		return fmt.Errorf("차트 생성 시 오류 발생. %w", err)
+	}
+
+	c.Type("png")
+	return c.Status(fiber.StatusOK).Send(png)
+}
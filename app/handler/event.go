@@ -1,7 +1,16 @@
 package handler
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	investind "investindicator"
+	"investindicator/app/apierr"
+	"investindicator/internal/audit"
+	m "investindicator/internal/model"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -20,17 +29,49 @@ func NewEventHandler(er EventRetriever, el EventLauncher, ec EventStatusChanger)
 	}
 }
 
-func (h *EventHandler) InitRoute(app *fiber.App) {
+func (h *EventHandler) InitRoute(r fiber.Router) {
 
-	router := app.Group("/events")
+	router := r.Group("/events")
 	router.Get("/", h.Events)
 	router.Post("/switch", h.SwtichEvent)
 	router.Post("/launch", h.LaunchEvent)
 }
 
+// 이벤트 목록. query: active(true|false), sort(id|title, '-' prefix 내림차순), 페이지
 func (h *EventHandler) Events(c *fiber.Ctx) error {
 
-	events := h.er.Events()
+	sort, err := m.ParseSort(c.Query("sort"), []string{"id", "title"})
+	if err != nil {
+		return apierr.BadRequest(err)
+	}
+	page, err := pageParam(c)
+	if err != nil {
+		return err
+	}
+
+	events := make([]*investind.EnrolledEvent, 0)
+	for _, e := range h.er.Events() {
+		if active := c.Query("active"); active != "" && strconv.FormatBool(e.IsActive) != active {
+			continue
+		}
+		events = append(events, e)
+	}
+	slices.SortStableFunc(events, func(a, b *investind.EnrolledEvent) int {
+		n := cmp.Compare(a.Id, b.Id)
+		if sort.Field == "title" {
+			n = strings.Compare(a.Title, b.Title)
+		}
+		if sort.Desc {
+			return -n
+		}
+		return n
+	})
+
+	total := len(events)
+	events, next, err := pageSlice(events, page)
+	if err != nil {
+		return err
+	}
 
 	eventResponse := make([]EventResponse, 0, len(events))
 	for _, e := range events {
@@ -42,6 +83,7 @@ func (h *EventHandler) Events(c *fiber.Ctx) error {
 		})
 	}
 
+	setPage(c, int64(total), next)
 	return c.Status(fiber.StatusOK).JSON(eventResponse)
 }
 
@@ -50,18 +92,26 @@ func (h *EventHandler) SwtichEvent(c *fiber.Ctx) error {
 	param := EventStatusChangeRequest{}
 	err := c.BodyParser(&param)
 	if err != nil {
-		return fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
 	}
 
 	err = validCheck(&param)
 	if err != nil {
-		return fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
+	}
+
+	var before any
+	for _, ev := range h.er.Events() {
+		if ev.Id == param.Id {
+			before = fiber.Map{"active": ev.IsActive}
+		}
 	}
 
 	err = h.ec.SetEventStatus(param.Id, param.Active)
 	if err != nil {
-		return fmt.Errorf("상태 변경 요청 시 오류. %w", err)
+		return eventErr(fmt.Errorf("상태 변경 요청 시 오류. %w", err))
 	}
+	addAudit(c, audit.Entry{Action: m.AuditUpdate, Entity: "event", EntityID: param.Id, Before: before, After: fiber.Map{"active": param.Active}})
 
 	return c.Status(fiber.StatusOK).SendString("Event 실행 성공")
 }
@@ -71,19 +121,31 @@ func (h *EventHandler) LaunchEvent(c *fiber.Ctx) error {
 	var param EventLaunchRequest
 	err := c.BodyParser(&param)
 	if err != nil {
-		return fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
 	}
 
 	err = validCheck(&param)
 	if err != nil {
-		return fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
 	}
 
 	err = h.el.LaunchEvent(param.Id)
 	if err != nil {
-		return fmt.Errorf("event Launch 시 오류 발생. %s", err.Error())
+		return eventErr(fmt.Errorf("event Launch 시 오류 발생. %w", err))
 	}
+	addAudit(c, audit.Entry{Action: m.AuditRun, Entity: "event", EntityID: param.Id})
 
 	return c.Status(fiber.StatusOK).SendString("event 실행 성공")
 
 }
+
+// eventErr 미존재 이벤트는 404, 비활성 이벤트 실행은 409
+func eventErr(err error) error {
+	switch {
+	case errors.Is(err, investind.ErrEventNotFound):
+		return apierr.NotFound(err)
+	case errors.Is(err, investind.ErrEventInactive):
+		return apierr.Conflict(err)
+	}
+	return err
+}
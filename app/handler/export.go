@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"fmt"
+	"investindicator/app/apierr"
+	"investindicator/internal/export"
+	m "investindicator/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExportHandler struct {
+	e Exporter
+}
+
+func NewExportHandler(e Exporter) *ExportHandler {
+	return &ExportHandler{
+		e: e,
+	}
+}
+
+func (h *ExportHandler) InitRoute(r fiber.Router) {
+	router := r.Group("/exports")
+	router.Get("/ledger", h.Ledger)
+	router.Get("/holdings", h.Holdings)
+	router.Get("/funds/:id/nav", h.FundNavs)
+	router.Get("/indicators", h.Indicators)
+}
+
+// 투자 이력 전체. query: format(csv|json|xlsx), 투자 이력 조회 조건(fund_id, asset_id, category, side, min_amount, max_amount, start, end, sort)
+func (h *ExportHandler) Ledger(c *fiber.Ctx) error {
+
+	q, err := exportQuery(c)
+	if err != nil {
+		return err
+	}
+	if q.FundID != 0 && !canViewFund(c, q.FundID) {
+		return forbidden(c)
+	}
+
+	t, err := h.e.Ledger(q)
+	if err != nil {
+		return fmt.Errorf("Ledger 시 오류 발생. %w", err)
+	}
+	return sendTable(c, t)
+}
+
+// 현재 보유 자산의 투자 원금, 평가 금액. query: format, fund_id, asset_id
+func (h *ExportHandler) Holdings(c *fiber.Ctx) error {
+
+	q, err := exportQuery(c)
+	if err != nil {
+		return err
+	}
+	if q.FundID != 0 && !canViewFund(c, q.FundID) {
+		return forbidden(c)
+	}
+
+	t, err := h.e.Holdings(q)
+	if err != nil {
+		return fmt.Errorf("Holdings 시 오류 발생. %w", err)
+	}
+	return sendTable(c, t)
+}
+
+// 자금 평가 총액 추이. days 미입력 시 최근 365일
+func (h *ExportHandler) FundNavs(c *fiber.Ctx) error {
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
+	}
+	if !canViewFund(c, uint(id)) {
+		return forbidden(c)
+	}
+
+	t, err := h.e.FundNavs(uint(id), c.QueryInt("days", 0))
+	if err != nil {
+		return fmt.Errorf("FundNavs 시 오류 발생. %w", err)
+	}
+	return sendTable(c, t)
+}
+
+// 공포 탐욕 지수, 하이일드 스프레드 등 일자별 지표. days 미입력 시 최근 365일
+func (h *ExportHandler) Indicators(c *fiber.Ctx) error {
+
+	t, err := h.e.Indicators(c.QueryInt("days", 0))
+	if err != nil {
+		return fmt.Errorf("Indicators 시 오류 발생. %w", err)
+	}
+	return sendTable(c, t)
+}
+
+// exportQuery 목록 조회와 같은 조건. 내보내기는 전체 행 대상이라 페이지 미적용
+func exportQuery(c *fiber.Ctx) (m.InvestQuery, error) {
+	q, err := investQuery(c)
+	q.Page = m.Page{}
+	return q, err
+}
+
+func sendTable(c *fiber.Ctx, t *export.Table) error {
+	format, err := export.ParseFormat(c.Query("format"))
+	if err != nil {
+		return err
+	}
+
+	b, err := export.Encode(t, format)
+	if err != nil {
+		return fmt.Errorf("Encode 시 오류 발생. %w", err)
+	}
+
+	c.Attachment(t.FileName(format))
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	return c.Status(fiber.StatusOK).Send(b)
+}
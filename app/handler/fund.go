@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"investindicator/app/apierr"
+	"investindicator/internal/audit"
 	"investindicator/internal/model"
 
 	"github.com/gofiber/fiber/v2"
@@ -25,8 +28,8 @@ func NewFundHandler(r FundRetriever, w FundWriter, i InvestRetriever, e ExchageR
 	}
 }
 
-func (h *FundHandler) InitRoute(app *fiber.App) {
-	router := app.Group("/funds")
+func (h *FundHandler) InitRoute(r fiber.Router) {
+	router := r.Group("/funds")
 
 	router.Get("/", h.TotalStatus)
 	router.Post("/", h.AddFund)
@@ -34,6 +37,9 @@ func (h *FundHandler) InitRoute(app *fiber.App) {
 	router.Get("/:id/assets", h.FundAssets)
 	router.Get("/:id/portion", h.FundPortion)
 	router.Get("/:id/available_amounts", h.AvailableAmounts)
+	router.Get("/:id/members", h.Members)
+	router.Put("/:id/members", h.SaveMember)
+	router.Delete("/:id/members/:userId<\\d+>", h.DeleteMember)
 }
 
 // 총 자금 금액
@@ -48,11 +54,16 @@ func (h *FundHandler) TotalStatus(c *fiber.Ctx) error {
 
 	funds := make(map[uint]*TotalStatusResp)
 	for _, is := range investSummarys {
+		if !canViewFund(c, is.FundID) {
+			continue
+		}
 
 		if funds[is.FundID] == nil {
+			role, _ := fundRole(c, is.FundID)
 			funds[is.FundID] = &TotalStatusResp{
 				ID:   is.FundID,
 				Name: is.Fund.Name,
+				Role: string(role),
 			}
 		}
 
@@ -66,33 +77,192 @@ func (h *FundHandler) TotalStatus(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(funds)
 }
 
+// 자금 추가. admin만 가능. owner_id 미입력 시 요청 사용자가 owner
 func (h *FundHandler) AddFund(c *fiber.Ctx) error {
 
+	if !isAdmin(c) {
+		return forbidden(c)
+	}
+
 	var param AddFundReq
 	err := c.BodyParser(&param)
 	if err != nil {
-		return fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
 	}
 
 	err = validCheck(param) // 포인터로 들어가도 validation 체크 되는지 확인
 	if err != nil {
-		return fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
 	}
 
-	err = h.w.SaveFund(param.Name)
+	id, err := h.w.SaveFund(param.Name)
 	if err != nil {
 		return fmt.Errorf("SaveFund 시 오류 발생. %w", err)
 	}
 
+	addAudit(c, audit.Entry{Action: model.AuditCreate, Entity: "fund", EntityID: id, After: param})
+
+	owner := param.OwnerId
+	if claims, ok := c.Locals(claimsKey).(*Claims); ok && owner == 0 {
+		owner = claims.UserID
+	}
+	if owner != 0 {
+		err = h.w.SaveFundMember(id, owner, model.FundOwner)
+		if err != nil {
+			return fmt.Errorf("SaveFundMember 시 오류 발생. %w", err)
+		}
+		addAudit(c, fundMemberAudit(id, owner, nil, model.FundOwner))
+	}
+
 	return c.Status(fiber.StatusOK).SendString("자금 정보 저장 성공")
 }
 
+// 자금 공유 사용자 목록. 자금 조회 권한이 있으면 조회 가능
+func (h *FundHandler) Members(c *fiber.Ctx) error {
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
+	}
+	if !canViewFund(c, uint(id)) {
+		return forbidden(c)
+	}
+
+	members, err := h.w.FundMembers(uint(id))
+	if err != nil {
+		return fmt.Errorf("FundMembers 시 오류 발생. %w", err)
+	}
+
+	resp := make([]FundMemberResponse, len(members))
+	for i, fm := range members {
+		resp[i] = FundMemberResponse{
+			UserId:   fm.UserID,
+			Username: fm.User.Username,
+			Role:     string(fm.Role),
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// 자금 공유 사용자 추가, 권한 변경. owner만 가능
+func (h *FundHandler) SaveMember(c *fiber.Ctx) error {
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
+	}
+	if !canManageFund(c, uint(id)) {
+		return forbidden(c)
+	}
+
+	var param FundMemberReq
+	err = c.BodyParser(&param)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
+	}
+
+	err = validCheck(&param)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
+	}
+
+	role := model.FundRole(param.Role)
+	prev, err := h.keepOwner(uint(id), param.UserId, role != model.FundOwner)
+	if err != nil {
+		return err
+	}
+
+	err = h.w.SaveFundMember(uint(id), param.UserId, role)
+	if err != nil {
+		return fmt.Errorf("SaveFundMember 시 오류 발생. %w", err)
+	}
+	addAudit(c, fundMemberAudit(uint(id), param.UserId, prev, role))
+
+	return c.Status(fiber.StatusOK).SendString("자금 공유 저장 성공")
+}
+
+// 자금 공유 해제. owner 혹은 본인만 가능
+func (h *FundHandler) DeleteMember(c *fiber.Ctx) error {
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
+	}
+	userId, err := c.ParamsInt("userId")
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 userId 조회 시 오류 발생. %w", err))
+	}
+	if !canManageFund(c, uint(id)) && !isSelf(c, userId) {
+		return forbidden(c)
+	}
+
+	prev, err := h.keepOwner(uint(id), userId, true)
+	if err != nil {
+		return err
+	}
+
+	err = h.w.DeleteFundMember(uint(id), userId)
+	if err != nil {
+		return fmt.Errorf("DeleteFundMember 시 오류 발생. %w", err)
+	}
+	entry := fundMemberAudit(uint(id), userId, prev, "")
+	entry.Action = model.AuditDelete
+	addAudit(c, entry)
+
+	return c.Status(fiber.StatusOK).SendString("자금 공유 해제 성공")
+}
+
+/*
+keepOwner userId의 현재 권한 조회. 미등록이면 nil
+  - release: owner 권한 해제 여부. 공유 관리가 불가능한 자금이 되지 않도록 마지막 owner 해제 거부
+*/
+func (h *FundHandler) keepOwner(fundId uint, userId int, release bool) (*model.FundRole, error) {
+
+	members, err := h.w.FundMembers(fundId)
+	if err != nil {
+		return nil, fmt.Errorf("FundMembers 시 오류 발생. %w", err)
+	}
+
+	var prev *model.FundRole
+	owners := 0
+	for _, fm := range members {
+		if fm.UserID == userId {
+			prev = &fm.Role
+		}
+		if fm.Role == model.FundOwner {
+			owners++
+		}
+	}
+	if release && prev != nil && *prev == model.FundOwner && owners == 1 {
+		return nil, apierr.BadRequest(errors.New("자금의 마지막 owner는 해제 불가"))
+	}
+	return prev, nil
+}
+
+// fundMemberAudit 자금 공유 변경 이력. 대상 id는 "{자금 id}:{사용자 id}"
+func fundMemberAudit(fundId uint, userId int, prev *model.FundRole, role model.FundRole) audit.Entry {
+	e := audit.Entry{Action: model.AuditUpdate, Entity: "fund_member", EntityID: fmt.Sprintf("%d:%d", fundId, userId)}
+	if prev == nil {
+		e.Action = model.AuditCreate
+	} else {
+		e.Before = fiber.Map{"role": *prev}
+	}
+	if role != "" {
+		e.After = fiber.Map{"role": role}
+	}
+	return e
+}
+
 // 자금별 보유 자산
 func (h *FundHandler) FundAssets(c *fiber.Ctx) error {
 
 	id, err := c.ParamsInt("id")
 	if err != nil {
-		return fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
+	}
+	if !canViewFund(c, uint(id)) {
+		return forbidden(c)
 	}
 
 	invests, err := h.r.RetreiveFundSummaryByFundId(uint(id))
@@ -129,30 +299,30 @@ func (h *FundHandler) FundAssets(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(resp)
 }
 
-// 자금별 투자 이력
+// 자금별 투자 이력. query: asset_id, category, side, min_amount, max_amount, start, end, sort, 페이지
 func (h *FundHandler) FundHist(c *fiber.Ctx) error {
 
 	id, err := c.ParamsInt("id")
 	if err != nil {
-		return fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
+	}
+	if !canViewFund(c, uint(id)) {
+		return forbidden(c)
 	}
 
-	start := c.Query("start")
-	end := c.Query("end")
-
-	var invests []model.Invest
-
-	if start == "" || end == "" {
-		invests, err = h.r.RetreiveFundInvestsById(uint(id))
-	} else {
-		invests, err = h.r.RetrieveFundInvestsByIdAndRange(uint(id), start, end)
+	q, err := investQuery(c)
+	if err != nil {
+		return err
 	}
+	q.FundID = uint(id)
+
+	list, err := h.r.RetrieveInvests(q)
 	if err != nil {
-		return fmt.Errorf("RetreiveAFundInvestsById 시 오류 발생. %w", err)
+		return fmt.Errorf("RetrieveInvests 시 오류 발생. %w", err)
 	}
 
-	fundHists := make([]HistResponse, len(invests))
-	for i, iv := range invests {
+	fundHists := make([]HistResponse, len(list.Items))
+	for i, iv := range list.Items {
 		fundHists[i] = HistResponse{
 			FundId:    iv.FundID,
 			AssetId:   iv.AssetID,
@@ -163,13 +333,17 @@ func (h *FundHandler) FundHist(c *fiber.Ctx) error {
 		}
 	}
 
+	setPage(c, list.Total, list.NextCursor)
 	return c.Status(fiber.StatusOK).JSON(fundHists)
 }
 
 func (h *FundHandler) FundPortion(c *fiber.Ctx) error {
 	id, err := c.ParamsInt("id")
 	if err != nil {
-		return fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
+	}
+	if !canViewFund(c, uint(id)) {
+		return forbidden(c)
 	}
 
 	funds, err := h.r.RetreiveFundSummaryByFundId(uint(id))
@@ -214,7 +388,10 @@ func (h *FundHandler) AvailableAmounts(c *fiber.Ctx) error {
 
 	id, err := c.ParamsInt("id")
 	if err != nil {
-		return fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
+	}
+	if !canViewFund(c, uint(id)) {
+		return forbidden(c)
 	}
 
 	availableAmount, err := h.is.InvestAvailableAmount(id)
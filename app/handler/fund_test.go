@@ -1,9 +1,15 @@
 package handler
 
 import (
+	"encoding/json"
 	"fmt"
+	"investindicator/app/apierr"
 	"investindicator/app/middleware"
 	m "investindicator/internal/model"
+	"investindicator/internal/session"
+	"io"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,7 +21,7 @@ import (
 func TestFundHandler(t *testing.T) {
 
 	app := fiber.New()
-	middleware.SetupMiddleware(app, nil)
+	middleware.SetupMiddleware(app, nil, nil)
 
 	readerMock := &FundRetrieverMock{}
 	writerMock := &FundWriterMock{}
@@ -229,3 +235,103 @@ func TestFundHandler(t *testing.T) {
 
 	app.Shutdown()
 }
+
+func TestFundAccess(t *testing.T) {
+
+	hash, _ := hashPassword("password1")
+	users := &UserRetrieverMock{
+		users: []m.User{
+			{ID: 1, Username: "admin", Password: hash, IsAdmin: true},
+			{ID: 2, Username: "owner", Password: hash},
+			{ID: 3, Username: "editor", Password: hash},
+			{ID: 4, Username: "viewer", Password: hash},
+		},
+		funds: map[int]map[uint]m.FundRole{
+			2: {1: m.FundOwner},
+			3: {1: m.FundEditor},
+			4: {1: m.FundViewer, 2: m.FundViewer},
+		},
+	}
+	writerMock := &FundWriterMock{members: []m.FundMember{
+		{FundID: 1, UserID: 2, Role: m.FundOwner},
+		{FundID: 2, UserID: 4, Role: m.FundViewer},
+	}}
+	readerMock := &FundRetrieverMock{
+		isli: []m.InvestSummary{
+			{ID: 1, FundID: 1, Fund: m.Fund{Name: "공용자금"}, Sum: 10000},
+			{ID: 2, FundID: 2, Fund: m.Fund{Name: "가족자금"}, Sum: 20000},
+		},
+	}
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			e := apierr.From(err)
+			return c.Status(e.Status()).SendString(e.Message)
+		},
+	})
+	NewAuthHandler(users, UserManagerMock{users}, session.NewManager(nil), ApiKeyManagerMock{}, "authkey").InitRoute(app)
+	NewFundHandler(readerMock, writerMock, &InvestRetrieverMock{}, &ExchageRateGetterMock{}, &InvestStatusIndicatorMock{}).InitRoute(app)
+	NewInvestHandler(NewADefaultssetRetrieverMock(), NewInvestSaverMock(0), NewExchageRateGetterMock(1400)).InitRoute(app)
+
+	request := func(method, path, token, body string) (int, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+	login := func(username string) string {
+		_, body := request("POST", "/login", "", fmt.Sprintf(`{"username":"%s","password":"password1"}`, username))
+		var tk JWTResponse
+		json.Unmarshal([]byte(body), &tk)
+		return tk.Token
+	}
+	owner, editor, viewer := login("owner"), login("editor"), login("viewer")
+
+	t.Run("Visibility", func(t *testing.T) {
+		code, body := request("GET", "/funds", editor, "")
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Contains(t, body, `"role":"editor"`)
+		assert.NotContains(t, body, "가족자금")
+	})
+
+	t.Run("Invest", func(t *testing.T) {
+		invest := `{"fund_id":%d,"asset_id":3,"price":10000,"count":3}`
+		code, _ := request("POST", "/invest", editor, fmt.Sprintf(invest, 1))
+		assert.Equal(t, fiber.StatusOK, code)
+		code, _ = request("POST", "/invest", viewer, fmt.Sprintf(invest, 1))
+		assert.Equal(t, fiber.StatusForbidden, code, "viewer는 기록 불가")
+		code, _ = request("POST", "/invest", editor, fmt.Sprintf(invest, 2))
+		assert.Equal(t, fiber.StatusForbidden, code, "권한 없는 자금")
+	})
+
+	t.Run("Add Fund", func(t *testing.T) {
+		code, _ := request("POST", "/funds", owner, `{"name":"신규자금"}`)
+		assert.Equal(t, fiber.StatusForbidden, code, "자금 추가는 admin만 가능")
+	})
+
+	t.Run("Members", func(t *testing.T) {
+		code, body := request("GET", "/funds/1/members", viewer, "")
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Contains(t, body, `"role":"owner"`)
+
+		code, _ = request("PUT", "/funds/1/members", editor, `{"user_id":4,"role":"editor"}`)
+		assert.Equal(t, fiber.StatusForbidden, code, "owner만 공유 관리")
+		code, _ = request("PUT", "/funds/1/members", owner, `{"user_id":4,"role":"admin"}`)
+		assert.Equal(t, fiber.StatusBadRequest, code)
+		code, _ = request("PUT", "/funds/1/members", owner, `{"user_id":4,"role":"editor"}`)
+		assert.Equal(t, fiber.StatusOK, code)
+
+		code, _ = request("PUT", "/funds/1/members", owner, `{"user_id":2,"role":"viewer"}`)
+		assert.Equal(t, fiber.StatusBadRequest, code, "마지막 owner 해제 불가")
+		code, _ = request("DELETE", "/funds/1/members/2", owner, "")
+		assert.Equal(t, fiber.StatusBadRequest, code, "마지막 owner 해제 불가")
+
+		code, _ = request("DELETE", "/funds/2/members/4", viewer, "")
+		assert.Equal(t, fiber.StatusOK, code, "본인 공유 해제 가능")
+		code, _ = request("DELETE", "/funds/1/members/2", viewer, "")
+		assert.Equal(t, fiber.StatusForbidden, code)
+	})
+}
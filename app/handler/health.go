@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	m "investindicator/internal/model"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// healthTimeout 구성 요소 상태 확인 제한 시간. 확인 요청(probe) timeout보다 짧게 유지
+const healthTimeout = 3 * time.Second
+
+// HealthHandler 구성 요소별 상태 확인. 인증 없이 조회 가능하도록 인증 middleware 이전에 등록
+type HealthHandler struct {
+	reporters []HealthReporter
+	timeout   time.Duration
+	mu        sync.Mutex
+	last      [][]m.ComponentHealth // reporter별 직전 응답. 제한 시간 초과 시 구성 요소 이름, 필수 여부 확인용
+}
+
+func NewHealthHandler(reporters ...HealthReporter) *HealthHandler {
+	return &HealthHandler{
+		reporters: reporters,
+		timeout:   healthTimeout,
+		last:      make([][]m.ComponentHealth, len(reporters)),
+	}
+}
+
+func (h *HealthHandler) InitRoute(r fiber.Router) {
+	r.Get("/healthz", h.Healthz)
+	r.Get("/readyz", h.Readyz)
+}
+
+// Healthz 필수 구성 요소(MySQL, Redis) 장애 시 503. 그 외 장애는 degraded로 보고하고 200
+func (h *HealthHandler) Healthz(c *fiber.Ctx) error {
+
+	resp := h.check(c.UserContext())
+	if resp.Status == string(m.HealthDown) {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(resp)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// Readyz 모든 구성 요소가 정상일 때만 200. degraded도 503
+func (h *HealthHandler) Readyz(c *fiber.Ctx) error {
+
+	resp := h.check(c.UserContext())
+	if resp.Status != string(m.HealthUp) {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(resp)
+	}
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+/*
+check 구성 요소별 상태를 동시에 확인. 응답 순서는 reporters 등록 순서 유지
+  - 제한 시간 내 응답하지 않은 reporter는 기다리지 않고 down으로 보고
+*/
+func (h *HealthHandler) check(ctx context.Context) HealthResponse {
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	type report struct {
+		i          int
+		components []m.ComponentHealth
+	}
+	done := make(chan report, len(h.reporters)) // memo. 제한 시간 이후 응답한 goroutine도 막히지 않고 종료
+	for i, r := range h.reporters {
+		go func() {
+			done <- report{i, r.Health(ctx)}
+		}()
+	}
+
+	reports := make([][]m.ComponentHealth, len(h.reporters))
+	received := make([]bool, len(h.reporters))
+wait:
+	for range h.reporters {
+		select {
+		case r := <-done:
+			reports[r.i], received[r.i] = r.components, true
+		case <-ctx.Done():
+			break wait
+		}
+	}
+
+	h.mu.Lock()
+	for i := range reports {
+		if received[i] {
+			h.last[i] = reports[i]
+		} else {
+			reports[i] = timedOut(i, h.last[i], ctx.Err())
+		}
+	}
+	h.mu.Unlock()
+
+	components := make([]m.ComponentHealth, 0)
+	for _, r := range reports {
+		components = append(components, r...)
+	}
+
+	resp := HealthResponse{
+		Status:     string(m.OverallHealth(components)),
+		Components: make([]componentHealthResponse, len(components)),
+	}
+	for i, c := range components {
+		resp.Components[i] = componentHealthResponse{
+			Name:        c.Name,
+			Status:      string(c.Status),
+			Required:    c.Required,
+			Error:       c.Error,
+			Detail:      c.Detail,
+			LastSuccess: formatTime(c.LastSuccess),
+			LastRun:     formatTime(c.LastRun),
+		}
+	}
+	return resp
+}
+
+// timedOut 제한 시간 내 응답하지 않은 reporter의 구성 요소. 직전 응답이 없으면 필수 구성 요소로 간주
+func timedOut(i int, last []m.ComponentHealth, err error) []m.ComponentHealth {
+
+	err = fmt.Errorf("상태 확인 시간 초과. %w", err)
+	if len(last) == 0 {
+		return []m.ComponentHealth{m.FailedComponent(fmt.Sprintf("reporter:%d", i), true, err)}
+	}
+
+	rtn := make([]m.ComponentHealth, len(last))
+	for j, c := range last {
+		c.Status, c.Error = m.HealthDown, err.Error()
+		rtn[j] = c
+	}
+	return rtn
+}
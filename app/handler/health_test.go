@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	m "investindicator/internal/model"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthHandler(t *testing.T) {
+
+	storage := &HealthReporterMock{components: []m.ComponentHealth{
+		m.HealthyComponent("mysql", true),
+		m.HealthyComponent("redis", true),
+	}}
+	telegram := &HealthReporterMock{components: []m.ComponentHealth{
+		m.HealthyComponent("telegram:bot", false),
+	}}
+
+	app := fiber.New()
+	NewHealthHandler(storage, telegram).InitRoute(app)
+
+	request := func(path string) (int, HealthResponse) {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		assert.NoError(t, err)
+		b, _ := io.ReadAll(resp.Body)
+		var h HealthResponse
+		assert.NoError(t, json.Unmarshal(b, &h))
+		return resp.StatusCode, h
+	}
+
+	t.Run("Up", func(t *testing.T) {
+		code, h := request("/readyz")
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Equal(t, "up", h.Status)
+		assert.Len(t, h.Components, 3)
+		assert.Equal(t, "mysql", h.Components[0].Name, "등록 순서 유지")
+		assert.NotEmpty(t, h.Components[0].LastSuccess)
+	})
+
+	t.Run("Degraded", func(t *testing.T) {
+		telegram.components = []m.ComponentHealth{m.FailedComponent("telegram:bot", false, errors.New("timeout"))}
+
+		code, h := request("/healthz")
+		assert.Equal(t, fiber.StatusOK, code, "필수가 아닌 구성 요소 장애")
+		assert.Equal(t, "degraded", h.Status)
+		assert.Equal(t, "timeout", h.Components[2].Error)
+
+		code, _ = request("/readyz")
+		assert.Equal(t, fiber.StatusServiceUnavailable, code)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		slow := &HealthReporterMock{components: storage.components}
+		h := NewHealthHandler(slow, telegram)
+		h.timeout = 50 * time.Millisecond
+
+		resp := h.check(t.Context())
+		assert.Equal(t, "mysql", resp.Components[0].Name)
+		assert.Equal(t, "up", resp.Components[0].Status)
+
+		slow.delay = time.Second
+		start := time.Now()
+		resp = h.check(t.Context())
+		assert.Less(t, time.Since(start), time.Second, "지연된 reporter 미대기")
+		assert.Equal(t, "down", resp.Status)
+		if assert.Len(t, resp.Components, 3) {
+			assert.Equal(t, "mysql", resp.Components[0].Name, "직전 응답의 구성 요소 이름 유지")
+			assert.Equal(t, "down", resp.Components[0].Status)
+			assert.True(t, resp.Components[0].Required)
+			assert.Contains(t, resp.Components[0].Error, "시간 초과")
+		}
+
+		h = NewHealthHandler(slow)
+		h.timeout = 50 * time.Millisecond
+		resp = h.check(t.Context())
+		if assert.Len(t, resp.Components, 1) {
+			assert.Equal(t, "reporter:0", resp.Components[0].Name)
+			assert.True(t, resp.Components[0].Required, "직전 응답이 없으면 필수로 간주")
+		}
+	})
+
+	t.Run("Down", func(t *testing.T) {
+		storage.components[1] = m.FailedComponent("redis", true, errors.New("connection refused"))
+
+		code, h := request("/healthz")
+		assert.Equal(t, fiber.StatusServiceUnavailable, code)
+		assert.Equal(t, "down", h.Status)
+	})
+}
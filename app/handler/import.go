@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"investindicator/app/apierr"
+	"investindicator/internal/audit"
+	"investindicator/internal/importer"
+	m "investindicator/internal/model"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ImportHandler struct {
+	im InvestImporter
+}
+
+func NewImportHandler(im InvestImporter) *ImportHandler {
+	return &ImportHandler{
+		im: im,
+	}
+}
+
+func (h *ImportHandler) InitRoute(r fiber.Router) {
+	router := r.Group("/imports")
+	router.Post("/", h.Preview)
+	router.Get("/:id", h.Batch)
+	router.Post("/:id/commit", h.Commit)
+	router.Delete("/:id", h.Discard)
+}
+
+// 거래 내역 파일(csv, xlsx) 업로드. 자산 매핑, 중복 확인 결과 반환. form: file, broker(kis|upbit|bithumb, 생략 시 자동 판단), fund_id(기본 자금)
+func (h *ImportHandler) Preview(c *fiber.Ctx) error {
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 file 조회 시 오류 발생. %w", err))
+	}
+	broker, err := importer.ParseBroker(c.FormValue("broker"))
+	if err != nil {
+		return apierr.BadRequest(err)
+	}
+	var fundId uint64
+	if v := c.FormValue("fund_id"); v != "" {
+		fundId, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return apierr.BadRequest(fmt.Errorf("파라미터 fund_id 변환 시 오류 발생. %w", err))
+		}
+		if !canEditFund(c, uint(fundId)) {
+			return forbidden(c)
+		}
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return fmt.Errorf("업로드 파일 열기 시 오류 발생. %w", err)
+	}
+	defer f.Close()
+
+	b, err := h.im.Preview(fh.Filename, f, broker, uint(fundId), RequestKey(c))
+	if err != nil {
+		return importErr("Preview", err)
+	}
+	addAudit(c, audit.Entry{Action: m.AuditCreate, Entity: "import", EntityID: b.ID, After: fiber.Map{"name": b.Name, "broker": b.Broker, "fund_id": fundId, "rows": len(b.Rows)}})
+
+	return c.Status(fiber.StatusOK).JSON(newImportBatchResponse(b))
+}
+
+// 미리보기 결과 조회
+func (h *ImportHandler) Batch(c *fiber.Ctx) error {
+
+	b, err := h.batch(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newImportBatchResponse(b))
+}
+
+// 미리보기 행을 투자 이력으로 기록. 전체 성공 혹은 전체 미반영. 기록되는 모든 자금에 editor 이상 권한 필요
+func (h *ImportHandler) Commit(c *fiber.Ctx) error {
+
+	b, err := h.batch(c)
+	if err != nil {
+		return err
+	}
+
+	var param CommitImportReq
+	if len(c.Body()) > 0 {
+		err := c.BodyParser(&param)
+		if err != nil {
+			return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
+		}
+	}
+
+	funds := make(map[int]uint, len(param.Funds))
+	for line, fundId := range param.Funds {
+		n, err := strconv.Atoi(line)
+		if err != nil {
+			return apierr.BadRequest(fmt.Errorf("파라미터 funds 행 번호 변환 시 오류 발생. %w", err))
+		}
+		funds[n] = fundId
+	}
+	for _, row := range b.Rows {
+		if row.Err != "" {
+			continue
+		}
+		fundId, ok := funds[row.Line]
+		if !ok {
+			fundId = row.FundID
+		}
+		if fundId != 0 && !canEditFund(c, fundId) {
+			return forbidden(c)
+		}
+	}
+
+	n, err := h.im.Commit(b.ID, funds, param.IncludeDuplicates)
+	if err != nil {
+		return importErr("Commit", err)
+	}
+	addAudit(c, audit.Entry{Action: m.AuditUpdate, Entity: "import", EntityID: b.ID, After: fiber.Map{"funds": funds, "include_duplicates": param.IncludeDuplicates, "recorded": n}})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"recorded": n,
+	})
+}
+
+// 미리보기 폐기
+func (h *ImportHandler) Discard(c *fiber.Ctx) error {
+
+	b, err := h.batch(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.im.Discard(b.ID)
+	if err != nil {
+		return importErr("Discard", err)
+	}
+	addAudit(c, audit.Entry{Action: m.AuditDelete, Entity: "import", EntityID: b.ID})
+
+	return c.Status(fiber.StatusOK).SendString("폐기 완료")
+}
+
+// batch 경로의 미리보기 결과. 생성한 요청 주체 혹은 admin만 접근 가능
+func (h *ImportHandler) batch(c *fiber.Ctx) (*importer.Batch, error) {
+
+	b, ok := h.im.Batch(c.Params("id"))
+	if !ok {
+		return nil, apierr.NotFound(importer.ErrBatchNotFound)
+	}
+	if !isAdmin(c) && b.Owner != RequestKey(c) {
+		return nil, forbidden(c)
+	}
+	return b, nil
+}
+
+func newImportBatchResponse(b *importer.Batch) importBatchResponse {
+	resp := importBatchResponse{
+		Id:        b.ID,
+		Name:      b.Name,
+		Broker:    string(b.Broker),
+		ExpiresAt: b.ExpiresAt.Format("2006-01-02 15:04:05"),
+		Rows:      make([]importRowResponse, len(b.Rows)),
+	}
+	for i, row := range b.Rows {
+		resp.Rows[i] = importRowResponse{
+			Line:      row.Line,
+			TradedAt:  row.TradedAt.Format("2006-01-02 15:04:05"),
+			Code:      row.Code,
+			AssetId:   row.AssetID,
+			AssetName: row.AssetName,
+			FundId:    row.FundID,
+			Price:     row.Price,
+			Count:     row.Count,
+			Duplicate: row.Duplicate,
+			Error:     row.Err,
+		}
+		if row.TradedAt.IsZero() {
+			resp.Rows[i].TradedAt = ""
+		}
+	}
+	return resp
+}
+
+// importErr 요청 만료는 404, 파일 형식 오류, 자금 미지정은 400
+func importErr(op string, err error) error {
+	err = fmt.Errorf("%s 시 오류 발생. %w", op, err)
+	switch {
+	case errors.Is(err, importer.ErrBatchNotFound):
+		return apierr.NotFound(err)
+	case errors.Is(err, importer.ErrInvalidFile), errors.Is(err, importer.ErrFundMissing):
+		return apierr.BadRequest(err)
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"investindicator/app/apierr"
+	"investindicator/internal/importer"
+	m "investindicator/internal/model"
+	"investindicator/internal/session"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportOwner(t *testing.T) {
+
+	hash, _ := hashPassword("password1")
+	users := &UserRetrieverMock{
+		users: []m.User{
+			{ID: 1, Username: "admin", Password: hash, IsAdmin: true},
+			{ID: 2, Username: "creator", Password: hash},
+			{ID: 3, Username: "other", Password: hash},
+		},
+		funds: map[int]map[uint]m.FundRole{
+			2: {1: m.FundEditor},
+			3: {1: m.FundEditor},
+		},
+	}
+	im := &InvestImporterMock{batches: map[string]*importer.Batch{
+		"b1": {ID: "b1", Owner: "user:2"},
+		"b2": {ID: "b2", Owner: "user:2"},
+		"b3": {ID: "b3", Owner: "user:2"},
+	}}
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			e := apierr.From(err)
+			return c.Status(e.Status()).SendString(e.Message)
+		},
+	})
+	NewAuthHandler(users, UserManagerMock{users}, session.NewManager(nil), ApiKeyManagerMock{}, "authkey").InitRoute(app)
+	NewImportHandler(im).InitRoute(app)
+
+	request := func(method, path, token, body string) (int, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+	status := func(method, path, token string) int {
+		code, _ := request(method, path, token, "")
+		return code
+	}
+	login := func(username string) string {
+		_, body := request("POST", "/login", "", fmt.Sprintf(`{"username":"%s","password":"password1"}`, username))
+		var tk JWTResponse
+		json.Unmarshal([]byte(body), &tk)
+		return tk.Token
+	}
+	admin, creator, other := login("admin"), login("creator"), login("other")
+
+	t.Run("Batch", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusOK, status("GET", "/imports/b1", creator))
+		assert.Equal(t, fiber.StatusForbidden, status("GET", "/imports/b1", other), "다른 사용자의 미리보기 조회 불가")
+		assert.Equal(t, fiber.StatusOK, status("GET", "/imports/b1", admin))
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusForbidden, status("POST", "/imports/b1/commit", other))
+		assert.Equal(t, fiber.StatusOK, status("POST", "/imports/b1/commit", creator))
+		assert.Equal(t, fiber.StatusOK, status("POST", "/imports/b2/commit", admin))
+	})
+
+	t.Run("Discard", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusForbidden, status("DELETE", "/imports/b3", other), "다른 사용자의 미리보기 폐기 불가")
+		assert.Contains(t, im.batches, "b3")
+		assert.Equal(t, fiber.StatusOK, status("DELETE", "/imports/b3", creator))
+		assert.Equal(t, fiber.StatusNotFound, status("DELETE", "/imports/b3", creator))
+	})
+}
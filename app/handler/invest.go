@@ -3,6 +3,8 @@ package handler
 import (
 	"errors"
 	"fmt"
+	"investindicator/app/apierr"
+	"investindicator/internal/audit"
 	m "investindicator/internal/model"
 
 	"github.com/gofiber/fiber/v2"
@@ -14,8 +16,8 @@ type InvestHandler struct {
 	e ExchageRateGetter
 }
 
-func (h *InvestHandler) InitRoute(app *fiber.App) {
-	router := app.Group("/invest")
+func (h *InvestHandler) InitRoute(r fiber.Router) {
+	router := r.Group("/invest")
 	router.Post("/", h.SaveInvest)
 }
 
@@ -47,12 +49,15 @@ func (h *InvestHandler) SaveInvest(c *fiber.Ctx) error {
 	param := SaveInvestParam{}
 	err := c.BodyParser(&param)
 	if err != nil {
-		return fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
 	}
 
 	err = validCheck(&param)
 	if err != nil {
-		return fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
+	}
+	if !canEditFund(c, param.FundId) {
+		return forbidden(c)
 	}
 
 	var assetId uint
@@ -66,20 +71,21 @@ func (h *InvestHandler) SaveInvest(c *fiber.Ctx) error {
 		assetId = h.r.RetrieveAssetIdByCode(param.AssetName)
 	}
 	if assetId == 0 {
-		return errors.New("parameter asset 정보 없음")
+		return apierr.BadRequest(errors.New("parameter asset 정보 없음"))
 	}
 
 	// 투자 이력 저장
-	h.w.RecordInvest(m.Invest{
+	invest := m.Invest{
 		FundID:  param.FundId,
 		AssetID: param.AssetId,
 		Price:   param.Price,
 		Count:   param.Count,
-	})
-
+	}
+	err = h.w.RecordInvest(invest)
 	if err != nil {
 		return fmt.Errorf("UpdateInvestSummaryCount 오류 발생. %w", err)
 	}
+	addAudit(c, audit.Entry{Action: m.AuditCreate, Entity: "invest", EntityID: invest.FundID, After: invest})
 
 	return c.Status(fiber.StatusOK).SendString("Invest 이력 저장 성공")
 }
@@ -89,12 +95,12 @@ func (h *InvestHandler) SaveInvest(c *fiber.Ctx) error {
 // 	var param model.GetInvestHistParam
 // 	err := c.BodyParser(&param)
 // 	if err != nil {
-// 		return fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err)
+// 		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
 // 	}
 
 // 	err = validCheck(&param)
 // 	if err != nil {
-// 		return fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err)
+// 		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
 // 	}
 
 // 	investHist, err := h.r.RetrieveInvestHist(param.FundId, param.AssetId, param.StartDate, param.EndDate)
@@ -15,7 +15,7 @@ func TestInvestGetHandler(t *testing.T) {
 	const dollarPrice float64 = 1400
 
 	app := fiber.New()
-	middleware.SetupMiddleware(app, nil)
+	middleware.SetupMiddleware(app, nil, nil)
 
 	readerMock := NewADefaultssetRetrieverMock()
 	writerMock := NewInvestSaverMock(initAmount)
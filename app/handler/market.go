@@ -2,6 +2,9 @@ package handler
 
 import (
 	"fmt"
+	"investindicator/app/apierr"
+	"investindicator/internal/audit"
+	m "investindicator/internal/model"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -18,9 +21,9 @@ func NewMarketHandler(r MaketRetriever, w MarketSaver) *MarketHandler {
 	}
 }
 
-func (h *MarketHandler) InitRoute(app *fiber.App) {
+func (h *MarketHandler) InitRoute(r fiber.Router) {
 
-	router := app.Group("/market")
+	router := r.Group("/market")
 	router.Get("/weekly_indicators", h.WeekMarketIndicators)
 	router.Get("/indicators/:date?", h.MarketIndicator)
 	router.Get("/:date?", h.Market)
@@ -33,7 +36,7 @@ func (h *MarketHandler) Market(c *fiber.Ctx) error {
 
 	isDateFormat := dateCheck(date)
 	if !isDateFormat {
-		return fmt.Errorf("파라미터 유효성 검사 시 오류 발생. 올바르지 않은 date 포맷. %s", date)
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. 올바르지 않은 date 포맷. %s", date))
 	}
 
 	market, err := h.r.RetrieveMarketStatus(date)
@@ -50,7 +53,7 @@ func (h *MarketHandler) MarketIndicator(c *fiber.Ctx) error {
 
 	isDateFormat := dateCheck(date)
 	if !isDateFormat {
-		return fmt.Errorf("파라미터 유효성 검사 시 오류 발생. 올바르지 않은 date 포맷. %s", date)
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. 올바르지 않은 date 포맷. %s", date))
 	}
 
 	dailyIdx, cliIdx, err := h.r.RetrieveMarketIndicator(date)
@@ -156,18 +159,24 @@ func (h *MarketHandler) ChangeMarketStatus(c *fiber.Ctx) error {
 	var param SaveMarketStatusParam
 	err := c.BodyParser(&param)
 	if err != nil {
-		return fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
 	}
 
 	err = validCheck(&param)
 	if err != nil {
-		return fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err)
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
+	}
+
+	var before any
+	if prev, err := h.r.RetrieveMarketStatus(""); err == nil {
+		before = fiber.Map{"status": prev.Status}
 	}
 
 	err = h.w.SaveMarketStatus(param.Status)
 	if err != nil {
 		return fmt.Errorf("RetrieveMarketStatus 오류 발생. %w", err)
 	}
+	addAudit(c, audit.Entry{Action: m.AuditUpdate, Entity: "market", Before: before, After: fiber.Map{"status": param.Status}})
 
 	return c.Status(fiber.StatusOK).SendString("시장 상태 저장 성공")
 
@@ -15,7 +15,7 @@ import (
 func TestMarketHandler(t *testing.T) {
 
 	app := fiber.New()
-	middleware.SetupMiddleware(app, nil)
+	middleware.SetupMiddleware(app, nil, nil)
 
 	readerMock := MaketRetrieverMock{}
 	writerMock := MarketSaverMock{}
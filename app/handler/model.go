@@ -9,9 +9,9 @@ import (
 type ModelHandler struct {
 }
 
-func (h *ModelHandler) InitRoute(app *fiber.App) {
-	app.Get("/categories", h.GetCategories)
-	app.Get("/currencies", h.GetCurrencies)
+func (h *ModelHandler) InitRoute(r fiber.Router) {
+	r.Get("/categories", h.GetCategories)
+	r.Get("/currencies", h.GetCurrencies)
 }
 
 func NewCategoryHandler() *ModelHandler {
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"investindicator/app/openapi"
+	"investindicator/internal/export"
+	m "investindicator/internal/model"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	pageParams = []openapi.Param{
+		{Name: "limit", Type: "integer", Description: "기본 100, 최대 1000"},
+		{Name: "offset", Type: "integer"},
+		{Name: "cursor", Type: "string", Description: "이전 응답의 X-Next-Cursor. 지정 시 offset 무시"},
+	}
+	investParams = params([]openapi.Param{
+		{Name: "fund_id", Type: "integer"},
+		{Name: "asset_id", Type: "integer"},
+		{Name: "category", Type: "string", Enum: m.CategoryList()},
+		{Name: "side", Type: "string", Enum: []string{string(m.Buy), string(m.Sell)}},
+		{Name: "min_amount", Type: "number", Description: "|가격 * 수량| 이상"},
+		{Name: "max_amount", Type: "number", Description: "|가격 * 수량| 이하"},
+		{Name: "start", Type: "string", Description: "2006-01-02"},
+		{Name: "end", Type: "string", Description: "2006-01-02. 해당 일자 포함"},
+		{Name: "sort", Type: "string", Description: "created_at(기본, 내림차순)|price|count|amount. '-' prefix 내림차순"},
+	}, pageParams)
+	daysParams   = []openapi.Param{{Name: "days", Type: "integer", Description: "조회 기간(일)"}}
+	formatParams = []openapi.Param{{Name: "format", Type: "string", Enum: []string{string(export.CSV), string(export.JSON), string(export.XLSX)}}}
+	exportTypes  = []string{export.CSV.ContentType(), export.JSON.ContentType(), export.XLSX.ContentType()}
+	stringId     = []openapi.Param{{Name: "id", Type: "string"}}
+)
+
+func params(groups ...[]openapi.Param) []openapi.Param {
+	ps := make([]openapi.Param, 0)
+	for _, g := range groups {
+		ps = append(ps, g...)
+	}
+	return ps
+}
+
+// routeDocs 경로별 요청, 응답 타입. 경로 추가 시 함께 등록(openapi_test에서 누락 확인)
+var routeDocs = []openapi.Route{
+	{Method: "POST", Path: "/login", Summary: "로그인. JWT, refresh token 발급. 연속 실패 시 일정 시간 로그인 거부(429)", Body: LoginRequest{}, Response: JWTResponse{}, Public: true},
+	{Method: "POST", Path: "/login/refresh", Summary: "token 재발급. 사용한 refresh token은 폐기", Body: RefreshRequest{}, Response: JWTResponse{}, Public: true},
+	{Method: "POST", Path: "/logout", Summary: "로그아웃. 현재 세션 token 폐기"},
+	{Method: "PUT", Path: "/users/me/password", Summary: "비밀번호 변경. 모든 세션 폐기 후 새 token 발급", Body: ChangePasswordReq{}, Response: JWTResponse{}},
+
+	{Method: "GET", Path: "/users", Summary: "사용자 목록(admin)", Response: []UserResponse{}},
+	{Method: "POST", Path: "/users", Summary: "사용자 등록(admin)", Body: AddUserReq{}, Response: UserResponse{}},
+	{Method: "GET", Path: "/users/{id}", Summary: "사용자 상세(admin)", Response: UserResponse{}},
+	{Method: "PUT", Path: "/users/{id}", Summary: "사용자 정보 갱신(admin). 비밀번호 초기화, 비활성화, 권한 변경 시 세션 폐기", Body: UpdateUserReq{}, Response: UserResponse{}},
+	{Method: "DELETE", Path: "/users/{id}", Summary: "사용자 삭제(admin)"},
+
+	{Method: "GET", Path: "/apikeys", Summary: "API 키 목록(admin)", Response: []ApiKeyResponse{}},
+	{Method: "POST", Path: "/apikeys", Summary: "API 키 발급(admin). 키 원문은 이 응답에서만 확인 가능", Body: AddApiKeyReq{}, Response: IssuedApiKeyResponse{}},
+	{Method: "DELETE", Path: "/apikeys/{id}", Summary: "API 키 폐기(admin)"},
+
+	{Method: "GET", Path: "/assets", Summary: "자산 목록, 현재가", Response: []assetResponse{}, Query: params([]openapi.Param{
+		{Name: "max_age", Type: "integer", Description: "허용할 시세 나이(초). 미지정 시 카테고리 TTL"},
+		{Name: "category", Type: "string", Enum: m.CategoryList()},
+		{Name: "currency", Type: "string", Enum: m.CurrencyList()},
+		{Name: "q", Type: "string", Description: "이름, 코드 부분 일치"},
+		{Name: "sort", Type: "string", Description: "id(기본)|name|code|category. '-' prefix 내림차순"},
+	}, pageParams)},
+	{Method: "POST", Path: "/assets", Summary: "자산 등록", Body: AddAssetReq{}},
+	{Method: "PUT", Path: "/assets", Summary: "자산 정보 갱신", Body: UpdateAssetReq{}},
+	{Method: "DELETE", Path: "/assets", Summary: "자산 삭제", Body: DeleteAssetReq{}},
+	{Method: "GET", Path: "/assets/list", Summary: "자산 id, 이름 목록", Response: []assetListResponse{}},
+	{Method: "GET", Path: "/assets/{id}", Summary: "자산 상세", Response: assetResponse{}},
+	{Method: "GET", Path: "/assets/{id}/hist", Summary: "자산 투자 이력", Response: []HistResponse{}, Query: investParams},
+
+	{Method: "GET", Path: "/funds", Summary: "자금별 총액", Response: map[string]TotalStatusResp{}},
+	{Method: "POST", Path: "/funds", Summary: "자금 추가(admin). owner_id 미입력 시 요청 사용자가 owner", Body: AddFundReq{}},
+	{Method: "GET", Path: "/funds/{id}/hist", Summary: "자금 투자 이력", Response: []HistResponse{}, Query: investParams},
+	{Method: "GET", Path: "/funds/{id}/assets", Summary: "자금 보유 자산", Response: []fundAssetsResponse{}},
+	{Method: "GET", Path: "/funds/{id}/portion", Summary: "자금 안전, 변동 자산 비중", Response: fundPortionResponse{}},
+	{Method: "GET", Path: "/funds/{id}/available_amounts", Summary: "투자 가능 금액", Response: float64(0)},
+	{Method: "GET", Path: "/funds/{id}/members", Summary: "자금 공유 사용자, 권한", Response: []FundMemberResponse{}},
+	{Method: "PUT", Path: "/funds/{id}/members", Summary: "자금 공유 추가, 권한 변경(owner)", Body: FundMemberReq{}},
+	{Method: "DELETE", Path: "/funds/{id}/members/{userId}", Summary: "자금 공유 해제(owner, 본인). 마지막 owner는 해제 불가"},
+
+	{Method: "POST", Path: "/invest", Summary: "투자 이력 저장(자금 editor 이상)", Body: SaveInvestParam{}},
+
+	{Method: "GET", Path: "/market", Summary: "오늘 시장 단계", Response: m.Market{}},
+	{Method: "GET", Path: "/market/{date}", Summary: "일자별 시장 단계", Response: m.Market{}},
+	{Method: "POST", Path: "/market", Summary: "시장 단계 변경", Body: SaveMarketStatusParam{}},
+	{Method: "GET", Path: "/market/indicators", Summary: "오늘 시장 지표. [일별 지표, CLI]", Response: []any{}},
+	{Method: "GET", Path: "/market/indicators/{date}", Summary: "일자별 시장 지표. [일별 지표, CLI]", Response: []any{}},
+	{Method: "GET", Path: "/market/weekly_indicators", Summary: "주간 시장 지표", Response: map[string]MarketIndexInner{}},
+
+	{Method: "GET", Path: "/categories", Summary: "자산 카테고리 목록", Response: []string{}},
+	{Method: "GET", Path: "/audit", Summary: "상태 변경 이력(admin). 최신순", Response: []AuditLogResponse{}, Query: params([]openapi.Param{
+		{Name: "actor_type", Type: "string", Enum: []string{string(m.ActorUser), string(m.ActorApiKey), string(m.ActorChat), string(m.ActorSystem)}},
+		{Name: "actor_id", Type: "string"},
+		{Name: "entity", Type: "string", Description: "asset, fund, invest, market, event 등"},
+		{Name: "entity_id", Type: "string"},
+		{Name: "action", Type: "string", Enum: []string{string(m.AuditCreate), string(m.AuditUpdate), string(m.AuditDelete), string(m.AuditRun)}},
+		{Name: "request_id", Type: "string"},
+		{Name: "start", Type: "string", Description: "2006-01-02"},
+		{Name: "end", Type: "string", Description: "2006-01-02. 해당 일자 포함"},
+	}, pageParams)},
+
+	{Method: "GET", Path: "/currencies", Summary: "통화 목록", Response: []string{}},
+
+	{Method: "GET", Path: "/events", Summary: "이벤트 목록", Response: []EventResponse{}, Query: params([]openapi.Param{
+		{Name: "active", Type: "boolean"},
+		{Name: "sort", Type: "string", Description: "id(기본)|title. '-' prefix 내림차순"},
+	}, pageParams)},
+	{Method: "POST", Path: "/events/switch", Summary: "이벤트 활성 상태 변경", Body: EventStatusChangeRequest{}},
+	{Method: "POST", Path: "/events/launch", Summary: "이벤트 수동 실행", Body: EventLaunchRequest{}},
+
+	{Method: "GET", Path: "/stream", Summary: "실시간 push(Server-Sent Events). EventSource는 access_token query로 인증", Produces: []string{"text/event-stream"}, Query: []openapi.Param{
+		{Name: "topics", Type: "string", Description: "콤마 구분. " + strings.Join(m.TopicList(), ", ") + ". 미입력 시 prices 제외 전체"},
+		{Name: "access_token", Type: "string", Description: "Authorization header 대신 사용하는 JWT"},
+	}},
+
+	{Method: "GET", Path: "/alerts/suppressed", Summary: "중복 억제 중인 알림", Response: []suppressedAlertResponse{}},
+	{Method: "GET", Path: "/providers", Summary: "시세 제공처 상태", Response: []providerStatusResponse{}},
+
+	{Method: "GET", Path: "/prompts", Summary: "응답 대기 중인 선택지 요청", Response: []promptResponse{}},
+	{Method: "POST", Path: "/prompts/{id}/answer", Summary: "선택지 요청 응답", Params: stringId, Body: AnswerPromptReq{}},
+	{Method: "DELETE", Path: "/prompts/{id}", Summary: "선택지 요청 취소", Params: stringId},
+
+	{Method: "GET", Path: "/charts/funds/{id}", Summary: "자금 평가액 추이 차트", Query: daysParams, Produces: []string{"image/png"}},
+	{Method: "GET", Path: "/charts/assets/{id}", Summary: "자산 가격, 매매 기준 차트", Query: daysParams, Produces: []string{"image/png"}},
+	{Method: "GET", Path: "/charts/indicators", Summary: "시장 지표 차트", Query: daysParams, Produces: []string{"image/png"}},
+	{Method: "GET", Path: "/charts/premium/{id}", Summary: "김치 프리미엄 추이 차트", Query: daysParams, Produces: []string{"image/png"}},
+
+	{Method: "GET", Path: "/exports/ledger", Summary: "투자 이력 내보내기", Query: params(formatParams, investParams), Produces: exportTypes},
+	{Method: "GET", Path: "/exports/holdings", Summary: "보유 자산 내보내기", Query: params(formatParams, investParams), Produces: exportTypes},
+	{Method: "GET", Path: "/exports/funds/{id}/nav", Summary: "자금 평가액 내보내기", Query: params(formatParams, daysParams), Produces: exportTypes},
+	{Method: "GET", Path: "/exports/indicators", Summary: "시장 지표 내보내기", Query: params(formatParams, daysParams), Produces: exportTypes},
+
+	{Method: "POST", Path: "/imports", Summary: "거래 내역 파일 미리보기", Response: importBatchResponse{}, Form: []openapi.Param{
+		{Name: "file", Type: "file", Required: true, Description: "csv, xlsx"},
+		{Name: "broker", Type: "string", Description: "kis|upbit|bithumb. 생략 시 자동 판단"},
+		{Name: "fund_id", Type: "integer", Description: "기본 자금"},
+	}},
+	{Method: "GET", Path: "/imports/{id}", Summary: "미리보기 조회", Params: stringId, Response: importBatchResponse{}},
+	{Method: "POST", Path: "/imports/{id}/commit", Summary: "미리보기 기록", Params: stringId, Body: CommitImportReq{}, Optional: true, Response: map[string]int{}},
+	{Method: "DELETE", Path: "/imports/{id}", Summary: "미리보기 폐기", Params: stringId},
+
+	{Method: "GET", Path: "/blackhole/profit", Summary: "BLACKHOLE 유동성 수익", Response: ProfitResponse{}, Query: []openapi.Param{
+		{Name: "baseDate", Type: "string", Required: true, Description: "2006-01-02"},
+	}},
+	{Method: "POST", Path: "/blackhole/swap", Summary: "전체 토큰 swap", Body: SwapAllRequest{}},
+}
+
+// validate tag별 schema 제약. validator.go의 사용자 정의 검사와 동일하게 유지
+var schemaTags = map[string]func(*openapi.Schema){
+	"category": func(s *openapi.Schema) {
+		for _, c := range m.CategoryList() {
+			s.Enum = append(s.Enum, c)
+		}
+	},
+	"market_status": func(s *openapi.Schema) {
+		min, max := 1.0, 5.0
+		s.Minimum, s.Maximum = &min, &max
+	},
+	"providers": func(s *openapi.Schema) {
+		s.Description = "시세 제공처 체인. 카테고리를 조회할 수 있는 제공처 1개 이상 필요. ex) bithumb,upbit"
+	},
+	"scopes": func(s *openapi.Schema) {
+		s.Description = "콤마 구분 API 키 scope. " + strings.Join(m.ScopeList(), ", ")
+	},
+	"fund_role": func(s *openapi.Schema) {
+		for _, r := range m.FundRoleList() {
+			s.Enum = append(s.Enum, r)
+		}
+	},
+	"date": func(s *openapi.Schema) {
+		s.Pattern = `^(\d{4}-\d{2}-\d{2})?$`
+	},
+}
+
+// OpenAPI prefix 하위에 등록된 경로로 문서 생성. Route 정보가 없는 경로 목록 함께 반환
+func OpenAPI(registered []fiber.Route, prefix string) (*openapi.Document, []string) {
+	return openapi.Builder{
+		Info: openapi.Info{
+			Title:   "Invest Indicator API",
+			Version: "1",
+		},
+		Prefix: prefix,
+		Security: map[string]openapi.SecurityScheme{
+			"bearer": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			"apiKey": {Type: "apiKey", In: "header", Name: "Authorization", Description: "\"ApiKey {key}\". 발급 시 지정한 scope 내에서만 사용 가능"},
+		},
+		Tags:     schemaTags,
+		Envelope: true,
+	}.Build(registered, routeDocs)
+}
+
+// DocsHandler OpenAPI 문서, Swagger UI 제공. 인증 없이 조회 가능하도록 인증 middleware 이전에 등록
+type DocsHandler struct {
+	mu  sync.RWMutex
+	doc *openapi.Document
+}
+
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+func (h *DocsHandler) InitRoute(r fiber.Router) {
+	r.Get("/openapi.json", h.Spec)
+	r.Get("/docs", h.SwaggerUI)
+}
+
+// Load 경로 등록 후 생성한 문서 지정
+func (h *DocsHandler) Load(doc *openapi.Document) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.doc = doc
+}
+
+func (h *DocsHandler) Spec(c *fiber.Ctx) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.doc == nil {
+		return fiber.ErrServiceUnavailable
+	}
+	return c.Status(fiber.StatusOK).JSON(h.doc)
+}
+
+func (h *DocsHandler) SwaggerUI(c *fiber.Ctx) error {
+	c.Type("html", "utf-8")
+	return c.Status(fiber.StatusOK).SendString(swaggerUI)
+}
+
+// memo. swagger-ui는 CDN에서 로드
+const swaggerUI = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8"/>
+  <title>Invest Indicator API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css"/>
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>`
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPI(t *testing.T) {
+
+	app := fiber.New()
+	v1 := app.Group("/v1")
+	for _, h := range []interface{ InitRoute(fiber.Router) }{
+		NewAuthHandler(nil, nil, nil, nil, ""),
+		NewUserHandler(nil, nil),
+		NewAuditHandler(nil),
+		NewApiKeyHandler(nil),
+		NewAssetHandler(nil, nil, nil, nil),
+		NewFundHandler(nil, nil, nil, nil, nil),
+		NewInvestHandler(nil, nil, nil),
+		NewMarketHandler(nil, nil),
+		NewCategoryHandler(),
+		NewEventHandler(nil, nil, nil),
+		NewAlertHandler(nil),
+		NewStreamHandler(nil),
+		NewProviderHandler(nil),
+		NewPromptHandler(nil),
+		NewChartHandler(nil),
+		NewImportHandler(nil),
+		NewExportHandler(nil),
+		NewBlackholeHandler(nil, nil),
+	} {
+		h.InitRoute(v1)
+	}
+
+	doc, undocumented := OpenAPI(app.GetRoutes(true), "/v1")
+	assert.Empty(t, undocumented, "routeDocs에 미등록 경로")
+
+	for _, r := range routeDocs {
+		item, ok := doc.Paths[r.Path]
+		if assert.True(t, ok, "등록되지 않은 경로 문서 %s %s", r.Method, r.Path) {
+			assert.Contains(t, item, strings.ToLower(r.Method), r.Path)
+		}
+	}
+
+	_, err := json.Marshal(doc)
+	assert.NoError(t, err)
+
+	login := doc.Paths["/login"]["post"]
+	assert.NotNil(t, login.Security, "로그인은 인증 불필요")
+	assert.Contains(t, doc.Components.Schemas, "AddAssetReq")
+	assert.ElementsMatch(t, []string{"name", "category", "currency"}, doc.Components.Schemas["AddAssetReq"].Required)
+}
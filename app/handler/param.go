@@ -1,5 +1,10 @@
 package handler
 
+import (
+	"encoding/json"
+	"time"
+)
+
 /***************************************************************** request ****************************************************************/
 
 type AssetHistReq struct {
@@ -10,10 +15,18 @@ type TotalStatusResp struct {
 	ID     uint    `json:"id"`
 	Name   string  `json:"name"`
 	Amount float64 `json:"amount"`
+	Role   string  `json:"role"` // 요청 사용자의 자금 권한. admin은 owner
 }
 
+// AddFundReq owner_id 미입력 시 요청 사용자가 owner
 type AddFundReq struct {
-	Name string `json:"name" validate:"required"`
+	Name    string `json:"name" validate:"required"`
+	OwnerId int    `json:"owner_id"`
+}
+
+type FundMemberReq struct {
+	UserId int    `json:"user_id" validate:"required"`
+	Role   string `json:"role" validate:"required,fund_role"`
 }
 
 type AddAssetReq struct {
@@ -25,6 +38,7 @@ type AddAssetReq struct {
 	Bottom    float64 `json:"bottom"`
 	SellPrice float64 `json:"sell"`
 	BuyPrice  float64 `json:"buy"`
+	Providers string  `json:"providers" validate:"providers"` // 시세 제공처 체인. ex) "bithumb,upbit"
 	Ema       float64 `json:"ema"`
 	Ndays     uint    `json:"ndays"`
 }
@@ -39,6 +53,7 @@ type UpdateAssetReq struct {
 	Bottom    float64 `json:"bottom"`
 	SellPrice float64 `json:"sell"`
 	BuyPrice  float64 `json:"buy"`
+	Providers string  `json:"providers" validate:"providers"`
 }
 
 type DeleteAssetReq struct {
@@ -64,6 +79,38 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type ChangePasswordReq struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8"`
+}
+
+type AddUserReq struct {
+	Username   string `json:"username" validate:"required"`
+	Email      string `json:"email"`
+	Password   string `json:"password" validate:"required,min=8"`
+	IsAdmin    bool   `json:"is_admin"`
+	TelegramId *int64 `json:"telegram_id"`
+}
+
+type AddApiKeyReq struct {
+	Name          string `json:"name" validate:"required"`
+	Scopes        string `json:"scopes" validate:"required,scopes"` // ex) "read,invest"
+	ExpiresInDays uint   `json:"expires_in_days"`                   // 0이면 만료 없음
+}
+
+// UpdateUserReq 미입력 필드는 기존 값 유지. password 입력 시 비밀번호 초기화
+type UpdateUserReq struct {
+	Email      *string `json:"email"`
+	Password   string  `json:"password" validate:"omitempty,min=8"`
+	IsAdmin    *bool   `json:"is_admin"`
+	TelegramId *int64  `json:"telegram_id"`
+	Disabled   *bool   `json:"disabled"`
+}
+
 /***************************************************************** resoponse ****************************************************************/
 
 type assetListResponse struct {
@@ -72,18 +119,27 @@ type assetListResponse struct {
 }
 
 type assetResponse struct {
-	ID        uint    `json:"id"`
-	Name      string  `json:"name"`
-	Category  string  `json:"category"`
-	Code      string  `json:"code"`
-	Currency  string  `json:"currency"`
-	Top       float64 `json:"top"`
-	Bottom    float64 `json:"bottom"`
-	SellPrice float64 `json:"sell"`
-	BuyPrice  float64 `json:"buy"`
-	Ema       float64 `json:"ema"`
-	NDays     float64 `json:"ndays"`
-	Price     float64 `json:"price"`
+	ID        uint           `json:"id"`
+	Name      string         `json:"name"`
+	Category  string         `json:"category"`
+	Code      string         `json:"code"`
+	Currency  string         `json:"currency"`
+	Top       float64        `json:"top"`
+	Bottom    float64        `json:"bottom"`
+	SellPrice float64        `json:"sell"`
+	BuyPrice  float64        `json:"buy"`
+	Ema       float64        `json:"ema"`
+	NDays     float64        `json:"ndays"`
+	Price     float64        `json:"price"`
+	Providers string         `json:"providers"`
+	Quote     *quoteResponse `json:"quote,omitempty"`
+}
+
+// 시세 조회 정보. age는 조회 후 경과 시간(초)
+type quoteResponse struct {
+	Source    string  `json:"source"`
+	FetchedAt string  `json:"fetched_at"`
+	Age       float64 `json:"age"`
 }
 
 type HistResponse struct {
@@ -101,16 +157,16 @@ type fundAssetsResponse struct {
 	// AssetName    string  `json:"asset_name"`
 	// Count        float64 `json:"count"`
 	// Sum          float64 `json:"sum"`
-	Name            string `json:"name"`
-	Amount          string `json:"amount"`
-	AmountDollar    string `json:"amount_dollar"`
-	ProfitRate      string `json:"profit_rate"`
-	MajorCategory   string `json:"major_category"`
-	MiddleCategory  string `json:"middle_category"`
-	SmallCategory   string `json:"small_category"`
-	Quantity        string `json:"quantity"`
-	Price           string `json:"price"`
-	PriceDollar     string `json:"price_dollar"`
+	Name           string `json:"name"`
+	Amount         string `json:"amount"`
+	AmountDollar   string `json:"amount_dollar"`
+	ProfitRate     string `json:"profit_rate"`
+	MajorCategory  string `json:"major_category"`
+	MiddleCategory string `json:"middle_category"`
+	SmallCategory  string `json:"small_category"`
+	Quantity       string `json:"quantity"`
+	Price          string `json:"price"`
+	PriceDollar    string `json:"price_dollar"`
 }
 
 type fundPortionResponse struct {
@@ -131,6 +187,79 @@ type EventResponse struct {
 	Active      bool   `json:"active"`
 }
 
+type suppressedAlertResponse struct {
+	Type      string  `json:"type"`
+	Id        uint    `json:"id"`
+	Price     float64 `json:"price"`
+	SentAt    string  `json:"sent_at"`
+	ExpiresAt string  `json:"expires_at"`
+}
+
+type promptResponse struct {
+	Id        string   `json:"id"`
+	Route     string   `json:"route"`
+	Prompt    string   `json:"prompt"`
+	Options   []string `json:"options"`
+	Default   string   `json:"default,omitempty"`
+	CreatedAt string   `json:"created_at"`
+	Deadline  string   `json:"deadline"`
+}
+
+type AnswerPromptReq struct {
+	Answer string `json:"answer" validate:"required"`
+}
+
+type importRowResponse struct {
+	Line      int     `json:"line"`
+	TradedAt  string  `json:"traded_at"`
+	Code      string  `json:"code"`
+	AssetId   uint    `json:"asset_id"`
+	AssetName string  `json:"asset_name"`
+	FundId    uint    `json:"fund_id"`
+	Price     float64 `json:"price"`
+	Count     float64 `json:"count"`
+	Duplicate bool    `json:"duplicate"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type importBatchResponse struct {
+	Id        string              `json:"id"`
+	Name      string              `json:"name"`
+	Broker    string              `json:"broker"`
+	ExpiresAt string              `json:"expires_at"`
+	Rows      []importRowResponse `json:"rows"`
+}
+
+// CommitImportReq Funds는 행 번호별 자금 id. 0이면 해당 행 제외
+type CommitImportReq struct {
+	Funds             map[string]uint `json:"funds"`
+	IncludeDuplicates bool            `json:"include_duplicates"`
+}
+
+type providerStatusResponse struct {
+	Provider    string `json:"provider"`
+	Healthy     bool   `json:"healthy"`
+	Failures    int    `json:"failures"`
+	LastError   string `json:"last_error"`
+	LastSuccess string `json:"last_success"`
+	LastFailure string `json:"last_failure"`
+}
+
+type HealthResponse struct {
+	Status     string                    `json:"status"`
+	Components []componentHealthResponse `json:"components"`
+}
+
+type componentHealthResponse struct {
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Required    bool   `json:"required"`
+	Error       string `json:"error,omitempty"`
+	Detail      string `json:"detail,omitempty"`
+	LastSuccess string `json:"last_success"`
+	LastRun     string `json:"last_run,omitempty"`
+}
+
 type EventStatusChangeRequest struct {
 	Id     uint `json:"id"`
 	Active bool `json:"active"`
@@ -142,8 +271,57 @@ type EventLaunchRequest struct {
 
 // JWTResponse is the response sent after successful authentication
 type JWTResponse struct {
-	Token  string `json:"token"`
-	Expiry int64  `json:"expiry"`
+	Token         string `json:"token"`
+	Expiry        int64  `json:"expiry"`
+	RefreshToken  string `json:"refresh_token"`
+	RefreshExpiry int64  `json:"refresh_expiry"`
+}
+
+type ApiKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IssuedApiKeyResponse 키 원문은 발급 시에만 응답
+type IssuedApiKeyResponse struct {
+	ApiKeyResponse
+	Key string `json:"key"`
+}
+
+type UserResponse struct {
+	ID         int    `json:"id"`
+	Username   string `json:"username"`
+	Email      string `json:"email"`
+	Role       string `json:"role"`
+	TelegramId *int64 `json:"telegram_id"`
+	Disabled   bool   `json:"disabled"`
+}
+
+type FundMemberResponse struct {
+	UserId   int    `json:"user_id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// AuditLogResponse before, after는 변경 전후 값. 값이 없으면 null
+type AuditLogResponse struct {
+	ID        uint            `json:"id"`
+	ActorType string          `json:"actor_type"`
+	ActorID   string          `json:"actor_id"`
+	Source    string          `json:"source"`
+	Action    string          `json:"action"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	RequestID string          `json:"request_id"`
+	CreatedAt time.Time       `json:"created_at"`
 }
 
 type ProfitResponse struct {
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"investindicator/app/apierr"
+	"investindicator/internal/audit"
+	m "investindicator/internal/model"
+	"investindicator/notify"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PromptHandler struct {
+	p PromptManager
+}
+
+func NewPromptHandler(p PromptManager) *PromptHandler {
+	return &PromptHandler{
+		p: p,
+	}
+}
+
+func (h *PromptHandler) InitRoute(r fiber.Router) {
+	router := r.Group("/prompts")
+	router.Get("/", h.Pending)
+	router.Post("/:id/answer", h.Answer)
+	router.Delete("/:id", h.Cancel)
+}
+
+// 응답 대기 중인 선택지 요청 목록
+func (h *PromptHandler) Pending(c *fiber.Ctx) error {
+
+	prompts := h.p.Pending()
+
+	resp := make([]promptResponse, len(prompts))
+	for i, p := range prompts {
+		resp[i] = promptResponse{
+			Id:        p.ID,
+			Route:     p.Route,
+			Prompt:    p.Prompt,
+			Options:   p.Options,
+			Default:   p.Default,
+			CreatedAt: p.CreatedAt.Format("2006-01-02 15:04:05"),
+			Deadline:  p.Deadline.Format("2006-01-02 15:04:05"),
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// 선택지 요청 응답. telegram 버튼 대신 사용
+func (h *PromptHandler) Answer(c *fiber.Ctx) error {
+
+	var param AnswerPromptReq
+	err := c.BodyParser(&param)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
+	}
+
+	err = validCheck(&param)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
+	}
+
+	err = h.p.Answer(c.Params("id"), param.Answer)
+	if errors.Is(err, notify.ErrPromptNotFound) {
+		return apierr.NotFound(err)
+	}
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("Answer 시 오류 발생. %w", err))
+	}
+	addAudit(c, audit.Entry{Action: m.AuditUpdate, Entity: "prompt", EntityID: c.Params("id"), After: param})
+
+	return c.Status(fiber.StatusOK).SendString("응답 완료")
+}
+
+// 선택지 요청 취소
+func (h *PromptHandler) Cancel(c *fiber.Ctx) error {
+
+	err := h.p.Cancel(c.Params("id"))
+	if err != nil {
+		return apierr.NotFound(fmt.Errorf("Cancel 시 오류 발생. %w", err))
+	}
+	addAudit(c, audit.Entry{Action: m.AuditDelete, Entity: "prompt", EntityID: c.Params("id")})
+
+	return c.Status(fiber.StatusOK).SendString("취소 완료")
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProviderHandler struct {
+	r ProviderStatusRetriever
+}
+
+func NewProviderHandler(r ProviderStatusRetriever) *ProviderHandler {
+	return &ProviderHandler{
+		r: r,
+	}
+}
+
+func (h *ProviderHandler) InitRoute(r fiber.Router) {
+	router := r.Group("/providers")
+	router.Get("/", h.ProviderStatus)
+}
+
+// 시세 제공처별 상태. 연속 실패로 unhealthy 상태인 제공처는 fallback 체인에서 후순위로 밀림
+func (h *ProviderHandler) ProviderStatus(c *fiber.Ctx) error {
+
+	status := h.r.ProviderStatus()
+
+	resp := make([]providerStatusResponse, len(status))
+	for i, s := range status {
+		resp[i] = providerStatusResponse{
+			Provider:    string(s.Provider),
+			Healthy:     s.Healthy,
+			Failures:    s.Failures,
+			LastError:   s.LastError,
+			LastSuccess: formatTime(s.LastSuccess),
+			LastFailure: formatTime(s.LastFailure),
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
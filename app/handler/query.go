@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"fmt"
+	"investindicator/app/apierr"
+	"investindicator/app/middleware"
+	m "investindicator/internal/model"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+
+	TotalCountHeader = "X-Total-Count"
+	NextCursorHeader = "X-Next-Cursor"
+)
+
+/*
+pageParam query: limit(최대 1000), offset, cursor. cursor 지정 시 offset 무시
+  - limit 미지정 시 /v1은 100건, 기존 경로는 전체 조회
+  - memo. 기존 경로는 전체 목록을 받던 클라이언트 대비 페이지 query를 지정한 경우에만 페이지 적용
+*/
+func pageParam(c *fiber.Ctx) (m.Page, error) {
+	limit := 0
+	if middleware.IsV1(c) {
+		limit = defaultLimit
+	}
+	if c.Query("limit") != "" {
+		limit = c.QueryInt("limit", 0)
+		if limit <= 0 {
+			return m.Page{}, apierr.BadRequest(fmt.Errorf("파라미터 limit 오류. 입력 값 : %s", c.Query("limit")))
+		}
+	}
+	offset := c.QueryInt("offset", 0)
+	if limit > maxLimit || offset < 0 {
+		return m.Page{}, apierr.BadRequest(fmt.Errorf("파라미터 limit, offset 오류. 입력 값 : %d, %d", limit, offset))
+	}
+	return m.Page{Limit: limit, Offset: offset, Cursor: c.Query("cursor")}, nil
+}
+
+// legacyList 기존 경로의 페이지 query 미지정 요청. 기존과 같이 전체 목록을 기존 순서로 응답
+func legacyList(c *fiber.Ctx) bool {
+	return !middleware.IsV1(c) && c.Query("limit") == "" && c.Query("offset") == "" && c.Query("cursor") == ""
+}
+
+// setPage 응답 body는 목록 그대로 두고 전체 건수, 다음 cursor는 header로 전달
+func setPage(c *fiber.Ctx, total int64, next string) {
+	c.Set(TotalCountHeader, strconv.FormatInt(total, 10))
+	if next != "" {
+		c.Set(NextCursorHeader, next)
+	}
+}
+
+// pageSlice 메모리 목록 페이지. cursor는 다음 offset
+func pageSlice[T any](items []T, page m.Page) ([]T, string, error) {
+	offset := page.Offset
+	if page.Cursor != "" {
+		n, err := strconv.Atoi(page.Cursor)
+		if err != nil || n < 0 {
+			return nil, "", apierr.BadRequest(fmt.Errorf("파라미터 cursor 오류. 입력 값 : %s", page.Cursor))
+		}
+		offset = n
+	}
+
+	items = items[min(offset, len(items)):]
+	if page.Limit > 0 && len(items) > page.Limit {
+		return items[:page.Limit], strconv.Itoa(offset + page.Limit), nil
+	}
+	return items, "", nil
+}
+
+var dateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+/*
+dateParam 일자 query 변환. 미입력 시 zero time
+  - 일자만 입력한 end는 해당 일자 포함(다음 날 0시 미만)
+  - memo. time.Time 문자열을 그대로 보내는 기존 클라이언트 대비 앞 10자리(일자)로 재시도
+*/
+func dateParam(c *fiber.Ctx, key string, end bool) (time.Time, error) {
+	s := c.Query(key)
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range dateLayouts {
+		t, err := time.ParseInLocation(layout, s, time.Local)
+		if err == nil {
+			if end && layout == "2006-01-02" {
+				t = t.AddDate(0, 0, 1)
+			}
+			return t, nil
+		}
+	}
+	if len(s) > 10 {
+		t, err := time.ParseInLocation("2006-01-02", s[:10], time.Local)
+		if err == nil {
+			if end {
+				t = t.AddDate(0, 0, 1)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, apierr.BadRequest(fmt.Errorf("파라미터 %s 일자 변환 시 오류 발생. 입력 값 : %s", key, s))
+}
+
+// investQuery query: fund_id, asset_id, category, side(buy|sell), min_amount, max_amount, start, end, sort, 페이지
+func investQuery(c *fiber.Ctx) (m.InvestQuery, error) {
+	var q m.InvestQuery
+	var err error
+
+	fundId := c.QueryInt("fund_id", 0)
+	assetId := c.QueryInt("asset_id", 0)
+	if fundId < 0 || assetId < 0 {
+		return q, apierr.BadRequest(fmt.Errorf("파라미터 fund_id, asset_id 오류. 입력 값 : %d, %d", fundId, assetId))
+	}
+	q.FundID, q.AssetID = uint(fundId), uint(assetId)
+
+	if category := c.Query("category"); category != "" {
+		q.Category, err = m.ToCategory(category)
+		if err != nil {
+			return q, apierr.BadRequest(fmt.Errorf("파라미터 category 변환 시 오류 발생. %w", err))
+		}
+	}
+	q.Side, err = m.ToSide(c.Query("side"))
+	if err != nil {
+		return q, apierr.BadRequest(err)
+	}
+
+	q.MinAmount = c.QueryFloat("min_amount", 0)
+	q.MaxAmount = c.QueryFloat("max_amount", 0)
+	if q.MinAmount < 0 || q.MaxAmount < 0 || (q.MaxAmount > 0 && q.MinAmount > q.MaxAmount) {
+		return q, apierr.BadRequest(fmt.Errorf("파라미터 min_amount, max_amount 오류. 입력 값 : %f, %f", q.MinAmount, q.MaxAmount))
+	}
+
+	q.Start, err = dateParam(c, "start", false)
+	if err != nil {
+		return q, err
+	}
+	q.End, err = dateParam(c, "end", true)
+	if err != nil {
+		return q, err
+	}
+
+	q.Sort, err = m.ParseSort(c.Query("sort"), m.InvestSortFields)
+	if err != nil {
+		return q, apierr.BadRequest(err)
+	}
+	if q.Sort.Field == "" && legacyList(c) {
+		q.Sort = m.Sort{Field: "id"} // 등록 순
+	}
+	q.Page, err = pageParam(c)
+	if err != nil {
+		return q, err
+	}
+
+	q.Funds = visibleFunds(c)
+	return q, nil
+}
+
+// assetQuery query: category, currency, q(이름, 코드 부분 일치), sort, 페이지
+func assetQuery(c *fiber.Ctx) (m.AssetQuery, error) {
+	var q m.AssetQuery
+	var err error
+
+	if category := c.Query("category"); category != "" {
+		q.Category, err = m.ToCategory(category)
+		if err != nil {
+			return q, apierr.BadRequest(fmt.Errorf("파라미터 category 변환 시 오류 발생. %w", err))
+		}
+	}
+	q.Currency = c.Query("currency")
+	q.Keyword = c.Query("q")
+
+	q.Sort, err = m.ParseSort(c.Query("sort"), m.AssetSortFields)
+	if err != nil {
+		return q, apierr.BadRequest(err)
+	}
+	q.Page, err = pageParam(c)
+	if err != nil {
+		return q, err
+	}
+	return q, nil
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"investindicator/app/middleware"
+	m "investindicator/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestListQuery(t *testing.T) {
+
+	now := time.Now()
+	readerMock := &FundRetrieverMock{
+		il: []m.Invest{
+			{ID: 1, FundID: 1, AssetID: 1, Price: 7800, Count: 5, Model: gorm.Model{CreatedAt: now}},
+			{ID: 2, FundID: 1, AssetID: 2, Price: 7800, Count: 3, Model: gorm.Model{CreatedAt: now.AddDate(0, 0, -1)}},
+			{ID: 3, FundID: 1, AssetID: 1, Price: 7800, Count: -2, Model: gorm.Model{CreatedAt: now.AddDate(0, -1, 0)}},
+			{ID: 4, FundID: 2, AssetID: 1, Price: 7800, Count: 1, Model: gorm.Model{CreatedAt: now}},
+		},
+	}
+
+	app := fiber.New()
+	NewFundHandler(readerMock, &FundWriterMock{}, &InvestRetrieverMock{}, &ExchageRateGetterMock{}, &InvestStatusIndicatorMock{}).InitRoute(app)
+
+	request := func(path string) (int, []HistResponse, http.Header) {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		assert.NoError(t, err)
+		var hists []HistResponse
+		if resp.StatusCode == fiber.StatusOK {
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&hists))
+		}
+		return resp.StatusCode, hists, resp.Header
+	}
+
+	t.Run("Page", func(t *testing.T) {
+		code, hists, headers := request("/funds/1/hist?limit=2")
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Len(t, hists, 2)
+		assert.Equal(t, "3", headers.Get(TotalCountHeader))
+		assert.NotEmpty(t, headers.Get(NextCursorHeader))
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		code, hists, headers := request("/funds/1/hist?start=" + now.AddDate(0, 0, -7).Format("2006-01-02"))
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Len(t, hists, 2)
+		assert.Equal(t, "2", headers.Get(TotalCountHeader))
+		assert.Empty(t, headers.Get(NextCursorHeader))
+	})
+
+	t.Run("Legacy", func(t *testing.T) {
+		code, hists, headers := request("/funds/1/hist")
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Len(t, hists, 3)
+		assert.Empty(t, headers.Get(NextCursorHeader))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, path := range []string{
+			"/funds/1/hist?limit=5000",
+			"/funds/1/hist?limit=0",
+			"/funds/1/hist?sort=fund_id",
+			"/funds/1/hist?side=hold",
+			"/funds/1/hist?start=yesterday",
+			"/funds/1/hist?min_amount=10&max_amount=1",
+		} {
+			code, _, _ := request(path)
+			assert.NotEqual(t, fiber.StatusOK, code, path)
+		}
+	})
+}
+
+func TestPageParam(t *testing.T) {
+
+	app := fiber.New()
+	for _, r := range []fiber.Router{app.Group(middleware.V1Prefix), app.Group("")} {
+		r.Get("/invests", func(c *fiber.Ctx) error {
+			q, err := investQuery(c)
+			if err != nil {
+				return err
+			}
+			return c.JSON(q)
+		})
+	}
+
+	request := func(path string) m.InvestQuery {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode, path)
+		var q m.InvestQuery
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&q))
+		return q
+	}
+
+	q := request("/v1/invests")
+	assert.Equal(t, defaultLimit, q.Limit)
+	assert.Empty(t, q.Sort.Field)
+
+	q = request("/invests")
+	assert.Equal(t, 0, q.Limit)
+	assert.Equal(t, m.Sort{Field: "id"}, q.Sort)
+
+	q = request("/invests?offset=10")
+	assert.Equal(t, 0, q.Limit)
+	assert.Equal(t, 10, q.Offset)
+	assert.Empty(t, q.Sort.Field)
+
+	q = request("/invests?limit=20&sort=-amount")
+	assert.Equal(t, 20, q.Limit)
+	assert.Equal(t, m.Sort{Field: "amount", Desc: true}, q.Sort)
+}
+
+func TestPageSlice(t *testing.T) {
+
+	items := []int{1, 2, 3, 4, 5}
+
+	page, next, err := pageSlice(items, m.Page{Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, page)
+
+	page, next, err = pageSlice(items, m.Page{Limit: 2, Cursor: next})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, page)
+
+	page, next, err = pageSlice(items, m.Page{Limit: 2, Cursor: next})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5}, page)
+	assert.Empty(t, next)
+
+	_, _, err = pageSlice(items, m.Page{Cursor: "abc"})
+	assert.Error(t, err)
+}
@@ -1,26 +1,38 @@
 package handler
 
 import (
+	"context"
 	investind "investindicator"
+	"investindicator/internal/audit"
+	"investindicator/internal/cache"
+	"investindicator/internal/export"
+	"investindicator/internal/importer"
 	m "investindicator/internal/model"
+	"investindicator/internal/session"
+	"investindicator/notify"
+	"io"
 	"time"
 )
 
 type FundRetriever interface {
 	RetreiveFundsSummaryOrderByFundId() ([]m.InvestSummary, error)
 	RetreiveFundSummaryByFundId(id uint) ([]m.InvestSummary, error)
-	RetreiveFundInvestsById(id uint) ([]m.Invest, error)
-	RetrieveFundInvestsByIdAndRange(id uint, start, end string) ([]m.Invest, error)
+	InvestLister
 }
 
+// memo. db.Storage가 구현. 자금 권한은 FundMember
 type FundWriter interface {
-	SaveFund(name string) error
+	SaveFund(name string) (uint, error)
+	FundMembers(fundId uint) ([]m.FundMember, error)
+	SaveFundMember(fundId uint, userId int, role m.FundRole) error
+	DeleteFundMember(fundId uint, userId int) error
 }
 
 type AssetRetriever interface {
 	RetrieveAssetList() ([]m.Asset, error)
+	RetrieveAssets(q m.AssetQuery) (*m.List[m.Asset], error)
 	RetrieveAsset(id uint) (*m.Asset, error)
-	RetrieveAssetHist(id uint) ([]m.Invest, error)
+	InvestLister
 	RetrieveAssetIdByName(name string) uint
 	RetrieveAssetIdByCode(code string) uint
 	RetreiveLatestEma(assetId uint) (*m.EmaHist, error)
@@ -36,7 +48,16 @@ type AssetInfoSaver interface {
 type PriceGetter interface {
 	TopBottomPrice(category m.Category, code string) (float64, float64, error)
 	AvgPrice(category m.Category, code string) (ap float64, n uint, err error)
-	PresentPrice(category m.Category, code string) (float64, error)
+	PresentPrice(category m.Category, code string, providers ...m.Provider) (float64, error)
+}
+
+// memo. quote.Cache가 구현. maxAge 이내 조회된 시세는 캐시 값 사용
+type QuoteGetter interface {
+	Quote(category m.Category, code string, maxAge time.Duration, providers ...m.Provider) (m.Quote, error)
+}
+
+type ProviderStatusRetriever interface {
+	ProviderStatus() []m.ProviderStatus
 }
 
 type MaketRetriever interface {
@@ -50,6 +71,11 @@ type MarketSaver interface {
 	SaveMarketStatus(status uint) error
 }
 
+// memo. db.Storage가 구현. 조건, 정렬, 페이지 적용 조회
+type InvestLister interface {
+	RetrieveInvests(q m.InvestQuery) (*m.List[m.Invest], error)
+}
+
 type InvestRetriever interface {
 	RetrieveInvestHist(fundId uint, assetId uint, start string, end string) ([]m.Invest, error)
 	// RetrieveInitAmountofAsset(fundId, assetId uint) (float64, error)
@@ -75,8 +101,92 @@ type EventStatusChanger interface {
 	SetEventStatus(id uint, active bool) error
 }
 
+type SuppressedAlertRetriever interface {
+	SuppressedAlerts() []cache.SuppressedAlert
+}
+
+// memo. notify.Prompts가 구현
+type PromptManager interface {
+	Pending() []notify.Prompt
+	Answer(id string, answer string) error
+	Cancel(id string) error
+}
+
+// memo. importer.Importer가 구현
+type InvestImporter interface {
+	Preview(name string, r io.Reader, broker importer.Broker, fundId uint, owner string) (*importer.Batch, error)
+	Batch(id string) (*importer.Batch, bool)
+	Commit(id string, funds map[int]uint, includeDuplicates bool) (int, error)
+	Discard(id string) error
+}
+
+// memo. export.Service가 구현
+type Exporter interface {
+	Ledger(q m.InvestQuery) (*export.Table, error)
+	Holdings(q m.InvestQuery) (*export.Table, error)
+	FundNavs(fundId uint, days int) (*export.Table, error)
+	Indicators(days int) (*export.Table, error)
+}
+
+// memo. chart.Service가 구현
+type ChartRenderer interface {
+	FundNav(fundId uint, days int) ([]byte, error)
+	Asset(assetId uint, days int) ([]byte, error)
+	Indicators(days int) ([]byte, error)
+	Premium(assetId uint, days int) ([]byte, error)
+}
+
 type UserRetrierver interface {
 	User(userName string) (*m.User, error)
+	UserByTelegramId(telegramId int64) (*m.User, error)
+	RetrieveFundRolesOfUser(userId int) (map[uint]m.FundRole, error)
+}
+
+// memo. password는 bcrypt hash
+type UserManager interface {
+	User(userName string) (*m.User, error)
+	UserById(id int) (*m.User, error)
+	Users() ([]m.User, error)
+	CreateUser(user *m.User) error
+	UpdateUser(user *m.User) error
+	UpdatePassword(userId int, hash string) error
+	DeleteUser(userId int) error
+}
+
+// memo. apikey.Manager가 구현
+type ApiKeyManager interface {
+	Issue(name string, scopes []m.Scope, expiresAt *time.Time) (*m.ApiKey, string, error)
+	Verify(raw string) (*m.ApiKey, error)
+	Keys() ([]m.ApiKey, error)
+	Revoke(id uint) error
+}
+
+// memo. push.Hub가 구현
+type PushSubscriber interface {
+	Subscribe(buf int, topics ...m.Topic) (<-chan m.Push, func())
+}
+
+// memo. db.Storage(MySQL, Redis), scrape.Scraper(KIS 토큰), InvestIndicator(stream, 이벤트), bot.TeleBotGroup(telegram)이 구현
+type HealthReporter interface {
+	Health(ctx context.Context) []m.ComponentHealth
+}
+
+// memo. audit.Recorder가 구현
+type AuditRecorder interface {
+	Record(actor m.Actor, entries ...audit.Entry)
+	Logs(q m.AuditQuery) (*m.List[m.AuditLog], error)
+}
+
+// memo. session.Manager가 구현
+type SessionStore interface {
+	Create(userId int) (*session.Token, error)
+	Refresh(refresh string) (*session.Token, error)
+	Active(userId int, id string) bool
+	Revoke(userId int, id string)
+	RevokeUser(userId int)
+	Locked(username string) (time.Time, bool)
+	Failed(username string) bool
+	Reset(username string)
 }
 
 type InvestStatusIndicator interface {
@@ -1,12 +1,17 @@
 package handler
 
 import (
+	"context"
 	"fmt"
+	"investindicator/internal/audit"
+	"investindicator/internal/importer"
 	m "investindicator/internal/model"
-	"strings"
+	"io"
+	"slices"
 	"time"
 
 	"github.com/kr/pretty"
+	"gorm.io/gorm"
 )
 
 /***************************** Asset ***********************************/
@@ -28,7 +33,11 @@ func (mock PriceGetterMock) AvgPrice(category m.Category, code string) (ap float
 	return 0, 0, nil
 }
 
-func (mock PriceGetterMock) PresentPrice(category m.Category, code string) (float64, error) {
+func (mock PriceGetterMock) Quote(category m.Category, code string, maxAge time.Duration, providers ...m.Provider) (m.Quote, error) {
+	return m.Quote{}, nil
+}
+
+func (mock PriceGetterMock) PresentPrice(category m.Category, code string, providers ...m.Provider) (float64, error) {
 	return 0, nil
 }
 
@@ -57,49 +66,84 @@ func (mock FundRetrieverMock) RetreiveFundSummaryByFundId(id uint) ([]m.InvestSu
 	return mock.isli, nil
 }
 
-func (mock FundRetrieverMock) RetreiveFundInvestsById(id uint) ([]m.Invest, error) {
-	fmt.Println("RetreiveAFundInvestsById Called")
+func (mock FundRetrieverMock) RetrieveInvests(q m.InvestQuery) (*m.List[m.Invest], error) {
+	fmt.Println("RetrieveInvests Called")
 
 	if mock.err != nil {
 		return nil, mock.err
 	}
-	var rtn []m.Invest
-	for _, iv := range mock.il {
-		if iv.FundID == id {
-			rtn = append(rtn, iv)
-		}
-	}
-	return rtn, nil
+	return filterInvests(mock.il, q), nil
 }
-func (mock FundRetrieverMock) RetrieveFundInvestsByIdAndRange(id uint, start, end string) ([]m.Invest, error) {
-	if mock.err != nil {
-		return nil, mock.err
-	}
 
+// filterInvests 자금, 자산, 기간 조건과 offset, limit만 적용
+func filterInvests(il []m.Invest, q m.InvestQuery) *m.List[m.Invest] {
 	var rtn []m.Invest
-	for _, iv := range mock.il {
-		if iv.FundID == id &&
-			strings.Compare(iv.CreatedAt.Format("2006-01-02"), start) == 1 &&
-			strings.Compare(iv.CreatedAt.Format("2006-01-02"), end) == -1 {
-			rtn = append(rtn, iv)
+	for _, iv := range il {
+		if (q.FundID != 0 && iv.FundID != q.FundID) ||
+			(q.AssetID != 0 && iv.AssetID != q.AssetID) ||
+			(q.Funds != nil && !slices.Contains(q.Funds, iv.FundID)) ||
+			(!q.Start.IsZero() && iv.CreatedAt.Before(q.Start)) ||
+			(!q.End.IsZero() && !iv.CreatedAt.Before(q.End)) {
+			continue
 		}
+		rtn = append(rtn, iv)
 	}
-	return rtn, nil
+
+	list := &m.List[m.Invest]{Total: int64(len(rtn))}
+	rtn = rtn[min(q.Offset, len(rtn)):]
+	if q.Limit > 0 && len(rtn) > q.Limit {
+		rtn = rtn[:q.Limit]
+		list.NextCursor = "next"
+	}
+	list.Items = rtn
+	return list
 }
 
 type FundWriterMock struct {
-	err error
+	err     error
+	members []m.FundMember
 }
 
-func (mock FundWriterMock) SaveFund(name string) error {
+func (mock *FundWriterMock) SaveFund(name string) (uint, error) {
 	fmt.Println("SaveFund Called")
 
 	if mock.err != nil {
-		return mock.err
+		return 0, mock.err
 	}
+	return 1, nil
+}
+
+func (mock *FundWriterMock) FundMembers(fundId uint) ([]m.FundMember, error) {
+	rtn := make([]m.FundMember, 0)
+	for _, fm := range mock.members {
+		if fm.FundID == fundId {
+			rtn = append(rtn, fm)
+		}
+	}
+	return rtn, nil
+}
+
+func (mock *FundWriterMock) SaveFundMember(fundId uint, userId int, role m.FundRole) error {
+	for i, fm := range mock.members {
+		if fm.FundID == fundId && fm.UserID == userId {
+			mock.members[i].Role = role
+			return nil
+		}
+	}
+	mock.members = append(mock.members, m.FundMember{FundID: fundId, UserID: userId, Role: role})
 	return nil
 }
 
+func (mock *FundWriterMock) DeleteFundMember(fundId uint, userId int) error {
+	for i, fm := range mock.members {
+		if fm.FundID == fundId && fm.UserID == userId {
+			mock.members = slices.Delete(mock.members, i, i+1)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
 /***************************** Market ***********************************/
 type MaketRetrieverMock struct {
 	err          error
@@ -239,13 +283,28 @@ func (mock AssetRetrieverMock) RetrieveAsset(id uint) (*m.Asset, error) {
 	return nil, fmt.Errorf("asset not found")
 }
 
-func (mock AssetRetrieverMock) RetrieveAssetHist(id uint) ([]m.Invest, error) {
-	fmt.Println("RetrieveAssetHist Called")
+func (mock AssetRetrieverMock) RetrieveAssets(q m.AssetQuery) (*m.List[m.Asset], error) {
+	fmt.Println("RetrieveAssets Called")
 
 	if mock.err != nil {
 		return nil, mock.err
 	}
-	return mock.hist, nil
+	var rtn []m.Asset
+	for _, a := range mock.assets {
+		if q.Category == 0 || a.Category == q.Category {
+			rtn = append(rtn, a)
+		}
+	}
+	return &m.List[m.Asset]{Items: rtn, Total: int64(len(rtn))}, nil
+}
+
+func (mock AssetRetrieverMock) RetrieveInvests(q m.InvestQuery) (*m.List[m.Invest], error) {
+	fmt.Println("RetrieveInvests Called")
+
+	if mock.err != nil {
+		return nil, mock.err
+	}
+	return filterInvests(mock.hist, q), nil
 }
 
 func (mock AssetRetrieverMock) RetrieveAssetIdByName(name string) uint {
@@ -422,3 +481,221 @@ func (mock InvestStatusIndicatorMock) InvestAvailableAmount(fundId int) (float64
 
 	return mock.availableAmount, nil
 }
+
+/***************************** UserRetriever ***********************************/
+type UserRetrieverMock struct {
+	users []m.User
+	funds map[int]map[uint]m.FundRole
+}
+
+func (mock UserRetrieverMock) User(userName string) (*m.User, error) {
+	for _, u := range mock.users {
+		if u.Username == userName {
+			return &u, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func (mock UserRetrieverMock) UserByTelegramId(telegramId int64) (*m.User, error) {
+	for _, u := range mock.users {
+		if u.TelegramId != nil && *u.TelegramId == telegramId {
+			return &u, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func (mock UserRetrieverMock) RetrieveFundRolesOfUser(userId int) (map[uint]m.FundRole, error) {
+	return mock.funds[userId], nil
+}
+
+// ApiKeyManagerMock key 원문 - 키
+type ApiKeyManagerMock struct {
+	keys map[string]m.ApiKey
+}
+
+func (mock ApiKeyManagerMock) Issue(name string, scopes []m.Scope, expiresAt *time.Time) (*m.ApiKey, string, error) {
+	key := m.ApiKey{ID: uint(len(mock.keys) + 1), Name: name, Scopes: m.JoinScopes(scopes), ExpiresAt: expiresAt}
+	raw := fmt.Sprintf("ik_%d.secret", key.ID)
+	mock.keys[raw] = key
+	return &key, raw, nil
+}
+
+func (mock ApiKeyManagerMock) Verify(raw string) (*m.ApiKey, error) {
+	key, ok := mock.keys[raw]
+	if !ok || !key.Active(time.Now()) {
+		return nil, fmt.Errorf("invalid key")
+	}
+	return &key, nil
+}
+
+func (mock ApiKeyManagerMock) Keys() ([]m.ApiKey, error) {
+	keys := make([]m.ApiKey, 0, len(mock.keys))
+	for _, k := range mock.keys {
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func (mock ApiKeyManagerMock) Revoke(id uint) error {
+	for raw, k := range mock.keys {
+		if k.ID == id {
+			now := time.Now()
+			k.RevokedAt = &now
+			mock.keys[raw] = k
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// UserManagerMock 변경 내용을 UserRetrieverMock과 공유
+type UserManagerMock struct {
+	*UserRetrieverMock
+}
+
+func (mock UserManagerMock) UserById(id int) (*m.User, error) {
+	for _, u := range mock.users {
+		if u.ID == id {
+			return &u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (mock UserManagerMock) Users() ([]m.User, error) {
+	return mock.users, nil
+}
+
+func (mock UserManagerMock) CreateUser(user *m.User) error {
+	user.ID = len(mock.users) + 1
+	mock.users = append(mock.users, *user)
+	return nil
+}
+
+func (mock UserManagerMock) UpdateUser(user *m.User) error {
+	for i, u := range mock.users {
+		if u.ID == user.ID {
+			user.Password = u.Password
+			mock.users[i] = *user
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (mock UserManagerMock) UpdatePassword(userId int, hash string) error {
+	for i, u := range mock.users {
+		if u.ID == userId {
+			mock.users[i].Password = hash
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (mock UserManagerMock) DeleteUser(userId int) error {
+	for i, u := range mock.users {
+		if u.ID == userId {
+			mock.users = append(mock.users[:i], mock.users[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+/***************************** Audit ***********************************/
+
+type AuditRecorderMock struct {
+	logs []m.AuditLog
+}
+
+func (mock *AuditRecorderMock) Record(actor m.Actor, entries ...audit.Entry) {
+	for _, e := range entries {
+		mock.logs = append(mock.logs, m.AuditLog{
+			ID:        uint(len(mock.logs) + 1),
+			ActorType: actor.Type,
+			ActorID:   actor.ID,
+			Source:    actor.Source,
+			Action:    e.Action,
+			Entity:    e.Entity,
+			EntityID:  fmt.Sprint(e.EntityID),
+			RequestID: actor.RequestID,
+		})
+	}
+}
+
+func (mock *AuditRecorderMock) Logs(q m.AuditQuery) (*m.List[m.AuditLog], error) {
+	li := make([]m.AuditLog, 0)
+	for _, l := range mock.logs {
+		if q.Entity == "" || l.Entity == q.Entity {
+			li = append(li, l)
+		}
+	}
+	return &m.List[m.AuditLog]{Items: li, Total: int64(len(li))}, nil
+}
+
+/***************************** Push ***********************************/
+
+// PushSubscriberMock 구독 시 pushes 중 구독 topic을 전달하고 채널 종료
+type PushSubscriberMock struct {
+	pushes []m.Push
+}
+
+func (mock PushSubscriberMock) Subscribe(buf int, topics ...m.Topic) (<-chan m.Push, func()) {
+	c := make(chan m.Push, len(mock.pushes))
+	for _, p := range mock.pushes {
+		if slices.Contains(topics, p.Topic) {
+			c <- p
+		}
+	}
+	close(c)
+	return c, func() {}
+}
+
+/***************************** Health ***********************************/
+
+type HealthReporterMock struct {
+	components []m.ComponentHealth
+	delay      time.Duration // 제한 시간을 무시하고 응답 지연
+}
+
+func (mock HealthReporterMock) Health(ctx context.Context) []m.ComponentHealth {
+	time.Sleep(mock.delay)
+	return mock.components
+}
+
+/***************************** Import ***********************************/
+
+type InvestImporterMock struct {
+	batches map[string]*importer.Batch
+}
+
+func (mock *InvestImporterMock) Preview(name string, r io.Reader, broker importer.Broker, fundId uint, owner string) (*importer.Batch, error) {
+	b := &importer.Batch{ID: name, Name: name, Owner: owner, Broker: broker}
+	mock.batches[b.ID] = b
+	return b, nil
+}
+
+func (mock *InvestImporterMock) Batch(id string) (*importer.Batch, bool) {
+	b, ok := mock.batches[id]
+	return b, ok
+}
+
+func (mock *InvestImporterMock) Commit(id string, funds map[int]uint, includeDuplicates bool) (int, error) {
+	b, ok := mock.batches[id]
+	if !ok {
+		return 0, importer.ErrBatchNotFound
+	}
+	delete(mock.batches, id)
+	return len(b.Rows), nil
+}
+
+func (mock *InvestImporterMock) Discard(id string) error {
+	if _, ok := mock.batches[id]; !ok {
+		return importer.ErrBatchNotFound
+	}
+	delete(mock.batches, id)
+	return nil
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"investindicator/app/apierr"
+	m "investindicator/internal/model"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// keepAlive 연결 유지용 주석 전송 주기. proxy의 idle timeout보다 짧게 유지
+const keepAlive = 20 * time.Second
+
+// StreamHandler 알림, 체결, 이벤트 실행 결과, 시세 등 실시간 push(Server-Sent Events)
+type StreamHandler struct {
+	sub PushSubscriber
+}
+
+func NewStreamHandler(sub PushSubscriber) *StreamHandler {
+	return &StreamHandler{
+		sub: sub,
+	}
+}
+
+func (h *StreamHandler) InitRoute(r fiber.Router) {
+	r.Get("/stream", h.Stream)
+}
+
+/*
+Stream topic 구독. query: topics(콤마 구분. 미입력 시 prices 제외 전체)
+  - 메시지마다 event: topic, data: {"topic", "data", "time"} 형식으로 전송
+  - 체결(fills)은 조회 권한이 있는 자금만 전달
+  - Blackhole 전략 보고(reports)는 admin 전용. 그 외 사용자는 기본 구독에서 제외하고 명시적으로 구독하면 403
+  - 브라우저 EventSource는 header 지정이 불가하므로 access_token query로도 인증 가능
+*/
+func (h *StreamHandler) Stream(c *fiber.Ctx) error {
+
+	topics, err := m.ParseTopics(c.Query("topics"))
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 topics 변환 시 오류 발생. %w", err))
+	}
+
+	// memo. stream 작성은 handler 반환 이후에 실행되므로 요청 정보는 미리 확인
+	roles, all := streamFunds(c)
+	if !all {
+		if c.Query("topics") != "" && slices.ContainsFunc(topics, m.Topic.AdminOnly) {
+			return forbidden(c)
+		}
+		topics = slices.DeleteFunc(topics, m.Topic.AdminOnly)
+	}
+	visible := func(fundId uint) bool {
+		_, ok := roles[fundId]
+		return all || fundId == 0 || ok
+	}
+
+	ch, cancel := h.sub.Subscribe(64, topics...)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // nginx buffering 해제
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		ticker := time.NewTicker(keepAlive)
+		defer ticker.Stop()
+
+		fmt.Fprint(w, ": connected\n\n")
+		if w.Flush() != nil {
+			return
+		}
+		for {
+			select {
+			case p, ok := <-ch:
+				if !ok {
+					return
+				}
+				if !visible(p.FundID) {
+					continue
+				}
+				b, err := json.Marshal(p)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", p.Topic, b)
+			case <-ticker.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+			if w.Flush() != nil { // 연결 종료
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// streamFunds 조회 가능한 자금. admin, 사용자 위임 없는 API 키는 전체
+func streamFunds(c *fiber.Ctx) (map[uint]m.FundRole, bool) {
+	claims, ok := c.Locals(claimsKey).(*Claims)
+	if !ok || claims.IsAdmin {
+		return nil, true
+	}
+	return claims.Funds, false
+}
+
+// isStream 실시간 push 구독 요청
+func isStream(c *fiber.Ctx) bool {
+	return c.Method() == fiber.MethodGet && pathSegment(c) == "stream"
+}
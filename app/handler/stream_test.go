@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"investindicator/app/apierr"
+	m "investindicator/internal/model"
+	"investindicator/internal/session"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamHandler(t *testing.T) {
+
+	hash, _ := hashPassword("password1")
+	users := &UserRetrieverMock{
+		users: []m.User{{ID: 1, Username: "viewer", Password: hash}},
+		funds: map[int]map[uint]m.FundRole{1: {1: m.FundViewer}},
+	}
+	pushes := PushSubscriberMock{pushes: []m.Push{
+		{Topic: m.TopicAlert, Data: m.PriceAlert{AssetID: 3, Name: "BTC", Side: m.Buy}},
+		{Topic: m.TopicFill, FundID: 1, Data: m.Fill{FundID: 1, Code: "BTC"}},
+		{Topic: m.TopicFill, FundID: 2, Data: m.Fill{FundID: 2, Code: "ETH"}},
+		{Topic: m.TopicPrice, Data: m.PriceTick{Code: "BTC"}},
+		{Topic: m.TopicReport, Data: "LP position rebalanced"},
+	}}
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			e := apierr.From(err)
+			return c.Status(e.Status()).SendString(e.Message)
+		},
+	})
+	NewAuthHandler(users, UserManagerMock{users}, session.NewManager(nil), ApiKeyManagerMock{}, "authkey").InitRoute(app)
+	NewStreamHandler(pushes).InitRoute(app)
+
+	request := func(path string) (int, string) {
+		req := httptest.NewRequest("GET", path, nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(`{"username":"viewer","password":"password1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	var tk JWTResponse
+	json.NewDecoder(resp.Body).Decode(&tk)
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		code, _ := request("/stream")
+		assert.Equal(t, fiber.StatusUnauthorized, code)
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		code, body := request("/stream?access_token=" + tk.Token)
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Contains(t, body, "event: alerts\n")
+		assert.Contains(t, body, `"code":"BTC"`)
+		assert.NotContains(t, body, "ETH", "조회 권한 없는 자금의 체결 미전달")
+		assert.NotContains(t, body, "event: prices", "prices는 명시적으로 구독")
+		assert.NotContains(t, body, "event: reports", "reports는 admin 전용")
+
+		_, body = request(fmt.Sprintf("/stream?topics=prices&access_token=%s", tk.Token))
+		assert.Contains(t, body, "event: prices\n")
+		assert.NotContains(t, body, "event: alerts")
+	})
+
+	t.Run("Topics", func(t *testing.T) {
+		code, _ := request("/stream?topics=orders&access_token=" + tk.Token)
+		assert.Equal(t, fiber.StatusBadRequest, code)
+
+		code, _ = request("/stream?topics=alerts,reports&access_token=" + tk.Token)
+		assert.Equal(t, fiber.StatusForbidden, code)
+	})
+}
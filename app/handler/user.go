@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"investindicator/app/apierr"
+	"investindicator/internal/audit"
+	m "investindicator/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UserHandler 사용자 관리. admin만 사용 가능. 비밀번호 변경(본인)은 AuthHandler에서 처리
+type UserHandler struct {
+	um UserManager
+	ss SessionStore
+}
+
+func NewUserHandler(um UserManager, ss SessionStore) *UserHandler {
+	return &UserHandler{
+		um: um,
+		ss: ss,
+	}
+}
+
+func (h *UserHandler) InitRoute(r fiber.Router) {
+
+	router := r.Group("/users")
+	router.Get("/", h.Users)
+	router.Post("/", h.AddUser)
+	router.Get("/:id<\\d+>", h.User)
+	router.Put("/:id<\\d+>", h.UpdateUser)
+	router.Delete("/:id<\\d+>", h.DeleteUser)
+}
+
+func (h *UserHandler) Users(c *fiber.Ctx) error {
+
+	if !isAdmin(c) {
+		return forbidden(c)
+	}
+
+	users, err := h.um.Users()
+	if err != nil {
+		return fmt.Errorf("Users 시 오류 발생. %w", err)
+	}
+
+	resp := make([]UserResponse, 0, len(users))
+	for _, u := range users {
+		resp = append(resp, userResponse(u))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+func (h *UserHandler) User(c *fiber.Ctx) error {
+
+	if !isAdmin(c) {
+		return forbidden(c)
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
+	}
+
+	user, err := h.um.UserById(id)
+	if err != nil {
+		return fmt.Errorf("UserById 시 오류 발생. %w", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(userResponse(*user))
+}
+
+func (h *UserHandler) AddUser(c *fiber.Ctx) error {
+
+	var req AddUserReq
+	err := c.BodyParser(&req)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
+	}
+
+	err = validCheck(&req)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
+	}
+
+	if _, err := h.um.User(req.Username); err == nil {
+		return apierr.Conflict(fmt.Errorf("이미 존재하는 사용자. 입력 값 : %s", req.Username))
+	}
+
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		return err
+	}
+
+	user := m.User{
+		Username:   req.Username,
+		Email:      req.Email,
+		Password:   hash,
+		IsAdmin:    req.IsAdmin,
+		TelegramId: req.TelegramId,
+	}
+	err = h.um.CreateUser(&user)
+	if err != nil {
+		return fmt.Errorf("CreateUser 시 오류 발생. %w", err)
+	}
+	addAudit(c, audit.Entry{Action: m.AuditCreate, Entity: "user", EntityID: user.ID, After: userResponse(user)})
+
+	return c.Status(fiber.StatusOK).JSON(userResponse(user))
+}
+
+/*
+UpdateUser 사용자 정보 갱신
+  - 비밀번호 초기화, 비활성화, 권한 변경 시 기존 세션 폐기. 발급된 token의 권한 정보가 달라지기 때문
+  - 본인 계정 비활성화, admin 권한 해제 불가
+*/
+func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
+	}
+
+	var req UpdateUserReq
+	err = c.BodyParser(&req)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 BodyParse 시 오류 발생. %w", err))
+	}
+
+	err = validCheck(&req)
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
+	}
+
+	if isSelf(c, id) && ((req.Disabled != nil && *req.Disabled) || (req.IsAdmin != nil && !*req.IsAdmin)) {
+		return apierr.BadRequest(errors.New("본인 계정은 비활성화, 권한 해제 불가"))
+	}
+
+	user, err := h.um.UserById(id)
+	if err != nil {
+		return fmt.Errorf("UserById 시 오류 발생. %w", err)
+	}
+
+	before := userResponse(*user)
+	revoke := req.Password != ""
+	if req.Email != nil {
+		user.Email = *req.Email
+	}
+	if req.IsAdmin != nil {
+		revoke = revoke || user.IsAdmin != *req.IsAdmin
+		user.IsAdmin = *req.IsAdmin
+	}
+	if req.TelegramId != nil {
+		user.TelegramId = req.TelegramId
+	}
+	if req.Disabled != nil {
+		revoke = revoke || *req.Disabled
+		user.Disabled = *req.Disabled
+	}
+
+	err = h.um.UpdateUser(user)
+	if err != nil {
+		return fmt.Errorf("UpdateUser 시 오류 발생. %w", err)
+	}
+
+	if req.Password != "" {
+		hash, err := hashPassword(req.Password)
+		if err != nil {
+			return err
+		}
+		err = h.um.UpdatePassword(user.ID, hash)
+		if err != nil {
+			return fmt.Errorf("UpdatePassword 시 오류 발생. %w", err)
+		}
+	}
+
+	if revoke {
+		h.ss.RevokeUser(user.ID)
+	}
+	after := fiber.Map{"user": userResponse(*user), "password_reset": req.Password != ""} // memo. 비밀번호는 초기화 여부만 기록
+	addAudit(c, audit.Entry{Action: m.AuditUpdate, Entity: "user", EntityID: user.ID, Before: before, After: after})
+
+	return c.Status(fiber.StatusOK).JSON(userResponse(*user))
+}
+
+func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return apierr.BadRequest(fmt.Errorf("파라미터 id 조회 시 오류 발생. %w", err))
+	}
+
+	if isSelf(c, id) {
+		return apierr.BadRequest(errors.New("본인 계정은 삭제 불가"))
+	}
+
+	user, err := h.um.UserById(id)
+	if err != nil {
+		return fmt.Errorf("UserById 시 오류 발생. %w", err)
+	}
+
+	err = h.um.DeleteUser(id)
+	if err != nil {
+		return fmt.Errorf("DeleteUser 시 오류 발생. %w", err)
+	}
+	h.ss.RevokeUser(id)
+	addAudit(c, audit.Entry{Action: m.AuditDelete, Entity: "user", EntityID: id, Before: userResponse(*user)})
+
+	return c.Status(fiber.StatusOK).SendString("사용자 삭제 성공")
+}
+
+// isSelf 요청 사용자 본인 여부. API 키 요청은 false
+func isSelf(c *fiber.Ctx, userId int) bool {
+	claims, ok := c.Locals(claimsKey).(*Claims)
+	return ok && claims.UserID == userId
+}
+
+func userResponse(u m.User) UserResponse {
+	return UserResponse{
+		ID:         u.ID,
+		Username:   u.Username,
+		Email:      u.Email,
+		Role:       string(u.Role()),
+		TelegramId: u.TelegramId,
+		Disabled:   u.Disabled,
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"investindicator/app/apierr"
+	m "investindicator/internal/model"
+	"investindicator/internal/session"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserHandler(t *testing.T) {
+
+	hash, _ := hashPassword("password1")
+	users := &UserRetrieverMock{
+		users: []m.User{
+			{ID: 1, Username: "admin", Password: hash, IsAdmin: true},
+			{ID: 2, Username: "viewer", Password: hash},
+		},
+	}
+	sessions := session.NewManager(nil)
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			e := apierr.From(err)
+			return c.Status(e.Status()).SendString(e.Message)
+		},
+	})
+	NewAuthHandler(users, UserManagerMock{users}, sessions, ApiKeyManagerMock{}, "authkey").InitRoute(app)
+	NewUserHandler(UserManagerMock{users}, sessions).InitRoute(app)
+
+	request := func(method, path, token, body string) (int, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+	login := func(username, password string) string {
+		_, body := request("POST", "/login", "", fmt.Sprintf(`{"username":"%s","password":"%s"}`, username, password))
+		var tk JWTResponse
+		json.Unmarshal([]byte(body), &tk)
+		return tk.Token
+	}
+
+	admin, viewer := login("admin", "password1"), login("viewer", "password1")
+
+	t.Run("Viewer", func(t *testing.T) {
+		code, _ := request("GET", "/users", viewer, "")
+		assert.Equal(t, fiber.StatusForbidden, code)
+		code, _ = request("POST", "/users", viewer, `{"username":"new","password":"password1"}`)
+		assert.Equal(t, fiber.StatusForbidden, code)
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		code, body := request("POST", "/users", admin, `{"username":"new","password":"password1"}`)
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.NotContains(t, body, "password")
+		assert.Contains(t, body, `"role":"viewer"`)
+
+		code, _ = request("POST", "/users", admin, `{"username":"new","password":"password1"}`)
+		assert.Equal(t, fiber.StatusConflict, code)
+
+		assert.NotEmpty(t, login("new", "password1"), "bcrypt hash 저장")
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		code, body := request("PUT", "/users/2", admin, `{"disabled":true}`)
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Contains(t, body, `"disabled":true`)
+
+		code, _ = request("GET", "/users/2", viewer, "")
+		assert.Equal(t, fiber.StatusUnauthorized, code, "비활성화 시 세션 폐기")
+		assert.Empty(t, login("viewer", "password1"))
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		code, _ := request("PUT", "/users/1", admin, `{"is_admin":false}`)
+		assert.Equal(t, fiber.StatusBadRequest, code)
+		code, _ = request("DELETE", "/users/1", admin, "")
+		assert.Equal(t, fiber.StatusBadRequest, code)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		code, _ := request("DELETE", "/users/3", admin, "")
+		assert.Equal(t, fiber.StatusOK, code)
+		code, _ = request("GET", "/users/3", admin, "")
+		assert.Equal(t, fiber.StatusNotFound, code)
+	})
+}
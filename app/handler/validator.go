@@ -3,8 +3,10 @@ package handler
 import (
 	"errors"
 	"fmt"
+	"investindicator/app/apierr"
 	"investindicator/internal/model"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -37,12 +39,29 @@ func init() {
 
 		return model.IsValidCategory(fl.Field().String())
 	})
+
+	myValidator.RegisterValidation("providers", func(fl validator.FieldLevel) bool {
+		_, err := model.ParseProviders(fl.Field().String())
+		return err == nil
+	})
+
+	myValidator.RegisterValidation("scopes", func(fl validator.FieldLevel) bool {
+		_, err := model.ParseScopes(fl.Field().String())
+		return err == nil
+	})
+
+	myValidator.RegisterValidation("fund_role", func(fl validator.FieldLevel) bool {
+		return slices.Contains(model.FundRoleList(), fl.Field().String())
+	})
 }
 
+// validCheck 필드별 검사 실패 tag는 오류 details로 전달
 func validCheck(s any) error {
 	if errs := validate(s); len(errs) > 0 && errs[0].Error {
 		errMsgs := make([]string, 0)
+		details := make(map[string]string, len(errs))
 		for _, err := range errs {
+			details[err.FailedField] = err.Tag
 			if err.Tag == "required" {
 				errMsgs = append(errMsgs, fmt.Sprintf(
 					"필수 필드 %s 누락",
@@ -56,7 +75,7 @@ func validCheck(s any) error {
 				))
 			}
 		}
-		return errors.New(strings.Join(errMsgs, ", "))
+		return apierr.New(apierr.CodeBadRequest, errors.New(strings.Join(errMsgs, ", "))).WithDetails(details)
 	}
 	return nil
 }
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"encoding/json"
+	"investindicator/app/apierr"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// V1Prefix 응답 envelope, 오류 코드를 적용하는 API 경로. 그 외 경로는 기존 응답 형식을 유지하는 deprecated alias
+const V1Prefix = "/v1"
+
+type errorBody struct {
+	Code    apierr.Code `json:"code"`
+	Message string      `json:"message"`
+	Details any         `json:"details,omitempty"`
+}
+
+type meta struct {
+	Total      *int64 `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+/*
+Envelope /v1 응답 형식 통일
+  - 성공: {"data": 응답, "meta": {"total", "next_cursor"}}. 텍스트 응답은 {"data": {"message": 텍스트}}
+  - 오류: {"error": {"code", "message", "details"}}. 상태 코드는 code에 따름
+  - 차트(png), 내보내기 파일 등 JSON, 텍스트가 아닌 응답은 그대로 전달
+  - memo. 목록 건수, 다음 cursor는 header에도 그대로 남김
+*/
+func Envelope(c *fiber.Ctx) error {
+
+	err := c.Next()
+	if err != nil {
+		e := apierr.From(err)
+		return sendError(c, e.Status(), e)
+	}
+
+	resp := c.Response()
+	if resp.IsBodyStream() { // memo. 실시간 push(SSE) 등 stream 응답은 body를 읽으면 종료까지 대기하므로 그대로 전달
+		return nil
+	}
+	status := resp.StatusCode()
+	body := resp.Body()
+	contentType := string(resp.Header.ContentType())
+
+	if status >= fiber.StatusBadRequest {
+		return sendError(c, status, directError(status, contentType, body))
+	}
+
+	var data any
+	switch {
+	case len(body) == 0:
+	case strings.HasPrefix(contentType, fiber.MIMEApplicationJSON):
+		data = json.RawMessage(append([]byte(nil), body...))
+	case strings.HasPrefix(contentType, fiber.MIMETextPlain):
+		data = fiber.Map{"message": string(body)}
+	default:
+		return nil
+	}
+
+	envelope := fiber.Map{"data": data}
+	if m := pageMeta(c); m != nil {
+		envelope["meta"] = m
+	}
+	return c.Status(status).JSON(envelope)
+}
+
+func sendError(c *fiber.Ctx, status int, e *apierr.Error) error {
+
+	if status >= fiber.StatusInternalServerError {
+		log.Error().Err(e.Cause()).Str("endpoint", c.Path()).Msg("Error in handler")
+	}
+
+	c.Response().Header.Del(fiber.HeaderContentDisposition)
+	return c.Status(status).JSON(fiber.Map{
+		"error": errorBody{Code: e.Code, Message: e.Message, Details: e.Details},
+	})
+}
+
+// directError handler가 오류 상태 코드로 직접 작성한 응답. JSON 응답의 message 혹은 텍스트를 오류 메시지로 사용
+func directError(status int, contentType string, body []byte) *apierr.Error {
+
+	msg := string(body)
+	if strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+		var m struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &m) == nil && m.Message != "" {
+			msg = m.Message
+		}
+	}
+	if msg == "" {
+		msg = fiber.NewError(status).Message
+	}
+	return apierr.FromStatus(status, msg)
+}
+
+func pageMeta(c *fiber.Ctx) *meta {
+
+	var m meta
+	if v := c.GetRespHeader("X-Total-Count"); v != "" {
+		if total, err := strconv.ParseInt(v, 10, 64); err == nil {
+			m.Total = &total
+		}
+	}
+	m.NextCursor = c.GetRespHeader("X-Next-Cursor")
+
+	if m.Total == nil && m.NextCursor == "" {
+		return nil
+	}
+	return &m
+}
+
+// Deprecated /v1 이전 경로 응답에 Deprecation, 후속 경로 Link header 추가
+func Deprecated(c *fiber.Ctx) error {
+	if !IsV1(c) {
+		c.Set("Deprecation", "true")
+		c.Set(fiber.HeaderLink, "<"+V1Prefix+c.Path()+`>; rel="successor-version"`)
+	}
+	return c.Next()
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"investindicator/app/apierr"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type envelope struct {
+	Data  json.RawMessage `json:"data"`
+	Meta  *meta           `json:"meta"`
+	Error *errorBody      `json:"error"`
+}
+
+func TestEnvelope(t *testing.T) {
+
+	app := fiber.New()
+	app.Use(errorHandle)
+
+	routes := func(r fiber.Router) {
+		r.Get("/list", func(c *fiber.Ctx) error {
+			c.Set("X-Total-Count", "3")
+			c.Set("X-Next-Cursor", "2")
+			return c.JSON([]int{1, 2})
+		})
+		r.Get("/text", func(c *fiber.Ctx) error {
+			return c.SendString("저장 성공")
+		})
+		r.Get("/png", func(c *fiber.Ctx) error {
+			c.Type("png")
+			return c.Send([]byte{0x89, 'P', 'N', 'G'})
+		})
+		r.Get("/stream", func(c *fiber.Ctx) error {
+			c.Set(fiber.HeaderContentType, "text/event-stream")
+			c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+				w.WriteString("event: alerts\ndata: {}\n\n")
+			})
+			return nil
+		})
+		r.Get("/invalid", func(c *fiber.Ctx) error {
+			err := apierr.New(apierr.CodeBadRequest, errors.New("필수 필드 Name 누락")).WithDetails(map[string]string{"Name": "required"})
+			return apierr.BadRequest(fmt.Errorf("파라미터 유효성 검사 시 오류 발생. %w", err))
+		})
+		r.Get("/forbidden", func(c *fiber.Ctx) error {
+			return apierr.Forbidden()
+		})
+		r.Get("/missing", func(c *fiber.Ctx) error {
+			return fmt.Errorf("RetrieveAsset 오류 발생. %w", gorm.ErrRecordNotFound)
+		})
+		r.Get("/internal", func(c *fiber.Ctx) error {
+			return errors.New("db connection refused")
+		})
+		r.Get("/direct", func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusNotImplemented).SendString("not yet implemented")
+		})
+	}
+	routes(app.Group(V1Prefix, Envelope))
+	routes(app.Group("", Deprecated))
+
+	request := func(path string) (int, envelope, string, http.Header) {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		var e envelope
+		json.Unmarshal(body, &e)
+		return resp.StatusCode, e, string(body), resp.Header
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		code, e, _, h := request("/v1/list")
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.JSONEq(t, `[1,2]`, string(e.Data))
+		assert.Equal(t, int64(3), *e.Meta.Total)
+		assert.Equal(t, "2", e.Meta.NextCursor)
+		assert.Empty(t, h.Get("Deprecation"))
+
+		code, e, _, _ = request("/v1/text")
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.JSONEq(t, `{"message":"저장 성공"}`, string(e.Data))
+		assert.Nil(t, e.Meta)
+
+		code, _, body, _ := request("/v1/png")
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Equal(t, "\x89PNG", body)
+
+		code, _, body, _ = request("/v1/stream")
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.Equal(t, "event: alerts\ndata: {}\n\n", body)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		for _, c := range []struct {
+			path   string
+			status int
+			code   apierr.Code
+		}{
+			{"/v1/invalid", fiber.StatusBadRequest, apierr.CodeBadRequest},
+			{"/v1/forbidden", fiber.StatusForbidden, apierr.CodeForbidden},
+			{"/v1/missing", fiber.StatusNotFound, apierr.CodeNotFound},
+			{"/v1/internal", fiber.StatusInternalServerError, apierr.CodeInternal},
+			{"/v1/direct", fiber.StatusNotImplemented, apierr.CodeInternal},
+			{"/v1/unknown", fiber.StatusNotFound, apierr.CodeNotFound},
+		} {
+			status, e, _, _ := request(c.path)
+			assert.Equal(t, c.status, status, c.path)
+			if assert.NotNil(t, e.Error, c.path) {
+				assert.Equal(t, c.code, e.Error.Code, c.path)
+				assert.NotEmpty(t, e.Error.Message, c.path)
+			}
+		}
+
+		_, e, _, _ := request("/v1/invalid")
+		assert.Equal(t, map[string]any{"Name": "required"}, e.Error.Details)
+
+		_, e, _, _ = request("/v1/internal")
+		assert.NotContains(t, e.Error.Message, "db connection")
+	})
+
+	t.Run("Deprecated", func(t *testing.T) {
+		code, _, body, h := request("/list")
+		assert.Equal(t, fiber.StatusOK, code)
+		assert.JSONEq(t, `[1,2]`, body)
+		assert.Equal(t, "true", h.Get("Deprecation"))
+		assert.Equal(t, `</v1/list>; rel="successor-version"`, h.Get("Link"))
+
+		code, _, body, _ = request("/forbidden")
+		assert.Equal(t, fiber.StatusForbidden, code)
+		assert.JSONEq(t, `{"message":"Forbidden"}`, body)
+
+		code, _, body, _ = request("/internal")
+		assert.Equal(t, fiber.StatusBadRequest, code)
+		assert.Equal(t, "db connection refused", body)
+	})
+}
@@ -0,0 +1,294 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"investindicator/app/apierr"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog"
+)
+
+const clientIpKey = "client_ip"
+
+// 거부 사유. 거부 요청 metric의 reason label
+const (
+	RejectDenied     = "denied"      // denylist 대역
+	RejectNotAllowed = "not_allowed" // allowlist 외 대역
+	RejectIpLimit    = "ip_limit"
+	RejectUserLimit  = "user_limit"
+	RejectLoginLimit = "login_limit"
+)
+
+var rejectedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "http_rejected_requests_total",
+	Help: "IP 대역, 요청 한도로 거부된 요청 수",
+}, []string{"reason"})
+
+// Limit 기간(Per) 내 최대 요청 수. Max가 0이면 미적용
+type Limit struct {
+	Max int
+	Per time.Duration
+}
+
+// ParseLimit "횟수/기간" 형식. ex) 300/1m
+func ParseLimit(s string) (Limit, error) {
+
+	n, d, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("요청 한도 형식 오류. 입력 값 : %s", s)
+	}
+	max, err := strconv.Atoi(strings.TrimSpace(n))
+	if err != nil {
+		return Limit{}, fmt.Errorf("요청 한도 횟수 변환 시 오류 발생. %w", err)
+	}
+	per, err := time.ParseDuration(strings.TrimSpace(d))
+	if err != nil {
+		return Limit{}, fmt.Errorf("요청 한도 기간 변환 시 오류 발생. %w", err)
+	}
+	if max < 0 || per < time.Second {
+		return Limit{}, fmt.Errorf("요청 한도는 0회 이상, 1초 이상 기간. 입력 값 : %s", s)
+	}
+	return Limit{Max: max, Per: per}, nil
+}
+
+// ParsePrefixes CIDR 목록. 단일 IP는 /32(/128) 대역으로 변환
+func ParsePrefixes(cidrs []string) ([]netip.Prefix, error) {
+
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, s := range cidrs {
+		s = strings.TrimSpace(s)
+		if !strings.Contains(s, "/") {
+			addr, err := netip.ParseAddr(s)
+			if err != nil {
+				return nil, fmt.Errorf("IP 변환 시 오류 발생. %w", err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return nil, fmt.Errorf("CIDR 변환 시 오류 발생. %w", err)
+		}
+		prefixes = append(prefixes, p.Masked())
+	}
+	return prefixes, nil
+}
+
+type GuardOption func(*Guard)
+
+// WithAllow 접근 허용 대역. 미지정 시 전체 허용
+func WithAllow(prefixes ...netip.Prefix) GuardOption {
+	return func(g *Guard) {
+		g.allow = prefixes
+	}
+}
+
+// WithDeny 접근 거부 대역. allowlist보다 우선
+func WithDeny(prefixes ...netip.Prefix) GuardOption {
+	return func(g *Guard) {
+		g.deny = prefixes
+	}
+}
+
+// WithTrustedProxies X-Forwarded-For를 신뢰하는 reverse proxy 대역. 미지정 시 X-Forwarded-For 무시
+func WithTrustedProxies(prefixes ...netip.Prefix) GuardOption {
+	return func(g *Guard) {
+		g.proxies = prefixes
+	}
+}
+
+// WithIpLimit client IP별 요청 한도
+func WithIpLimit(l Limit) GuardOption {
+	return func(g *Guard) {
+		g.ipLimit = l
+	}
+}
+
+// WithLoginLimit client IP별 /login 요청 한도. IP별 요청 한도와 별도로 적용
+func WithLoginLimit(l Limit) GuardOption {
+	return func(g *Guard) {
+		g.loginLimit = l
+	}
+}
+
+// WithUserLimit 요청 주체(사용자, API 키)별 요청 한도. WithUserKey 지정 시에만 적용
+func WithUserLimit(l Limit) GuardOption {
+	return func(g *Guard) {
+		g.userLimit = l
+	}
+}
+
+// WithUserKey 인증 이후 요청 주체 구분 값. 빈 값이면 사용자별 요청 한도 미적용
+func WithUserKey(key func(*fiber.Ctx) string) GuardOption {
+	return func(g *Guard) {
+		g.userKey = key
+	}
+}
+
+/*
+Guard 네트워크 수준 요청 제한
+  - client IP 기준 deny/allow 대역 확인. 신뢰하는 proxy를 거친 요청만 X-Forwarded-For로 client IP 판단
+  - client IP별 요청 한도, /login은 더 낮은 한도 별도 적용
+  - 사용자별 요청 한도는 인증 이후 경로에 등록(InitRoute)
+  - 거부 요청은 사유별 metric(http_rejected_requests_total)으로 집계
+*/
+type Guard struct {
+	allow      []netip.Prefix
+	deny       []netip.Prefix
+	proxies    []netip.Prefix
+	ipLimit    Limit
+	loginLimit Limit
+	userLimit  Limit
+	userKey    func(*fiber.Ctx) string
+	users      fiber.Handler // memo. 여러 group에 등록해도 같은 한도를 공유하도록 한 번만 생성
+	lg         zerolog.Logger
+}
+
+func NewGuard(opts ...GuardOption) *Guard {
+	g := &Guard{
+		lg: zerolog.New(os.Stdout).With().Str("Module", "Guard").Timestamp().Logger(),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.userKey != nil {
+		g.users = g.limiter(g.userLimit, RejectUserLimit, func(c *fiber.Ctx) bool { return g.userKey(c) == "" }, g.userKey)
+	}
+	return g
+}
+
+// Handlers IP 대역 확인, IP별 요청 한도, /login 요청 한도 순으로 적용
+func (g *Guard) Handlers() []fiber.Handler {
+	return []fiber.Handler{
+		g.filter,
+		g.limiter(g.ipLimit, RejectIpLimit, nil, ClientIP),
+		g.limiter(g.loginLimit, RejectLoginLimit, func(c *fiber.Ctx) bool { return !isLogin(c) }, ClientIP),
+	}
+}
+
+// InitRoute 사용자별 요청 한도 등록. 요청 주체 확인을 위해 인증 middleware 이후에 등록
+func (g *Guard) InitRoute(r fiber.Router) {
+	if g.users != nil {
+		r.Use(g.users)
+	}
+}
+
+func (g *Guard) filter(c *fiber.Ctx) error {
+
+	ip := g.clientIP(c)
+	c.Locals(clientIpKey, ip.String())
+
+	if contains(g.deny, ip) {
+		return g.reject(c, RejectDenied, apierr.Forbidden())
+	}
+	if len(g.allow) > 0 && !contains(g.allow, ip) {
+		return g.reject(c, RejectNotAllowed, apierr.Forbidden())
+	}
+	return c.Next()
+}
+
+// limiter 요청 한도 초과 시 429. memo. /v1, 기존 경로 group에 모두 등록되어도 요청당 한 번만 집계
+func (g *Guard) limiter(l Limit, reason string, skip func(*fiber.Ctx) bool, key func(*fiber.Ctx) string) fiber.Handler {
+
+	if l.Max == 0 {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+	counted := "guard_" + reason
+	return limiter.New(limiter.Config{
+		Max:        l.Max,
+		Expiration: l.Per,
+		Next: func(c *fiber.Ctx) bool {
+			if c.Locals(counted) != nil || (skip != nil && skip(c)) {
+				return true
+			}
+			c.Locals(counted, true)
+			return false
+		},
+		KeyGenerator: key,
+		LimitReached: func(c *fiber.Ctx) error {
+			return g.reject(c, reason, apierr.TooMany(errors.New("요청 한도 초과. 잠시 후 재시도")))
+		},
+		LimiterMiddleware: limiter.SlidingWindow{},
+	})
+}
+
+/*
+clientIP 요청 client IP
+  - 직접 연결한 주소가 신뢰하는 proxy일 때만 X-Forwarded-For 사용
+  - X-Forwarded-For는 오른쪽(가까운 proxy)부터 확인하여 신뢰하지 않는 첫 주소를 client로 판단. 왼쪽 값은 client가 조작 가능
+*/
+func (g *Guard) clientIP(c *fiber.Ctx) netip.Addr {
+
+	remote, _ := netip.AddrFromSlice(c.Context().RemoteIP())
+	ip := remote.Unmap()
+	if !contains(g.proxies, ip) {
+		return ip
+	}
+
+	hops := strings.Split(c.Get(fiber.HeaderXForwardedFor), ",")
+	for _, hop := range slices.Backward(hops) {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hop))
+		if err != nil {
+			break
+		}
+		ip = addr.Unmap()
+		if !contains(g.proxies, ip) {
+			break
+		}
+	}
+	return ip
+}
+
+// reject 거부 응답. 전역 middleware라 /v1 envelope이 적용되지 않으므로 경로에 맞는 형식으로 직접 응답
+func (g *Guard) reject(c *fiber.Ctx, reason string, err error) error {
+
+	rejectedRequests.WithLabelValues(reason).Inc()
+	g.lg.Warn().Str("reason", reason).Str("ip", ClientIP(c)).Str("endpoint", c.Path()).Msg("요청 거부")
+
+	e := apierr.From(err)
+	if IsV1(c) {
+		return sendError(c, e.Status(), e)
+	}
+	return c.Status(e.Status()).JSON(fiber.Map{
+		"message": e.Message,
+	})
+}
+
+// ClientIP Guard가 판단한 client IP. Guard 미적용 시 직접 연결한 주소
+func ClientIP(c *fiber.Ctx) string {
+	if ip, ok := c.Locals(clientIpKey).(string); ok {
+		return ip
+	}
+	return c.IP()
+}
+
+func contains(prefixes []netip.Prefix, ip netip.Addr) bool {
+	for _, p := range prefixes {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsV1 envelope 적용 경로 요청 여부. 기존 경로(deprecated alias)는 응답 형식, 기본 동작 유지
+func IsV1(c *fiber.Ctx) bool {
+	path := c.Path()
+	return strings.HasPrefix(path, V1Prefix+"/") || path == V1Prefix
+}
+
+// isLogin 로그인, token 재발급 요청
+func isLogin(c *fiber.Ctx) bool {
+	path := strings.TrimPrefix(c.Path(), V1Prefix)
+	return c.Method() == fiber.MethodPost && (path == "/login" || strings.HasPrefix(path, "/login/"))
+}
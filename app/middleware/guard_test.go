@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuard(t *testing.T) {
+
+	prefixes := func(cidrs ...string) GuardOption {
+		p, err := ParsePrefixes(cidrs)
+		assert.NoError(t, err)
+		return WithTrustedProxies(p...)
+	}
+	allow, _ := ParsePrefixes([]string{"10.0.0.0/8"})
+	deny, _ := ParsePrefixes([]string{"10.0.0.66"})
+
+	// memo. app.Test 요청의 연결 주소는 0.0.0.0. proxy로 신뢰하여 X-Forwarded-For로 client IP 지정
+	guard := NewGuard(
+		prefixes("0.0.0.0", "192.168.0.0/16"),
+		WithAllow(allow...),
+		WithDeny(deny...),
+		WithIpLimit(Limit{Max: 5, Per: time.Minute}),
+		WithLoginLimit(Limit{Max: 2, Per: time.Minute}),
+		WithUserLimit(Limit{Max: 1, Per: time.Minute}),
+		WithUserKey(func(c *fiber.Ctx) string { return c.Get("X-User") }),
+	)
+
+	app := fiber.New()
+	for _, h := range guard.Handlers() {
+		app.Use(h)
+	}
+	for _, r := range []fiber.Router{app.Group(V1Prefix), app.Group("")} {
+		guard.InitRoute(r)
+		r.Get("/ip", func(c *fiber.Ctx) error { return c.SendString(ClientIP(c)) })
+		r.Post("/login", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	}
+
+	request := func(method, path, xff, user string) (int, string) {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set(fiber.HeaderXForwardedFor, xff)
+		req.Header.Set("X-User", user)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+
+	t.Run("ClientIP", func(t *testing.T) {
+		_, ip := request("GET", "/ip", "10.0.0.1", "")
+		assert.Equal(t, "10.0.0.1", ip)
+		_, ip = request("GET", "/ip", "1.2.3.4, 10.0.0.2, 192.168.0.1", "")
+		assert.Equal(t, "10.0.0.2", ip, "신뢰하는 proxy를 제외한 가장 가까운 주소")
+	})
+
+	t.Run("Network", func(t *testing.T) {
+		code, _ := request("GET", "/ip", "8.8.8.8", "")
+		assert.Equal(t, fiber.StatusForbidden, code)
+		code, body := request("GET", "/v1/ip", "10.0.0.66", "")
+		assert.Equal(t, fiber.StatusForbidden, code)
+		var e envelope
+		assert.NoError(t, json.Unmarshal([]byte(body), &e))
+		assert.Equal(t, "FORBIDDEN", string(e.Error.Code), "/v1은 envelope 형식")
+	})
+
+	t.Run("Login", func(t *testing.T) {
+		for range 2 {
+			code, _ := request("POST", "/v1/login", "10.0.0.3", "")
+			assert.Equal(t, fiber.StatusOK, code)
+		}
+		code, _ := request("POST", "/login", "10.0.0.3", "")
+		assert.Equal(t, fiber.StatusTooManyRequests, code)
+		code, _ = request("POST", "/login", "10.0.0.4", "")
+		assert.Equal(t, fiber.StatusOK, code, "IP별 한도")
+	})
+
+	t.Run("User", func(t *testing.T) {
+		code, _ := request("GET", "/v1/ip", "10.0.0.5", "user:1")
+		assert.Equal(t, fiber.StatusOK, code, "/v1, 기존 경로 group 중복 집계 없음")
+		code, _ = request("GET", "/ip", "10.0.0.6", "user:1")
+		assert.Equal(t, fiber.StatusTooManyRequests, code)
+		code, _ = request("GET", "/ip", "10.0.0.6", "user:2")
+		assert.Equal(t, fiber.StatusOK, code)
+	})
+
+	t.Run("Ip", func(t *testing.T) {
+		for range 5 {
+			request("GET", "/ip", "10.0.0.7", "")
+		}
+		code, _ := request("GET", "/ip", "10.0.0.7", "")
+		assert.Equal(t, fiber.StatusTooManyRequests, code)
+	})
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(rejectedRequests.WithLabelValues(RejectNotAllowed))+testutil.ToFloat64(rejectedRequests.WithLabelValues(RejectDenied)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(rejectedRequests.WithLabelValues(RejectUserLimit)))
+
+	_, err := ParseLimit("10")
+	assert.Error(t, err)
+	l, err := ParseLimit("10/1m")
+	assert.NoError(t, err)
+	assert.Equal(t, Limit{Max: 10, Per: time.Minute}, l)
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"investindicator/app/apierr"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "API 요청 수",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "API 요청 처리 시간",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+/*
+observeRequest 요청 수, 처리 시간 집계
+  - route label은 요청 경로가 아닌 등록된 경로(/assets/:id). id별로 label이 늘어나지 않도록
+  - 처리되지 않은 오류는 fiber ErrorHandler가 응답하므로 오류의 상태 코드로 집계
+*/
+func observeRequest(c *fiber.Ctx) error {
+
+	start := time.Now()
+	err := c.Next()
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		status = apierr.From(err).Status()
+	}
+	route := c.Route().Path
+	httpRequests.WithLabelValues(c.Method(), route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(c.Method(), route).Observe(time.Since(start).Seconds())
+	return err
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveRequest(t *testing.T) {
+
+	app := fiber.New()
+	app.Use(observeRequest)
+	app.Use(errorHandle)
+	for _, r := range []fiber.Router{app.Group(V1Prefix), app.Group("")} {
+		r.Get("/assets/:id", func(c *fiber.Ctx) error {
+			if c.Params("id") == "0" {
+				return fiber.ErrNotFound
+			}
+			return c.SendString("ok")
+		})
+	}
+
+	for _, path := range []string{"/assets/1", "/assets/2", "/v1/assets/1", "/assets/0"} {
+		_, err := app.Test(httptest.NewRequest("GET", path, nil))
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(httpRequests.WithLabelValues("GET", "/assets/:id", "200")), "요청 경로가 아닌 등록된 경로로 집계")
+	assert.Equal(t, 1.0, testutil.ToFloat64(httpRequests.WithLabelValues("GET", "/v1/assets/:id", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(httpRequests.WithLabelValues("GET", "/assets/:id", "400")), "errorHandle이 응답한 상태 코드")
+	assert.Equal(t, 2, testutil.CollectAndCount(httpDuration), "처리 시간은 상태 코드 구분 없이 경로별 집계")
+}
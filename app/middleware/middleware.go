@@ -1,22 +1,34 @@
 package middleware
 
 import (
+	"errors"
+	"investindicator/app/apierr"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/rs/zerolog/log"
 )
 
-func SetupMiddleware(router fiber.Router, allowIp []string) {
+// SetupMiddleware guard가 nil이면 IP 대역 확인, 요청 한도 미적용
+func SetupMiddleware(router fiber.Router, allowIp []string, guard *Guard) {
 
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     strings.Join(allowIp, ","), // Production and local dev origins
 		AllowHeaders:     "Origin, Content-Type, Authorization",
 		AllowMethods:     strings.Join([]string{fiber.MethodGet, fiber.MethodPost, fiber.MethodHead, fiber.MethodPut, fiber.MethodDelete, fiber.MethodPatch}, ","),
+		ExposeHeaders:    "X-Total-Count, X-Next-Cursor, Content-Disposition, Deprecation, Link, X-Request-ID", // 목록 페이지, 내보내기 파일명, 기존 경로 안내, 요청 id
 		AllowCredentials: true,
 		MaxAge:           60 * 60 * 1,
 	}))
+	router.Use(requestid.New()) // memo. 클라이언트가 X-Request-ID를 보내면 그대로 사용. 변경 이력(audit)과 로그 연결용
+	router.Use(observeRequest)  // memo. Guard 거부 요청까지 집계하도록 Guard 앞에 등록
+	if guard != nil {
+		for _, h := range guard.Handlers() {
+			router.Use(h)
+		}
+	}
 	router.Use(errorHandle)
 	router.Use(logRequest)
 
@@ -27,13 +39,22 @@ func errorHandle(c *fiber.Ctx) error {
 	err := c.Next()
 	if err != nil {
 		log.Error().Err(err).Msg("Error in middleware")
+
+		// memo. 기존 경로 응답 유지. 인증, 권한, 미존재 등 상태 코드를 가진 오류만 {"message"} JSON으로 응답
+		var e *apierr.Error
+		if errors.As(err, &e) && e.Status() != fiber.StatusBadRequest {
+			return c.Status(e.Status()).JSON(fiber.Map{
+				"message": e.Message,
+			})
+		}
 		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
 	}
 	return nil
 }
 
 func logRequest(c *fiber.Ctx) error {
-	log.Info().Str("endpoint", c.Path()).Msg("Request endpoint")
-	log.Info().Str("body", string(c.Body())).Msg("Request body")
+	id, _ := c.Locals(requestid.ConfigDefault.ContextKey).(string)
+	log.Info().Str("request_id", id).Str("ip", ClientIP(c)).Str("endpoint", c.Path()).Msg("Request endpoint")
+	log.Info().Str("request_id", id).Str("body", string(c.Body())).Msg("Request body")
 	return c.Next()
 }
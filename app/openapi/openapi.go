@@ -0,0 +1,354 @@
+package openapi
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Document OpenAPI 3.0 문서. 생성에 필요한 항목만 정의
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers,omitempty"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem 소문자 method별 operation
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+/*
+Route 경로별 문서 정보. handler의 요청, 응답 타입을 지정
+  - Path: prefix 제외 OpenAPI 형식 경로. ex) /assets/{id}
+  - Body: JSON 요청 body 타입의 zero 값. nil이면 body 없음
+  - Response: JSON 응답 타입의 zero 값. nil이면 텍스트 응답
+  - Produces: JSON이 아닌 응답 content type. ex) image/png
+*/
+type Route struct {
+	Method   string
+	Path     string
+	Summary  string
+	Params   []Param // 경로 parameter 타입 지정. 미지정 시 id는 integer, 그 외 string
+	Query    []Param
+	Form     []Param // multipart/form-data 요청
+	Body     any
+	Optional bool // body 생략 가능
+	Response any
+	Produces []string
+	Public   bool // 인증 불필요
+}
+
+// Param query, form 항목. Type은 JSON schema 타입(string, integer, number, boolean, file)
+type Param struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+	Enum        []string
+}
+
+var (
+	paramPattern     = regexp.MustCompile(`:(\w+)(<[^>]*>)?(\?)?`)
+	pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+)
+
+/*
+paths fiber 경로를 OpenAPI 경로로 변환
+  - :id<\d+> 는 {id}
+  - 선택 parameter(:date?)는 parameter 유무 두 경로로 분리
+  - 끝의 '/' 제거
+*/
+func paths(fiberPath string) []string {
+
+	optional := ""
+	path := paramPattern.ReplaceAllStringFunc(fiberPath, func(s string) string {
+		m := paramPattern.FindStringSubmatch(s)
+		if m[3] != "" {
+			optional = m[1]
+		}
+		return "{" + m[1] + "}"
+	})
+
+	trim := func(p string) string {
+		if len(p) > 1 {
+			p = strings.TrimSuffix(p, "/")
+		}
+		return p
+	}
+
+	if optional == "" {
+		return []string{trim(path)}
+	}
+	return []string{trim(strings.Replace(path, "/{"+optional+"}", "", 1)), trim(path)}
+}
+
+func pathParams(path string) []string {
+	params := make([]string, 0)
+	for _, m := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		params = append(params, m[1])
+	}
+	return params
+}
+
+// Builder Route 정보로 문서 생성. Tags로 validate tag별 schema 제약 지정
+type Builder struct {
+	Info     Info
+	Prefix   string // 문서 대상 경로 prefix. ex) /v1
+	Security map[string]SecurityScheme
+	Tags     map[string]func(*Schema)
+	Envelope bool // 성공 응답을 {"data", "meta"}로 감싸는 경우
+}
+
+/*
+Build fiber에 등록된 경로 중 Prefix 하위 경로로 문서 생성
+  - Route 정보가 없는 경로도 포함. 응답은 텍스트로 간주
+  - HEAD(GET 자동 등록), 미들웨어 경로 제외
+  - 반환한 undocumented는 Route 정보가 없는 "METHOD 경로" 목록
+*/
+func (b Builder) Build(registered []fiber.Route, routes []Route) (doc *Document, undocumented []string) {
+
+	g := newGenerator(b.Tags)
+	g.envelope = b.Envelope
+
+	doc = &Document{
+		OpenAPI: "3.0.3",
+		Info:    b.Info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas:         g.schemas,
+			SecuritySchemes: b.Security,
+		},
+	}
+	if b.Prefix != "" {
+		doc.Servers = []Server{{URL: b.Prefix}}
+	}
+	for name := range b.Security {
+		doc.Security = append(doc.Security, map[string][]string{name: {}})
+	}
+	sort.Slice(doc.Security, func(i, j int) bool {
+		return firstKey(doc.Security[i]) < firstKey(doc.Security[j])
+	})
+
+	docs := make(map[string]Route, len(routes))
+	for _, r := range routes {
+		docs[r.Method+" "+r.Path] = r
+	}
+
+	for _, fr := range registered {
+		if fr.Method == fiber.MethodHead || fr.Method == fiber.MethodConnect || fr.Method == fiber.MethodTrace || fr.Method == fiber.MethodOptions {
+			continue
+		}
+		if b.Prefix != "" && fr.Path != b.Prefix && !strings.HasPrefix(fr.Path, b.Prefix+"/") {
+			continue
+		}
+
+		for _, path := range paths(strings.TrimPrefix(fr.Path, b.Prefix)) {
+			item, ok := doc.Paths[path]
+			if !ok {
+				item = make(PathItem)
+				doc.Paths[path] = item
+			}
+			method := strings.ToLower(fr.Method)
+			if _, ok := item[method]; ok {
+				continue
+			}
+
+			r, ok := docs[fr.Method+" "+path]
+			if !ok {
+				undocumented = append(undocumented, fr.Method+" "+path)
+				r = Route{Method: fr.Method, Path: path}
+			}
+			item[method] = g.operation(r)
+		}
+	}
+
+	sort.Strings(undocumented)
+	return doc, undocumented
+}
+
+func firstKey(m map[string][]string) string {
+	for k := range m {
+		return k
+	}
+	return ""
+}
+
+func (g *generator) operation(r Route) *Operation {
+
+	op := &Operation{
+		OperationID: operationId(r.Method, r.Path),
+		Summary:     r.Summary,
+		Responses:   make(map[string]Response),
+	}
+	if tag := strings.Split(strings.TrimPrefix(r.Path, "/"), "/")[0]; tag != "" {
+		op.Tags = []string{tag}
+	}
+	if r.Public {
+		op.Security = []map[string][]string{{}}
+	}
+
+	for _, name := range pathParams(r.Path) {
+		schema := pathParamSchema(name)
+		for _, p := range r.Params {
+			if p.Name == name {
+				schema = paramSchema(p)
+			}
+		}
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   schema,
+		})
+	}
+	for _, p := range r.Query {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        p.Name,
+			In:          "query",
+			Required:    p.Required,
+			Description: p.Description,
+			Schema:      paramSchema(p),
+		})
+	}
+
+	if r.Body != nil {
+		op.RequestBody = &RequestBody{
+			Required: !r.Optional,
+			Content:  map[string]MediaType{fiber.MIMEApplicationJSON: {Schema: g.schema(typeOf(r.Body))}},
+		}
+	} else if len(r.Form) > 0 {
+		form := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for _, p := range r.Form {
+			form.Properties[p.Name] = paramSchema(p)
+			if p.Required {
+				form.Required = append(form.Required, p.Name)
+			}
+		}
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{fiber.MIMEMultipartForm: {Schema: form}},
+		}
+	}
+
+	ok := Response{Description: "성공"}
+	switch {
+	case len(r.Produces) > 0:
+		ok.Content = make(map[string]MediaType)
+		for _, ct := range r.Produces {
+			ok.Content[ct] = MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+		}
+	case r.Response != nil:
+		ok.Content = map[string]MediaType{fiber.MIMEApplicationJSON: {Schema: g.wrap(g.schema(typeOf(r.Response)))}}
+	case g.envelope:
+		message := &Schema{Type: "object", Properties: map[string]*Schema{"message": {Type: "string"}}}
+		ok.Content = map[string]MediaType{fiber.MIMEApplicationJSON: {Schema: g.wrap(message)}}
+	default:
+		ok.Content = map[string]MediaType{fiber.MIMETextPlain: {Schema: &Schema{Type: "string"}}}
+	}
+	op.Responses["200"] = ok
+	op.Responses["default"] = Response{
+		Description: "오류",
+		Content:     map[string]MediaType{fiber.MIMEApplicationJSON: {Schema: &Schema{Ref: "#/components/schemas/Error"}}},
+	}
+	return op
+}
+
+// operationId GET /funds/{id}/hist -> getFundsIdHist
+func operationId(method, path string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '_' || r == '-'
+	}) {
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return sb.String()
+}
+
+func pathParamSchema(name string) *Schema {
+	if name == "id" || strings.HasSuffix(name, "_id") {
+		return &Schema{Type: "integer", Minimum: ptr(0)}
+	}
+	if name == "date" {
+		return &Schema{Type: "string", Format: "date"}
+	}
+	return &Schema{Type: "string"}
+}
+
+func paramSchema(p Param) *Schema {
+	s := &Schema{Type: p.Type, Description: p.Description}
+	if p.Type == "file" {
+		s.Type, s.Format = "string", "binary"
+	}
+	if s.Type == "" {
+		s.Type = "string"
+	}
+	for _, e := range p.Enum {
+		s.Enum = append(s.Enum, e)
+	}
+	return s
+}
+
+func ptr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,109 @@
+package openapi
+
+import (
+	"encoding/json"
+	"investindicator/app/apierr"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type addReq struct {
+	Name     string  `json:"name" validate:"required"`
+	Category string  `json:"category" validate:"oneof=cash gold"`
+	Price    float64 `json:"price" validate:"required,min=0"`
+	Count    uint    `json:"count"`
+	Tags     []itemReq
+}
+
+type itemReq struct {
+	Key string `json:"key" validate:"required"`
+}
+
+func TestPaths(t *testing.T) {
+	assert.Equal(t, []string{"/assets/{id}/hist"}, paths(`/assets/:id<\d+>/hist`))
+	assert.Equal(t, []string{"/market/indicators", "/market/indicators/{date}"}, paths("/market/indicators/:date?"))
+	assert.Equal(t, []string{"/funds"}, paths("/funds/"))
+	assert.Equal(t, "getFundsIdAvailableAmounts", operationId("GET", "/funds/{id}/available_amounts"))
+}
+
+func TestValidator(t *testing.T) {
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			e := apierr.From(err)
+			return c.Status(e.Status()).JSON(e.Details)
+		},
+	})
+	v := NewValidator()
+	v1 := app.Group("/v1", v.Handler)
+	ok := func(c *fiber.Ctx) error { return c.SendString("ok") }
+	v1.Post("/items", ok)
+	v1.Get("/items/:id", ok)
+	v1.Get("/items/list", ok)
+
+	doc, undocumented := Builder{Prefix: "/v1", Envelope: true}.Build(app.GetRoutes(true), []Route{
+		{Method: "POST", Path: "/items", Body: addReq{}},
+		{Method: "GET", Path: "/items/{id}", Query: []Param{
+			{Name: "side", Type: "string", Enum: []string{"buy", "sell"}},
+			{Name: "limit", Type: "integer"},
+		}},
+	})
+	assert.Equal(t, []string{"GET /items/list"}, undocumented)
+	v.Load(doc)
+
+	schema := doc.Components.Schemas["AddReq"]
+	assert.ElementsMatch(t, []string{"name", "price"}, schema.Required)
+	assert.Equal(t, []any{"cash", "gold"}, schema.Properties["category"].Enum)
+	assert.Contains(t, doc.Components.Schemas, "ItemReq")
+	_, err := json.Marshal(doc)
+	assert.NoError(t, err)
+
+	request := func(method, path, body string) (int, map[string]string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		b, _ := io.ReadAll(resp.Body)
+		details := make(map[string]string)
+		json.Unmarshal(b, &details)
+		return resp.StatusCode, details
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		code, _ := request("POST", "/v1/items", `{"name":"금","category":"gold","price":10,"count":1,"Tags":[{"key":"a"}]}`)
+		assert.Equal(t, fiber.StatusOK, code)
+
+		code, _ = request("GET", "/v1/items/1?side=buy&limit=10", "")
+		assert.Equal(t, fiber.StatusOK, code)
+
+		code, _ = request("GET", "/v1/items/list?limit=abc", "")
+		assert.Equal(t, fiber.StatusOK, code, "고정 경로 우선, 문서 없는 경로는 검증 생략")
+	})
+
+	t.Run("Invalid Body", func(t *testing.T) {
+		code, details := request("POST", "/v1/items", `{"category":"stock","price":-1,"count":"1","Tags":[{}]}`)
+		assert.Equal(t, fiber.StatusBadRequest, code)
+		assert.Contains(t, details, "body.name")
+		assert.Contains(t, details, "body.category")
+		assert.Contains(t, details, "body.price")
+		assert.Contains(t, details, "body.count")
+		assert.Contains(t, details, "body.Tags[0].key")
+
+		code, details = request("POST", "/v1/items", "")
+		assert.Equal(t, fiber.StatusBadRequest, code)
+		assert.Contains(t, details, "body")
+	})
+
+	t.Run("Invalid Params", func(t *testing.T) {
+		code, details := request("GET", "/v1/items/x?side=hold&limit=1.5", "")
+		assert.Equal(t, fiber.StatusBadRequest, code)
+		assert.Contains(t, details, "path.id")
+		assert.Contains(t, details, "query.side")
+		assert.Contains(t, details, "query.limit")
+	})
+}
@@ -0,0 +1,239 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema JSON schema. OpenAPI 3.0 문서와 요청 검증에서 사용하는 항목만 정의
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textType      = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// generator 타입별 schema 생성. 이름 있는 struct는 components에 등록 후 $ref로 참조
+type generator struct {
+	schemas  map[string]*Schema
+	tags     map[string]func(*Schema)
+	envelope bool
+}
+
+func newGenerator(tags map[string]func(*Schema)) *generator {
+	g := &generator{
+		schemas: make(map[string]*Schema),
+		tags:    tags,
+	}
+	g.schemas["Error"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"error": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"code":    {Type: "string"},
+					"message": {Type: "string"},
+					"details": {},
+				},
+				Required: []string{"code", "message"},
+			},
+		},
+		Required: []string{"error"},
+	}
+	g.schemas["Meta"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"total":       {Type: "integer", Description: "페이지 적용 전 전체 건수"},
+			"next_cursor": {Type: "string", Description: "다음 페이지 cursor. 마지막 페이지면 생략"},
+		},
+	}
+	return g
+}
+
+// wrap envelope 사용 시 응답 schema를 {"data", "meta"}로 감쌈
+func (g *generator) wrap(s *Schema) *Schema {
+	if !g.envelope {
+		return s
+	}
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"data": s,
+			"meta": {Ref: "#/components/schemas/Meta"},
+		},
+		Required: []string{"data"},
+	}
+}
+
+func typeOf(v any) reflect.Type {
+	return reflect.TypeOf(v)
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	if t.Kind() == reflect.Pointer {
+		s := g.schema(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	}
+	if t.Kind() != reflect.Interface && (t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType)) {
+		return &Schema{} // memo. 직접 변환하는 타입(gorm.DeletedAt 등)은 형식 미지정
+	}
+	if t.Kind() != reflect.Interface && (t.Implements(textType) || reflect.PointerTo(t).Implements(textType)) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: ptr(0)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		name := schemaName(t)
+		if _, ok := g.schemas[name]; !ok {
+			g.schemas[name] = &Schema{} // memo. 재귀 참조 대비 먼저 등록
+			*g.schemas[name] = *g.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	return &Schema{}
+}
+
+// schemaName 외부에 노출할 이름. 비공개 타입명은 첫 글자를 대문자로 변환
+func schemaName(t reflect.Type) string {
+	name := t.Name()
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// object struct 필드별 schema. json tag 이름 사용, 익명 필드는 펼침, validate tag는 제약으로 변환
+func (g *generator) object(t reflect.Type) *Schema {
+
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		ft := f.Type
+		if f.Anonymous && name == "" {
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded := g.object(ft)
+				for k, v := range embedded.Properties {
+					s.Properties[k] = v
+				}
+				s.Required = append(s.Required, embedded.Required...)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := g.schema(ft)
+		if strings.Contains(opts, "string") && fs.Type != "string" {
+			fs = &Schema{Type: "string"}
+		}
+		if g.constrain(fs, f.Tag.Get("validate")) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = fs
+	}
+	return s
+}
+
+/*
+constrain validate tag를 schema 제약으로 변환. required 여부 반환
+  - required: 필수 필드
+  - min, max: 숫자는 범위, 문자열은 최소 길이
+  - oneof: enum
+  - 그 외 tag는 Builder.Tags에 등록된 변환 적용
+*/
+func (g *generator) constrain(s *Schema, tag string) bool {
+
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "", "omitempty":
+		case "required":
+			required = true
+		case "min", "max":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				continue
+			}
+			switch {
+			case s.Type == "string" && name == "min":
+				l := int(n)
+				s.MinLength = &l
+			case name == "min":
+				s.Minimum = ptr(n)
+			case name == "max":
+				s.Maximum = ptr(n)
+			}
+		case "oneof":
+			for _, v := range strings.Fields(arg) {
+				s.Enum = append(s.Enum, v)
+			}
+		default:
+			if f, ok := g.tags[name]; ok {
+				f(s)
+			}
+		}
+	}
+	return required
+}
@@ -0,0 +1,286 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"investindicator/app/apierr"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type compiledOp struct {
+	method string
+	re     *regexp.Regexp
+	names  []string
+	op     *Operation
+}
+
+/*
+Validator 문서 기준 요청 검증 middleware
+  - 경로, query parameter 타입과 enum, 필수 여부
+  - JSON body 필드 타입, 필수 필드, enum, 범위
+  - 문서에 없는 경로는 검증 없이 통과. 경로 등록 후 문서를 만들기 때문에 Load 전 요청도 통과
+*/
+type Validator struct {
+	mu      sync.RWMutex
+	prefix  string
+	ops     []compiledOp
+	schemas map[string]*Schema
+}
+
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+func (v *Validator) Load(doc *Document) {
+
+	ops := make([]compiledOp, 0)
+	for path, item := range doc.Paths {
+		names := pathParams(path)
+		parts := pathParamPattern.Split(path, -1)
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		re := regexp.MustCompile("^" + strings.Join(parts, `([^/]+)`) + "/?$")
+		for method, op := range item {
+			ops = append(ops, compiledOp{method: strings.ToUpper(method), re: re, names: names, op: op})
+		}
+	}
+	// memo. 고정 경로(/market/indicators)가 parameter 경로(/market/{date})보다 먼저 일치하도록 parameter 수로 정렬
+	slices.SortFunc(ops, func(a, b compiledOp) int {
+		return len(a.names) - len(b.names)
+	})
+
+	prefix := ""
+	if len(doc.Servers) > 0 {
+		prefix = doc.Servers[0].URL
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.prefix = prefix
+	v.ops = ops
+	v.schemas = doc.Components.Schemas
+}
+
+func (v *Validator) Handler(c *fiber.Ctx) error {
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	path := strings.TrimPrefix(c.Path(), v.prefix)
+	for _, o := range v.ops {
+		if o.method != c.Method() {
+			continue
+		}
+		m := o.re.FindStringSubmatch(path)
+		if m == nil {
+			continue
+		}
+
+		errs := make(map[string]string)
+		v.params(c, o, m[1:], errs)
+		v.body(c, o.op, errs)
+		if len(errs) > 0 {
+			return invalid(errs)
+		}
+		break
+	}
+	return c.Next()
+}
+
+func (v *Validator) params(c *fiber.Ctx, o compiledOp, values []string, errs map[string]string) {
+
+	for _, p := range o.op.Parameters {
+		var value string
+		switch p.In {
+		case "path":
+			if i := slices.Index(o.names, p.Name); i >= 0 {
+				value = values[i]
+			}
+		case "query":
+			value = c.Query(p.Name)
+		}
+
+		key := p.In + "." + p.Name
+		if value == "" {
+			if p.Required {
+				errs[key] = "필수 parameter 누락"
+			}
+			continue
+		}
+		if msg := v.checkString(p.Schema, value); msg != "" {
+			errs[key] = msg
+		}
+	}
+}
+
+func (v *Validator) body(c *fiber.Ctx, op *Operation, errs map[string]string) {
+
+	if op.RequestBody == nil {
+		return
+	}
+	mt, ok := op.RequestBody.Content[fiber.MIMEApplicationJSON]
+	if !ok {
+		return // memo. multipart(파일 업로드)는 handler에서 확인
+	}
+
+	body := c.Body()
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			errs["body"] = "요청 body 누락"
+		}
+		return
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		errs["body"] = fmt.Sprintf("JSON 변환 불가. %s", err.Error())
+		return
+	}
+	v.check(mt.Schema, value, "body", errs)
+}
+
+func (v *Validator) resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = v.schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
+
+// check JSON 값 검증. null은 필드 미입력과 같이 취급
+func (v *Validator) check(s *Schema, value any, key string, errs map[string]string) {
+
+	s = v.resolve(s)
+	if s == nil || value == nil {
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			errs[key] = "object 타입이 아님"
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				errs[key+"."+name] = "필수 필드 누락"
+			}
+		}
+		for name, fv := range obj {
+			if ps, ok := s.Properties[name]; ok {
+				v.check(ps, fv, key+"."+name, errs)
+			} else if s.AdditionalProperties != nil {
+				v.check(s.AdditionalProperties, fv, key+"."+name, errs)
+			}
+		}
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			errs[key] = "array 타입이 아님"
+			return
+		}
+		for i, item := range arr {
+			v.check(s.Items, item, fmt.Sprintf("%s[%d]", key, i), errs)
+		}
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			errs[key] = s.Type + " 타입이 아님"
+			return
+		}
+		if msg := checkNumber(s, n.String()); msg != "" {
+			errs[key] = msg
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			errs[key] = "string 타입이 아님"
+			return
+		}
+		if msg := checkString(s, str); msg != "" {
+			errs[key] = msg
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			errs[key] = "boolean 타입이 아님"
+		}
+	}
+}
+
+// checkString 경로, query 값 검증. 문자열을 schema 타입으로 변환해서 확인
+func (v *Validator) checkString(s *Schema, value string) string {
+	s = v.resolve(s)
+	if s == nil {
+		return ""
+	}
+	switch s.Type {
+	case "integer", "number":
+		return checkNumber(s, value)
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return "boolean 타입이 아님"
+		}
+		return ""
+	}
+	return checkString(s, value)
+}
+
+func checkNumber(s *Schema, value string) string {
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return s.Type + " 타입이 아님"
+	}
+	if s.Type == "integer" && n != float64(int64(n)) {
+		return "integer 타입이 아님"
+	}
+	if s.Minimum != nil && n < *s.Minimum {
+		return fmt.Sprintf("%v 이상이어야 함", *s.Minimum)
+	}
+	if s.Maximum != nil && n > *s.Maximum {
+		return fmt.Sprintf("%v 이하여야 함", *s.Maximum)
+	}
+	return ""
+}
+
+func checkString(s *Schema, value string) string {
+
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, any(value)) {
+		return fmt.Sprintf("허용하지 않는 값. 허용 값 :%v", s.Enum)
+	}
+	if s.MinLength != nil && len([]rune(value)) < *s.MinLength {
+		return fmt.Sprintf("%d자 이상이어야 함", *s.MinLength)
+	}
+	if s.Pattern != "" {
+		if ok, err := regexp.MatchString(s.Pattern, value); err == nil && !ok {
+			return "형식 불일치. " + s.Pattern
+		}
+	}
+	return ""
+}
+
+func invalid(errs map[string]string) error {
+
+	keys := make([]string, 0, len(errs))
+	for k := range errs {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	msgs := make([]string, len(keys))
+	for i, k := range keys {
+		msgs[i] = k + ": " + errs[k]
+	}
+	return apierr.New(apierr.CodeBadRequest, errors.New("요청 형식 오류. "+strings.Join(msgs, ", "))).WithDetails(errs)
+}
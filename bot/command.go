@@ -0,0 +1,234 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	model "investindicator/internal/model"
+	"investindicator/notify"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Command telegram 명령. ex) "/event on 3" => Name: "event", Args: ["on", "3"]
+type Command struct {
+	Name   string
+	Args   []string
+	ChatId int64
+	From   int64       // 명령을 보낸 telegram user id
+	User   *model.User // 명령을 보낸 사용자. nil이면 설정된 chat의 소유자
+	File   *File       // 명령을 캡션으로 첨부한 파일. 없으면 nil
+}
+
+// File telegram으로 업로드된 파일
+type File struct {
+	Name string
+	Data []byte
+}
+
+// IsAdmin 설정된 chat의 소유자 혹은 admin 사용자
+func (c Command) IsAdmin() bool {
+	return c.User == nil || c.User.IsAdmin
+}
+
+// Session 명령을 보낸 chat과의 대화. 선택지 버튼은 요청 ID로 응답을 매칭하므로 동시에 여러 명령이 진행되어도 섞이지 않음
+type Session interface {
+	Reply(msg string)
+	ReplyPhoto(name string, png []byte, caption string)
+	ReplyDocument(name string, data []byte, caption string)
+	Choose(prompt string, options ...string) (string, error)
+	Confirm(prompt string) (bool, error)
+}
+
+type CommandHandler func(s Session, cmd Command) error
+
+// ErrCancelled 확인 단계에서 취소 시 CommandHandler가 반환
+var ErrCancelled = errors.New("사용자 취소")
+
+type command struct {
+	handler CommandHandler
+	admin   bool   // true면 관리자 chat에서만 실행 가능
+	usage   string // /help 출력용
+}
+
+// Handle 명령 등록. Run 이전에 호출. 미등록 명령은 기존과 같이 조회 API 경로로 처리
+func (t *TeleBot) Handle(name string, admin bool, usage string, h CommandHandler) {
+	t.commands[name] = command{
+		handler: h,
+		admin:   admin,
+		usage:   usage,
+	}
+}
+
+// UsePrompts 선택지 응답 대기 목록 지정. 버튼 callback과 명령 대화의 선택지 응답에 사용
+func (t *TeleBot) UsePrompts(p *notify.Prompts) {
+	t.prompts = p
+}
+
+// memo. db.Storage가 구현
+type userRetriever interface {
+	UserByTelegramId(telegramId int64) (*model.User, error)
+}
+
+// telegramUserHeader 조회 API 요청 시 사용자 전달용 header. handler.TelegramUserHeader와 동일
+const telegramUserHeader = "X-Telegram-User"
+
+// UseUsers telegram user id - 사용자 연동 조회 지정. 미지정 시 설정된 chat의 소유자만 허용
+func (t *TeleBot) UseUsers(u userRetriever) {
+	t.users = u
+}
+
+/*
+authorize 메시지를 보낸 telegram 사용자 확인
+  - User 테이블에 연동된 사용자면 해당 사용자 반환. 비활성화된 사용자는 거부
+  - 설정된 chat의 소유자(개인 chat id == user id)는 nil 사용자로 허용
+  - 그 외는 거부
+*/
+func (t TeleBot) authorize(from *tgbotapi.User) (*model.User, bool) {
+	if from == nil {
+		return nil, false
+	}
+	if t.users != nil {
+		if user, err := t.users.UserByTelegramId(from.ID); err == nil {
+			return user, !user.Disabled
+		}
+	}
+	if from.ID == t.chatId {
+		return nil, true
+	}
+	return nil, false
+}
+
+// canAnswer admin이 아니면 본인이 실행한 명령의 선택지만 응답 가능
+func (t TeleBot) canAnswer(user *model.User, from int64, promptId string) bool {
+	if user == nil || user.IsAdmin || t.prompts == nil {
+		return true
+	}
+	p, ok := t.prompts.Get(promptId)
+	return ok && p.Route == sessionRoute(from)
+}
+
+func fromId(from *tgbotapi.User) int64 {
+	if from == nil {
+		return 0
+	}
+	return from.ID
+}
+
+// sessionRoute 명령 대화 선택지의 route. 명령을 보낸 사용자별로 구분
+func sessionRoute(from int64) string {
+	return fmt.Sprintf("telegram:%d", from)
+}
+
+func parseCommand(txt string, chatId int64) Command {
+	fields := strings.Fields(strings.TrimPrefix(txt, "/"))
+	if len(fields) == 0 {
+		return Command{ChatId: chatId}
+	}
+	name, _, _ := strings.Cut(fields[0], "@") // 그룹 chat에서는 /invest@botname 형식
+	return Command{
+		Name:   name,
+		Args:   fields[1:],
+		ChatId: chatId,
+	}
+}
+
+// runCommand 명령 실행. 선택지 응답을 기다리는 동안 update 수신이 막히지 않도록 별도 goroutine에서 호출
+func (t TeleBot) runCommand(c command, cmd Command) {
+	s := chatSession{t: t, chatId: cmd.ChatId, from: cmd.From}
+	if c.admin && !cmd.IsAdmin() {
+		s.Reply("관리자만 실행할 수 있는 명령입니다.")
+		return
+	}
+	if err := c.handler(s, cmd); err != nil {
+		if errors.Is(err, ErrCancelled) || errors.Is(err, notify.ErrPromptTimeout) || errors.Is(err, notify.ErrPromptCancelled) {
+			s.Reply(fmt.Sprintf("/%s 취소. %s", cmd.Name, err))
+			return
+		}
+		s.Reply(fmt.Sprintf("/%s 실행 시 오류 발생. %s\n사용법: %s", cmd.Name, err, c.usage))
+	}
+}
+
+// maxFileSize telegram bot API 파일 다운로드 제한
+const maxFileSize = 20 << 20
+
+// download 첨부 파일 다운로드
+func (t TeleBot) download(doc *tgbotapi.Document) (*File, error) {
+	if doc.FileSize > maxFileSize {
+		return nil, fmt.Errorf("파일 크기 제한 초과. %d byte", doc.FileSize)
+	}
+	url, err := t.bot.GetFileDirectURL(doc.FileID)
+	if err != nil {
+		return nil, fmt.Errorf("파일 경로 조회 시 오류 발생. %w", err)
+	}
+
+	res, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("파일 다운로드 시 오류 발생. %w", err)
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(res.Body, maxFileSize))
+	if err != nil {
+		return nil, fmt.Errorf("파일 다운로드 시 오류 발생. %w", err)
+	}
+	return &File{Name: doc.FileName, Data: b}, nil
+}
+
+func (t TeleBot) commandHelp() string {
+	names := make([]string, 0, len(t.commands))
+	for name := range t.commands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var sb strings.Builder
+	sb.WriteString("명령 목록\n")
+	for _, name := range names {
+		sb.WriteString(t.commands[name].usage + "\n")
+	}
+	return sb.String()
+}
+
+type chatSession struct {
+	t      TeleBot
+	chatId int64
+	from   int64
+}
+
+func (s chatSession) Reply(msg string) {
+	s.t.send("message", tgbotapi.NewMessage(s.chatId, msg))
+}
+
+func (s chatSession) ReplyPhoto(name string, png []byte, caption string) {
+	photo := tgbotapi.NewPhoto(s.chatId, tgbotapi.FileBytes{Name: name, Bytes: png})
+	photo.Caption = caption
+	s.t.send("photo", photo)
+}
+
+func (s chatSession) ReplyDocument(name string, data []byte, caption string) {
+	doc := tgbotapi.NewDocument(s.chatId, tgbotapi.FileBytes{Name: name, Bytes: data})
+	doc.Caption = caption
+	s.t.send("document", doc)
+}
+
+func (s chatSession) Choose(prompt string, options ...string) (string, error) {
+	if s.t.prompts == nil {
+		return "", errors.New("선택지 응답 대기 목록 미설정")
+	}
+	answer, _, err := s.t.prompts.Ask(sessionRoute(s.from), notify.Question{Prompt: prompt, Options: options}, func(p notify.Prompt) error {
+		return s.t.sendPrompt(s.chatId, p.ID, p.Prompt, p.Options...)
+	})
+	return answer, err
+}
+
+func (s chatSession) Confirm(prompt string) (bool, error) {
+	answer, err := s.Choose(prompt, "확인", "취소")
+	if err != nil {
+		return false, err
+	}
+	return answer == "확인", nil
+}
@@ -0,0 +1,29 @@
+package bot
+
+import (
+	"context"
+	m "investindicator/internal/model"
+	"time"
+)
+
+// healthTTL telegram API 확인 결과 재사용 시간. 상태 확인 요청마다 getMe를 호출하지 않도록 제한
+const healthTTL = 30 * time.Second
+
+// Health telegram API 도달 여부. 알림 전송 불가 시에도 API는 동작하므로 필수 구성 요소 아님
+func (t TeleBot) Health(ctx context.Context) m.ComponentHealth {
+	return t.health.Get(func() m.ComponentHealth {
+		name := "telegram:" + t.bot.Self.UserName
+		if _, err := t.bot.GetMe(); err != nil {
+			return m.FailedComponent(name, false, err)
+		}
+		return m.HealthyComponent(name, false)
+	})
+}
+
+func (t TeleBotGroup) Health(ctx context.Context) []m.ComponentHealth {
+	rtn := make([]m.ComponentHealth, len(t.bots))
+	for i, bot := range t.bots {
+		rtn[i] = bot.Health(ctx)
+	}
+	return rtn
+}
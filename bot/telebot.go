@@ -4,17 +4,34 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	model "investindicator/internal/model"
+	"investindicator/notify"
 	"io"
 	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+var sendFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "telegram_send_failures_total",
+	Help: "telegram 메시지 전송 실패 수",
+}, []string{"kind"})
+
 type TeleBot struct {
-	bot     *tgbotapi.BotAPI
-	chatId  int64
-	updates tgbotapi.UpdatesChannel
-	ch      chan string
+	bot      *tgbotapi.BotAPI
+	chatId   int64
+	updates  tgbotapi.UpdatesChannel
+	users    userRetriever
+	commands map[string]command
+	prompts  *notify.Prompts // 선택지 응답 대기 목록. 버튼 callback을 요청 ID로 전달
+	health   *model.HealthCache
 }
 
 type TeleBotConfig struct {
@@ -35,6 +52,7 @@ var helpMsg = `
 				/market
 				/market/indicators/{date?}
 				/events
+				/prompts
 				`
 
 func NewTeleBot(conf *TeleBotConfig) (*TeleBot, error) {
@@ -49,53 +67,67 @@ func NewTeleBot(conf *TeleBotConfig) (*TeleBot, error) {
 	u.Timeout = 60
 	updates := bot.GetUpdatesChan(u)
 
-	ch := make(chan string) // channel은 telebot 내부에서 thread간 소통용. telebot 외부에서는 다 동기처럼 보이게끔 설계.
-
 	return &TeleBot{
-		bot:     bot,
-		chatId:  conf.ChatId,
-		updates: updates,
-		ch:      ch,
+		bot:      bot,
+		chatId:   conf.ChatId,
+		updates:  updates,
+		commands: make(map[string]command),
+		health:   model.NewHealthCache(healthTTL),
 	}, nil
 }
 
-func (t TeleBot) Run(port int, passkey string) {
+func (t TeleBot) Run(port int, apiKey string) {
 	t.SendMessage("LAUNCHED SUCCESSFULLY")
 
 	for update := range t.updates {
 		if update.Message != nil {
 			txt := update.Message.Text
-			if txt[0] == '/' {
-				switch txt {
-				case "/help":
-					t.SendMessage(helpMsg)
+			if update.Message.Document != nil { // 파일 첨부 명령은 캡션으로 입력
+				txt = update.Message.Caption
+			}
+			if len(txt) > 0 && txt[0] == '/' {
+				s := chatSession{t: t, chatId: update.Message.Chat.ID}
+				user, ok := t.authorize(update.Message.From)
+				if !ok {
+					s.Reply(fmt.Sprintf("등록되지 않은 사용자입니다. telegram id: %d", fromId(update.Message.From)))
+					continue
+				}
+
+				cmd := parseCommand(txt, update.Message.Chat.ID)
+				cmd.From = fromId(update.Message.From)
+				cmd.User = user
+				c, ok := t.commands[cmd.Name]
+				switch {
+				case cmd.Name == "help":
+					s.Reply(helpMsg + "\n" + t.commandHelp())
+				case ok && update.Message.Document != nil:
+					go func() {
+						f, err := t.download(update.Message.Document)
+						if err != nil {
+							s.Reply(err.Error())
+							return
+						}
+						cmd.File = f
+						t.runCommand(c, cmd)
+					}()
+				case ok:
+					go t.runCommand(c, cmd)
 				default:
-					rtn, err := httpsend(fmt.Sprintf("http://localhost:%d%s", port, txt), passkey)
+					apiPath, ok := proxyPath(txt)
+					if !ok {
+						s.Reply("지원하지 않는 명령입니다. /help 참고")
+						continue
+					}
+					rtn, err := httpsend(fmt.Sprintf("http://localhost:%d%s", port, apiPath), apiKey, user)
 					if err != nil {
-						t.SendMessage(err.Error())
+						s.Reply(err.Error())
 					} else {
-						t.SendMessage(rtn)
+						s.Reply(rtn)
 					}
 				}
 			}
 		} else if update.CallbackQuery != nil {
-			// Answer the callback to remove loading state
-			callback := tgbotapi.NewCallback(update.CallbackQuery.ID, "")
-			t.bot.Request(callback)
-
-			// Parse and return the selected value
-			t.ch <- update.CallbackQuery.Data
-
-			newKeyboard := tgbotapi.NewInlineKeyboardMarkup(
-				tgbotapi.NewInlineKeyboardRow(
-					tgbotapi.NewInlineKeyboardButtonData("SUCCESSFULLY SELECTED", update.CallbackQuery.Data),
-				),
-			)
-			// Edit the message with the updated keyboard
-			editMsg := tgbotapi.NewEditMessageReplyMarkup(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.Message.MessageID, newKeyboard)
-			if _, err := t.bot.Send(editMsg); err != nil {
-				t.SendMessage("Callback 오류. " + err.Error())
-			}
+			t.handleCallback(update.CallbackQuery)
 		}
 	}
 
@@ -114,32 +146,33 @@ func (t TeleBot) InitKey(msg error) string {
 }
 
 func (t TeleBot) SendMessage(msg string) {
-	t.bot.Send(tgbotapi.NewMessage(t.chatId, msg))
+	t.send("message", tgbotapi.NewMessage(t.chatId, msg))
 }
 
-func (t TeleBot) SendButtonsAndGetResult(prompt string, options ...string) (answer string, err error) {
-
-	err = t.sendButtons(prompt, options...)
-	if err != nil {
-		return "", err
-	}
-	answer = <-t.ch
-
-	return answer, nil
-
+// SendPrompt 선택지 버튼 전송. callback data는 요청 ID|선택지 index (telegram callback data 64byte 제한)
+func (t TeleBot) SendPrompt(id string, prompt string, options ...string) error {
+	return t.sendPrompt(t.chatId, id, prompt, options...)
 }
 
 /**********************************************************************************************************************
 *************************************************Inner Function*******************************************************
 **********************************************************************************************************************/
 
-// SendButtonsAndGetSelection sends a message with inline keyboard buttons for the given integer options
-// and returns the selected button value. The prompt parameter is the message text to display.
-func (t TeleBot) sendButtons(prompt string, options ...string) error {
+// send 전송 실패 시 종류(kind)별 실패 metric 집계
+func (t TeleBot) send(kind string, c tgbotapi.Chattable) error {
+	_, err := t.bot.Send(c)
+	if err != nil {
+		sendFailures.WithLabelValues(kind).Inc()
+	}
+	return err
+}
+
+// sendPrompt chatId로 선택지 버튼 전송
+func (t TeleBot) sendPrompt(chatId int64, id string, prompt string, options ...string) error {
 	// Create inline keyboard buttons
 	var buttons [][]tgbotapi.InlineKeyboardButton
-	for _, option := range options {
-		button := tgbotapi.NewInlineKeyboardButtonData(option, option)
+	for i, option := range options {
+		button := tgbotapi.NewInlineKeyboardButtonData(option, fmt.Sprintf("%s|%d", id, i))
 		buttons = append(buttons, []tgbotapi.InlineKeyboardButton{button})
 	}
 
@@ -147,22 +180,74 @@ func (t TeleBot) sendButtons(prompt string, options ...string) error {
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(buttons...)
 
 	// Create and send the message with buttons
-	msg := tgbotapi.NewMessage(t.chatId, prompt)
+	msg := tgbotapi.NewMessage(chatId, prompt)
 	msg.ReplyMarkup = keyboard
-	_, err := t.bot.Send(msg)
-	if err != nil {
-		return err
+	return t.send("prompt", msg)
+
+}
+
+// handleCallback 버튼 선택 시 요청 ID로 응답 전달 후 선택 결과로 버튼 교체
+func (t TeleBot) handleCallback(q *tgbotapi.CallbackQuery) {
+
+	result := "처리할 수 없는 요청"
+	id, idxStr, _ := strings.Cut(q.Data, "|")
+	user, ok := t.authorize(q.From)
+	if !ok || !t.canAnswer(user, fromId(q.From), id) {
+		t.bot.Request(tgbotapi.NewCallback(q.ID, "권한 없음"))
+		return
 	}
+	if idx, err := strconv.Atoi(idxStr); err == nil && t.prompts != nil {
+		answer, err := t.prompts.AnswerIndex(id, idx)
+		if err != nil {
+			result = err.Error()
+		} else {
+			result = "SUCCESSFULLY SELECTED : " + answer
+		}
+	}
+
+	// Answer the callback to remove loading state
+	t.bot.Request(tgbotapi.NewCallback(q.ID, result))
+
+	newKeyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(result, q.Data),
+		),
+	)
+	// Edit the message with the updated keyboard
+	editMsg := tgbotapi.NewEditMessageReplyMarkup(q.Message.Chat.ID, q.Message.MessageID, newKeyboard)
+	if _, err := t.bot.Send(editMsg); err != nil {
+		t.SendMessage("Callback 오류. " + err.Error())
+	}
+}
 
-	return nil
+// proxyPaths 미등록 명령을 조회 API로 전달할 수 있는 경로의 첫 segment. helpMsg 목록과 동일하게 유지
+var proxyPaths = []string{"funds", "assets", "market", "events", "prompts"}
 
+// proxyPath 미등록 명령을 조회 API 경로로 변환. 정리된 경로의 첫 segment가 proxyPaths에 없으면 거부
+func proxyPath(txt string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(txt))
+	if err != nil || u.Host != "" || !strings.HasPrefix(u.Path, "/") {
+		return "", false
+	}
+	cleaned := path.Clean(u.Path) // memo. /funds/../shutdown 등으로 다른 경로 접근 방지
+	segment, _, _ := strings.Cut(strings.TrimPrefix(cleaned, "/"), "/")
+	if !slices.Contains(proxyPaths, segment) {
+		return "", false
+	}
+	u.Path = cleaned
+	u.RawPath = ""
+	return u.RequestURI(), true
 }
 
-func httpsend(url string, passkey string) (string, error) {
+// httpsend 조회 API 요청. user가 있으면 해당 사용자 권한으로 처리되도록 telegram id 전달
+func httpsend(url string, apiKey string, user *model.User) (string, error) {
 
 	// url := "http://localhost:50001" + path
 	req, _ := http.NewRequest(http.MethodGet, url, nil)
-	req.Header.Set("Authorization", passkey)
+	req.Header.Set("Authorization", "ApiKey "+apiKey)
+	if user != nil && user.TelegramId != nil {
+		req.Header.Set(telegramUserHeader, strconv.FormatInt(*user.TelegramId, 10))
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	client := &http.Client{}
@@ -1,5 +1,7 @@
 package bot
 
+import "investindicator/notify"
+
 type TeleBotGroup struct {
 	bots []*TeleBot
 }
@@ -19,9 +21,9 @@ func NewTeleBotGroup(confs []*TeleBotConfig) *TeleBotGroup {
 	}
 }
 
-func (t TeleBotGroup) RunAll(port int, passkey string) {
+func (t TeleBotGroup) RunAll(port int, apiKey string) {
 	for _, bot := range t.bots {
-		go bot.Run(port, passkey)
+		go bot.Run(port, apiKey)
 	}
 }
 
@@ -32,6 +34,10 @@ func (t TeleBotGroup) Bot(idx int) *TeleBot {
 	return t.bots[idx]
 }
 
+func (t TeleBotGroup) Len() int {
+	return len(t.bots)
+}
+
 func (t TeleBotGroup) SendMessage(idx int, msg string) {
 	if idx < 0 || idx >= len(t.bots) {
 		idx = 0 // Default to the first bot if index is out of range
@@ -39,9 +45,23 @@ func (t TeleBotGroup) SendMessage(idx int, msg string) {
 	t.bots[idx].SendMessage(msg)
 }
 
-func (t TeleBotGroup) SendButtonsAndGetResult(idx int, prompt string, options ...string) (answer string, err error) {
-	if idx < 0 || idx >= len(t.bots) {
-		idx = 0 // Default to the first bot if index is out of range
+// UsePrompts 모든 봇의 선택지 응답 대기 목록 지정
+func (t TeleBotGroup) UsePrompts(p *notify.Prompts) {
+	for _, bot := range t.bots {
+		bot.UsePrompts(p)
+	}
+}
+
+// UseUsers 모든 봇의 사용자 연동 조회 지정
+func (t TeleBotGroup) UseUsers(u userRetriever) {
+	for _, bot := range t.bots {
+		bot.UseUsers(u)
+	}
+}
+
+// Handle 모든 봇에 명령 등록
+func (t TeleBotGroup) Handle(name string, admin bool, usage string, h CommandHandler) {
+	for _, bot := range t.bots {
+		bot.Handle(name, admin, usage, h)
 	}
-	return t.bots[idx].SendButtonsAndGetResult(prompt, options...)
 }
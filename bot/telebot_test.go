@@ -1,5 +1,36 @@
 package bot
 
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
 // func TestAlarm(t *testing.T) {
 
 // }
+
+func TestProxyPath(t *testing.T) {
+
+	cases := []struct {
+		txt  string
+		path string
+		ok   bool
+	}{
+		{"/funds", "/funds", true},
+		{"/funds/1/hist?page=2", "/funds/1/hist?page=2", true},
+		{"/market/indicators/2024-01-01", "/market/indicators/2024-01-01", true},
+		{"/shutdown", "", false},
+		{"/users", "", false},
+		{"/apikeys", "", false},
+		{"/funds/../shutdown", "", false},
+		{"/funds/%2e%2e/shutdown", "", false},
+		{"//evil.com/funds", "", false},
+	}
+
+	for _, c := range cases {
+		path, ok := proxyPath(c.txt)
+		assert.Equal(t, c.ok, ok, c.txt)
+		assert.Equal(t, c.path, path, c.txt)
+	}
+}
@@ -3,9 +3,21 @@ package main
 import (
 	investind "investindicator"
 	app "investindicator/app"
+	"investindicator/app/command"
 	"investindicator/bot"
 	"investindicator/config"
+	"investindicator/internal/apikey"
+	"investindicator/internal/audit"
+	"investindicator/internal/cache"
+	"investindicator/internal/chart"
 	"investindicator/internal/db"
+	"investindicator/internal/export"
+	"investindicator/internal/importer"
+	"investindicator/internal/model"
+	"investindicator/internal/pricebus"
+	"investindicator/internal/push"
+	"investindicator/internal/quote"
+	"investindicator/notify"
 	"investindicator/scrape"
 
 	"github.com/rs/zerolog"
@@ -40,6 +52,7 @@ func main() {
 	scraper, err := scrape.NewScraper(conf,
 		scrape.WithKIS(conf.KisConfig(teleBotGroup.Bot(0))), // todo. 여기에 봇을 집어넣고, config struct 반환
 		scrape.WithUpbitToken(conf.UpbitConfig(teleBotGroup.Bot(0))),
+		scrape.WithRateLimits(conf.RateLimits()),
 	)
 	if err != nil {
 		panic(err)
@@ -78,10 +91,63 @@ func main() {
 
 	// bt := blockchain.NewBlockChainTrader(us, bd, conf.ToStrategyConfig())
 
-	eventHandler := investind.NewInvestIndicator(db, scraper, scraper, nil, teleBotGroup)
+	alertWindows, err := conf.AlertWindows()
+	if err != nil {
+		panic(err)
+	}
+
+	quoteTTLs, err := conf.QuoteTTLs()
+	if err != nil {
+		panic(err)
+	}
+	quoteCache := quote.NewCache(scraper, quote.WithTTLs(quoteTTLs))
+
+	router, err := conf.NotifyRouter(teleBotGroup)
+	if err != nil {
+		panic(err)
+	}
+	dispatcherOpts, err := conf.DispatcherOptions()
+	if err != nil {
+		panic(err)
+	}
+	dispatcher := notify.NewDispatcher(router, dispatcherOpts...)
+	go dispatcher.Run()
+
+	recorder := audit.NewRecorder(db)
+
+	// memo. 실시간 시세는 이벤트 처리와 API push(prices topic)가 같은 bus를 구독
+	hub := push.NewHub()
+	bus := pricebus.NewBus()
+	ticks, _ := bus.Subscribe(256)
+	go hub.ForwardTicks(ticks)
+
+	eventHandler := investind.NewInvestIndicator(db, scraper, scraper, nil, dispatcher,
+		investind.WithAuditor(recorder),
+		investind.WithPriceBus(bus),
+		investind.WithPublisher(hub),
+		investind.WithAlertCache(cache.NewAlertCache(db, cache.WithWindows(alertWindows))),
+		investind.WithQuoteCache(quoteCache),
+	)
 	// eventHandler.Run()
 
-	teleBotGroup.RunAll(conf.App.Port, conf.App.Passkey) // todo. telegram login
+	investImporter := importer.NewImporter(db, eventHandler)
+
+	command.NewCommander(db, eventHandler, scraper, chart.NewService(db), investImporter, export.NewService(db, scraper), recorder).InitCommands(teleBotGroup)
+
+	// memo. bot 조회 요청 전용 키. 기동 시마다 재발급하며 이전 키는 폐기
+	apiKeys := apikey.NewManager(db)
+	botKey, err := apiKeys.Provision("telegram", model.ScopeRead, model.ScopeTelegram)
+	if err != nil {
+		panic(err)
+	}
+
+	teleBotGroup.UseUsers(db)
+	teleBotGroup.RunAll(conf.App.Port, botKey) // todo. telegram login
+
+	guardOpts, err := conf.GuardOptions()
+	if err != nil {
+		panic(err)
+	}
 
-	app.Run(conf.App.Port, conf.App.JwtKey, conf.App.Passkey, conf.App.AllowIp, db, scraper, eventHandler)
+	app.Run(conf.App.Port, conf.App.JwtKey, conf.App.AllowIp, guardOpts, db, apiKeys, scraper, quoteCache, router.Prompts(), eventHandler, investImporter, recorder, hub, teleBotGroup)
 }
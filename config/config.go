@@ -2,13 +2,19 @@ package config
 
 import (
 	_ "embed"
+	"fmt"
 	"math/big"
+	"net/netip"
 	"time"
 
+	"investindicator/app/middleware"
 	"investindicator/blockchain/uniswap"
 	"investindicator/bot"
+	"investindicator/internal/cache"
 	"investindicator/internal/db"
+	m "investindicator/internal/model"
 	"investindicator/internal/util"
+	"investindicator/notify"
 	"investindicator/scrape"
 	"strconv"
 
@@ -25,9 +31,14 @@ type Config struct {
 	Log string `yaml:"log"`
 	App struct {
 		Port    int      `yaml:"port"`
-		AllowIp []string `yaml:"allowIp"`
+		AllowIp []string `yaml:"allowIp"` // CORS 허용 origin
 		JwtKey  string   `yaml:"jwtkey"`
-		Passkey string   `yaml:"passkey"`
+		Guard   struct {
+			Allow          []string          `yaml:"allow"`          // 접근 허용 CIDR. 미지정 시 전체 허용
+			Deny           []string          `yaml:"deny"`           // 접근 거부 CIDR
+			TrustedProxies []string          `yaml:"trustedProxies"` // X-Forwarded-For를 신뢰하는 proxy CIDR
+			Limit          map[string]string `yaml:"limit"`          // 요청 한도(횟수/기간). ip, user, login. ex) login: 10/1m
+		} `yaml:"guard"`
 	} `yaml:"app"`
 	ApiKey   map[string]string `yaml:"api-key"`
 	Telegram []struct {
@@ -35,6 +46,22 @@ type Config struct {
 		Token  string `yaml:"token"`
 	} `yaml:"telegram"`
 
+	Alert struct {
+		Window map[string]string `yaml:"window"` // 알림 종류별 중복 억제 시간. ex) buy: 6h
+	} `yaml:"alert"`
+
+	Notify struct {
+		Channels []NotifyChannel     `yaml:"channels"`
+		Routes   map[string][]string `yaml:"routes"`   // route 이름 - 채널 이름 목록. ex) errors: [telegram-0, mail]
+		Severity map[string]string   `yaml:"severity"` // route 이름 - severity(info, alert, error)
+		Digest   string              `yaml:"digest"`   // 반복 오류 요약 주기. ex) 10m
+		Throttle string              `yaml:"throttle"` // info 메시지 key별 최소 전송 간격. ex) 1m
+		Prompt   string              `yaml:"prompt"`   // 선택지 요청 기본 응답 대기 시간. ex) 10m
+	} `yaml:"notify"`
+
+	RateLimit map[string]float64 `yaml:"rate-limit"` // 시세 제공처별 초당 호출 한도. ex) kis: 15
+	QuoteTTL  map[string]string  `yaml:"quote-ttl"`  // 카테고리별 시세 캐시 유지 시간. ex) 국내코인: 10s
+
 	KIS   map[string]*string `yaml:"KIS"`
 	Upbit struct {
 		AccessKey string `yaml:"accesskey"`
@@ -123,6 +150,172 @@ func (c Config) LogLevel() (zerolog.Level, error) {
 	return level, nil
 }
 
+// NotifyChannel 알림 채널 설정. type : telegram, slack, discord, email, webhook
+type NotifyChannel struct {
+	Name     string            `yaml:"name"`
+	Type     string            `yaml:"type"`
+	Telegram int               `yaml:"telegram"` // telegram 설정 목록의 index
+	Url      string            `yaml:"url"`
+	Headers  map[string]string `yaml:"headers"`
+	Smtp     struct {
+		Host     string   `yaml:"host"`
+		Port     int      `yaml:"port"`
+		User     string   `yaml:"user"`
+		Password string   `yaml:"pwd"`
+		From     string   `yaml:"from"`
+		To       []string `yaml:"to"`
+		Subject  string   `yaml:"subject"`
+	} `yaml:"smtp"`
+}
+
+/*
+NotifyRouter 알림 채널 및 route 구성
+  - telegram 봇은 설정 여부와 관계 없이 telegram-{index} 이름의 채널로 등록
+  - route 미설정 시 기존 동작 유지 : alerts, errors => telegram-0 / dex => telegram-1
+*/
+func (c Config) NotifyRouter(tg *bot.TeleBotGroup) (*notify.Router, error) {
+
+	opts := make([]notify.RouterOption, 0)
+	if c.Notify.Prompt != "" {
+		d, err := time.ParseDuration(c.Notify.Prompt)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, notify.WithPromptTimeout(d))
+	}
+
+	r := notify.NewRouter(opts...)
+	tg.UsePrompts(r.Prompts())
+	for i := range tg.Len() {
+		if err := r.AddChannel(notify.NewTelegram(fmt.Sprintf("telegram-%d", i), tg.Bot(i))); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ch := range c.Notify.Channels {
+		var nc notify.Channel
+		switch ch.Type {
+		case "telegram":
+			if ch.Telegram < 0 || ch.Telegram >= tg.Len() {
+				return nil, fmt.Errorf("telegram 채널 %s의 index 오류. %d", ch.Name, ch.Telegram)
+			}
+			nc = notify.NewTelegram(ch.Name, tg.Bot(ch.Telegram))
+		case "slack":
+			nc = notify.NewSlack(ch.Name, ch.Url)
+		case "discord":
+			nc = notify.NewDiscord(ch.Name, ch.Url)
+		case "webhook":
+			nc = notify.NewWebhook(ch.Name, ch.Url, ch.Headers)
+		case "email":
+			email, err := notify.NewEmail(ch.Name, notify.EmailConfig{
+				Host:     ch.Smtp.Host,
+				Port:     ch.Smtp.Port,
+				User:     ch.Smtp.User,
+				Password: ch.Smtp.Password,
+				From:     ch.Smtp.From,
+				To:       ch.Smtp.To,
+				Subject:  ch.Smtp.Subject,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("email 채널 %s 설정 오류. %w", ch.Name, err)
+			}
+			nc = email
+		default:
+			return nil, fmt.Errorf("지원하지 않는 알림 채널 종류. %s", ch.Type)
+		}
+		if err := r.AddChannel(nc); err != nil {
+			return nil, err
+		}
+	}
+
+	routes := make(map[string][]string)
+	if tg.Len() > 0 {
+		routes[notify.Alerts] = []string{"telegram-0"}
+		routes[notify.Errors] = []string{"telegram-0"}
+		routes[notify.Dex] = []string{"telegram-0"}
+	}
+	if tg.Len() > 1 {
+		routes[notify.Dex] = []string{"telegram-1"}
+	}
+	for route, names := range c.Notify.Routes {
+		routes[route] = names
+	}
+	for route, names := range routes {
+		if err := r.SetRoute(route, names...); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func (c Config) DispatcherOptions() ([]notify.DispatcherOption, error) {
+
+	opts := make([]notify.DispatcherOption, 0)
+	for route, v := range c.Notify.Severity {
+		s, err := notify.ParseSeverity(v)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, notify.WithSeverity(route, s))
+	}
+	if c.Notify.Digest != "" {
+		d, err := time.ParseDuration(c.Notify.Digest)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, notify.WithDigestInterval(d))
+	}
+	if c.Notify.Throttle != "" {
+		d, err := time.ParseDuration(c.Notify.Throttle)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, notify.WithThrottleInterval(d))
+	}
+
+	return opts, nil
+}
+
+func (c Config) GuardOptions() ([]middleware.GuardOption, error) {
+
+	g := c.App.Guard
+	opts := make([]middleware.GuardOption, 0)
+	for _, li := range []struct {
+		cidrs []string
+		opt   func(...netip.Prefix) middleware.GuardOption
+	}{
+		{g.Allow, middleware.WithAllow},
+		{g.Deny, middleware.WithDeny},
+		{g.TrustedProxies, middleware.WithTrustedProxies},
+	} {
+		prefixes, err := middleware.ParsePrefixes(li.cidrs)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, li.opt(prefixes...))
+	}
+
+	for k, v := range g.Limit {
+		l, err := middleware.ParseLimit(v)
+		if err != nil {
+			return nil, err
+		}
+		switch k {
+		case "ip":
+			opts = append(opts, middleware.WithIpLimit(l))
+		case "user":
+			opts = append(opts, middleware.WithUserLimit(l))
+		case "login":
+			opts = append(opts, middleware.WithLoginLimit(l))
+		default:
+			return nil, fmt.Errorf("지원하지 않는 요청 한도. %s", k)
+		}
+	}
+
+	return opts, nil
+}
+
 func (c Config) BotConfigs() ([]*bot.TeleBotConfig, error) {
 
 	confs := make([]*bot.TeleBotConfig, len(c.Telegram))
@@ -140,6 +333,54 @@ func (c Config) BotConfigs() ([]*bot.TeleBotConfig, error) {
 	return confs, nil
 }
 
+func (c Config) AlertWindows() (map[cache.AlertType]time.Duration, error) {
+
+	windows := make(map[cache.AlertType]time.Duration)
+	for k, v := range c.Alert.Window {
+		t, err := cache.ParseAlertType(k)
+		if err != nil {
+			return nil, err
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+		windows[t] = d
+	}
+
+	return windows, nil
+}
+
+func (c Config) QuoteTTLs() (map[m.Category]time.Duration, error) {
+
+	ttls := make(map[m.Category]time.Duration)
+	for k, v := range c.QuoteTTL {
+		category, err := m.ToCategory(k)
+		if err != nil {
+			return nil, err
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+		ttls[category] = d
+	}
+
+	return ttls, nil
+}
+
+func (c Config) RateLimits() map[m.Provider]float64 {
+
+	limits := make(map[m.Provider]float64)
+	for k, v := range c.RateLimit {
+		if m.IsProvider(k) {
+			limits[m.Provider(k)] = v
+		}
+	}
+
+	return limits
+}
+
 func (c *Config) UniswapConfig(keyPasser KeyPasser) *uniswap.UniswapClientConfig {
 
 	var pk string
@@ -325,5 +566,4 @@ func decode(conf *Config) {
 	// util.Decode(&conf.Telegram.ChatId)
 	// util.Decode(&conf.Telegram.Token)
 	util.Decode(&conf.App.JwtKey)
-	util.Decode(&conf.App.Passkey)
 }
@@ -0,0 +1,380 @@
+package investind
+
+import (
+	"cmp"
+	"fmt"
+	"investindicator/internal/cache"
+	m "investindicator/internal/model"
+	"investindicator/notify"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+포트폴리오 요약 메시지
+  - 자금별 총액과 기간 변동, 중분류별 비중
+  - 보유 자산 중 변동률 상위 자산, 기간 내 발송된 알림, 지표 변화, 확인이 필요한 항목
+  - 기간 변동 기준은 기간 시작 전 마지막 기록(자금 평가 총액, EMA 이력의 종가, 일별 지표)
+  - 전체 자금을 담으므로 운영자 채널인 reports route로만 전송. 자금 권한별 사용자 발송 미지원
+*/
+
+const (
+	digestTopMovers = 5
+	digestLookback  = 7 // 기간 시작 전 기록 조회 시 추가로 조회하는 일수. 휴일 등으로 기록이 없는 날 대비
+)
+
+func (e InvestIndicator) runDailyDigestEvent(isManual WayOfLaunch) {
+	e.sendDigest("일간", 1)
+}
+
+func (e InvestIndicator) runWeeklyDigestEvent(isManual WayOfLaunch) {
+	e.sendDigest("주간", 7)
+}
+
+func (e InvestIndicator) sendDigest(title string, days int) {
+	e.lg.Info().Msgf("Starting DigestEvent. %s", title)
+
+	pm := make(map[uint]float64)
+	ivsmLi := make([]m.InvestSummary, 0)
+	e.updateAsset(pm, &ivsmLi)
+
+	ex := e.dp.ExchageRate()
+	if ex == 0 {
+		e.ms.SendMessage(notify.Errors, "[DigestEvent] ExchageRate 시 환율 값 0 반환")
+		return
+	}
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1-days)
+
+	msg, err := e.genDigestMsg(title, from, ivsmLi, pm, ex)
+	if err != nil {
+		e.lg.Error().Err(err).Msg("[DigestEvent] genDigestMsg 시, 에러 발생")
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("[DigestEvent] genDigestMsg 시, 에러 발생. %s", err))
+		return
+	}
+
+	e.ms.SendMessage(notify.Reports, msg)
+	e.lg.Info().Msgf("DigestEvent completed. %s", title)
+}
+
+// genDigestMsg from 이후 기간의 요약 메시지 생성. ivsmLi, pm은 updateAsset으로 갱신된 투자 내역과 현재가
+func (e InvestIndicator) genDigestMsg(title string, from time.Time, ivsmLi []m.InvestSummary, pm map[uint]float64, ex float64) (string, error) {
+
+	assets, err := e.stg.RetrieveAssetList()
+	if err != nil {
+		return "", fmt.Errorf("RetrieveAssetList 시 오류 발생. %w", err)
+	}
+	market, err := e.stg.RetrieveMarketStatus("")
+	if err != nil {
+		return "", fmt.Errorf("RetrieveMarketStatus 시 오류 발생. %w", err)
+	}
+	var level m.MarketLevel // 0이면 시장 단계 미설정. 비중 확인 생략
+	if market != nil {
+		level = m.MarketLevel(market.Status)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("[%s 포트폴리오 요약] %s ~ %s\n", title, from.Format("01-02"), time.Now().Format("01-02 15:04")))
+
+	// 1. 자금별 총액, 비중
+	funds := summarizeFunds(ivsmLi, ex)
+	actions := make([]string, 0)
+	sb.WriteString("\n■ 자금\n")
+	if len(funds) == 0 {
+		sb.WriteString("없음\n")
+	}
+	for _, f := range funds {
+		sb.WriteString(fmt.Sprintf("- %d %s : %s원", f.id, f.name, comma(f.total)))
+		if base, ok := e.baseNav(f.id, from); ok && base != 0 {
+			sb.WriteString(fmt.Sprintf(" (%s, %s원)", signedPct(100*(f.total-base)/base), signedComma(f.total-base)))
+		}
+		sb.WriteString("\n  " + f.allocation() + "\n")
+
+		if r := f.volatileRate(); level != 0 && (r < level.MinVolatileAssetRate() || r > level.MaxVolatileAssetRate()) {
+			actions = append(actions, fmt.Sprintf("- 자금 %d 변동 자산 비중 %.1f%% (%s 기준 %.0f~%.0f%%)",
+				f.id, 100*r, level, 100*level.MinVolatileAssetRate(), 100*level.MaxVolatileAssetRate()))
+		}
+	}
+
+	// 2. 보유 자산 변동률 상위
+	sb.WriteString("\n■ 주요 변동 자산\n")
+	movers := e.topMovers(ivsmLi, pm, from)
+	if len(movers) == 0 {
+		sb.WriteString("없음\n")
+	}
+	for _, mv := range movers {
+		sb.WriteString(fmt.Sprintf("- %s %s (%s)\n", mv.name, signedPct(mv.rate), strconv.FormatFloat(mv.price, 'f', -1, 64)))
+	}
+
+	// 3. 기간 내 발송 알림
+	names := make(map[uint]string, len(assets))
+	for _, a := range assets {
+		names[a.ID] = a.Name
+	}
+	sb.WriteString("\n■ 발송 알림\n")
+	fired := e.ac.Fired(from)
+	if len(fired) == 0 {
+		sb.WriteString("없음\n")
+	}
+	for _, a := range fired {
+		target := names[a.Id]
+		if a.Type == cache.Portfolio {
+			target = fmt.Sprintf("자금 %d", a.Id)
+		}
+		sb.WriteString(fmt.Sprintf("- %s %s %s", a.SentAt.Format("01-02 15:04"), strings.ToUpper(string(a.Type)), target))
+		if a.Price != 0 {
+			sb.WriteString(fmt.Sprintf(" @ %.2f", a.Price))
+		}
+		sb.WriteString("\n")
+	}
+
+	// 4. 지표 변화
+	sb.WriteString("\n■ 지표\n")
+	indicators, err := e.indicatorChanges(from)
+	if err != nil {
+		return "", err
+	}
+	if len(indicators) == 0 {
+		sb.WriteString("없음\n")
+	}
+	for _, l := range indicators {
+		sb.WriteString(l + "\n")
+	}
+
+	// 5. 확인 필요 항목. 포트폴리오 비중 이탈, 매수/매도 기준 도달
+	owned := make(map[uint]bool)
+	for _, is := range ivsmLi {
+		if is.Count > 0 {
+			owned[is.AssetID] = true
+		}
+	}
+	for _, a := range assets {
+		pp, ok := pm[a.ID]
+		if !ok {
+			continue
+		}
+		if a.BuyPrice >= pp {
+			actions = append(actions, fmt.Sprintf("- 매수 기준 도달 %s (현재 %.2f / 기준 %.2f)", a.Name, pp, a.BuyPrice))
+		} else if a.SellPrice != 0 && a.SellPrice <= pp && owned[a.ID] {
+			actions = append(actions, fmt.Sprintf("- 매도 기준 도달 %s (현재 %.2f / 기준 %.2f)", a.Name, pp, a.SellPrice))
+		}
+	}
+	sb.WriteString("\n■ 확인 필요\n")
+	if len(actions) == 0 {
+		sb.WriteString("없음\n")
+	}
+	for _, l := range actions {
+		sb.WriteString(l + "\n")
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+type fundDigest struct {
+	id       uint
+	name     string
+	total    float64
+	volatile float64
+	byMiddle map[string]float64
+}
+
+func (f fundDigest) volatileRate() float64 {
+	if f.total == 0 {
+		return 0
+	}
+	return f.volatile / f.total
+}
+
+// allocation 중분류별 비중. 비중이 큰 순
+func (f fundDigest) allocation() string {
+	keys := make([]string, 0, len(f.byMiddle))
+	for k := range f.byMiddle {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Compare(f.byMiddle[b], f.byMiddle[a])
+	})
+
+	li := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if f.total == 0 {
+			break
+		}
+		li = append(li, fmt.Sprintf("%s %.1f%%", k, 100*f.byMiddle[k]/f.total))
+	}
+	return strings.Join(li, " | ")
+}
+
+// summarizeFunds 자금별 원화 환산 총액, 중분류별 금액 집계. 미대상 자금 제외, 자금 id 순
+func summarizeFunds(ivsmLi []m.InvestSummary, ex float64) []fundDigest {
+	funds := make(map[uint]*fundDigest)
+	for _, is := range ivsmLi {
+		if is.Fund.IsExcept {
+			continue
+		}
+		f, ok := funds[is.FundID]
+		if !ok {
+			f = &fundDigest{id: is.FundID, name: is.Fund.Name, byMiddle: make(map[string]float64)}
+			funds[is.FundID] = f
+		}
+
+		v := is.Sum
+		if is.Asset.Currency == m.USD.String() {
+			v = is.Sum * ex
+		}
+		f.total += v
+		f.byMiddle[is.Asset.Category.GetMiddleCategory()] += v
+		if !is.Asset.Category.IsStable() {
+			f.volatile += v
+		}
+	}
+
+	rtn := make([]fundDigest, 0, len(funds))
+	for _, f := range funds {
+		rtn = append(rtn, *f)
+	}
+	slices.SortFunc(rtn, func(a, b fundDigest) int {
+		return cmp.Compare(a.id, b.id)
+	})
+	return rtn
+}
+
+// baseNav from 이전 마지막 자금 평가 총액
+func (e InvestIndicator) baseNav(fundId uint, from time.Time) (float64, bool) {
+	navs, err := e.stg.RetrieveFundNavs(fundId, from.AddDate(0, 0, -digestLookback))
+	if err != nil {
+		e.lg.Error().Err(err).Msg("[DigestEvent] RetrieveFundNavs 시, 에러 발생")
+		return 0, false
+	}
+
+	var base float64
+	found := false
+	for _, n := range navs {
+		if n.CreatedAt.Before(from) {
+			base = n.Amount
+			found = true
+		}
+	}
+	return base, found
+}
+
+type mover struct {
+	name  string
+	price float64
+	rate  float64
+}
+
+// topMovers 보유 자산의 기간 변동률 절대값 상위. 기준가는 from 이전 마지막 EMA 이력의 종가
+func (e InvestIndicator) topMovers(ivsmLi []m.InvestSummary, pm map[uint]float64, from time.Time) []mover {
+	seen := make(map[uint]bool)
+	movers := make([]mover, 0)
+	for _, is := range ivsmLi {
+		pp, ok := pm[is.AssetID]
+		if is.Count == 0 || seen[is.AssetID] || !ok {
+			continue
+		}
+		seen[is.AssetID] = true
+
+		hists, err := e.stg.RetrieveEmaHists(is.AssetID, from.AddDate(0, 0, -digestLookback))
+		if err != nil {
+			e.lg.Error().Err(err).Msg("[DigestEvent] RetrieveEmaHists 시, 에러 발생")
+			continue
+		}
+		var base float64
+		for _, h := range hists {
+			if h.Date.Before(from) && h.Price != 0 {
+				base = h.Price
+			}
+		}
+		if base == 0 {
+			continue
+		}
+		movers = append(movers, mover{name: is.Asset.Name, price: pp, rate: 100 * (pp - base) / base})
+	}
+
+	slices.SortFunc(movers, func(a, b mover) int {
+		return cmp.Compare(math.Abs(b.rate), math.Abs(a.rate))
+	})
+	if len(movers) > digestTopMovers {
+		movers = movers[:digestTopMovers]
+	}
+	return movers
+}
+
+// indicatorChanges from 이전 마지막 기록 대비 최신 지표 변화
+func (e InvestIndicator) indicatorChanges(from time.Time) ([]string, error) {
+	since := from.AddDate(0, 0, -digestLookback)
+	indexes, err := e.stg.RetrieveMarketIndicators(since)
+	if err != nil {
+		return nil, fmt.Errorf("RetrieveMarketIndicators 시 오류 발생. %w", err)
+	}
+	spreads, err := e.stg.RetrieveHighYieldSpreads(since)
+	if err != nil {
+		return nil, fmt.Errorf("RetrieveHighYieldSpreads 시 오류 발생. %w", err)
+	}
+
+	li := make([]string, 0, 4)
+	if len(indexes) > 0 {
+		cur := indexes[len(indexes)-1]
+		base := cur
+		for _, idx := range indexes {
+			if idx.CreatedAt.Before(from) {
+				base = idx
+			}
+		}
+		li = append(li,
+			fmt.Sprintf("- 공포탐욕 %d → %d (%+d)", base.FearGreedIndex, cur.FearGreedIndex, int(cur.FearGreedIndex)-int(base.FearGreedIndex)),
+			fmt.Sprintf("- 나스닥 %.2f (%s)", cur.NasDaq, signedPct(changeRate(base.NasDaq, cur.NasDaq))),
+			fmt.Sprintf("- S&P500 %.2f (%s)", cur.Sp500, signedPct(changeRate(base.Sp500, cur.Sp500))),
+		)
+	}
+	if len(spreads) > 0 {
+		cur := spreads[len(spreads)-1]
+		base := cur
+		for _, sp := range spreads {
+			if sp.CreatedAt.Before(from) {
+				base = sp
+			}
+		}
+		li = append(li, fmt.Sprintf("- 하이일드 스프레드 %.2f → %.2f (%+.2f)", base.Spread, cur.Spread, cur.Spread-base.Spread))
+	}
+	return li, nil
+}
+
+func changeRate(base, cur float64) float64 {
+	if base == 0 {
+		return 0
+	}
+	return 100 * (cur - base) / base
+}
+
+func signedPct(v float64) string {
+	return fmt.Sprintf("%+.2f%%", v)
+}
+
+func signedComma(v float64) string {
+	if v >= 0 {
+		return "+" + comma(v)
+	}
+	return comma(v)
+}
+
+// comma 정수부 천 단위 구분. ex) 1234567.8 => "1,234,568"
+func comma(v float64) string {
+	s := strconv.FormatFloat(math.Round(math.Abs(v)), 'f', 0, 64)
+	var sb strings.Builder
+	if v < 0 && s != "0" {
+		sb.WriteString("-")
+	}
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -1,6 +1,11 @@
 package investind
 
-import "github.com/robfig/cron"
+import (
+	m "investindicator/internal/model"
+	"time"
+
+	"github.com/robfig/cron"
+)
 
 // todo. db로 이동
 
@@ -26,8 +31,8 @@ func (e InvestIndicator) Run() {
 		e.runIndexEvent()
 		e.runEmaUpdateEvent()
 		e.runHighYieldSpreadEvent()
-		e.runAssetRecommendEvent(false)
 		e.runFindNewSP500Event()
+		e.runFundNavEvent()
 	})
 
 	// c.AddFunc(EstateSpec, e.RealEstateEvent)
@@ -38,7 +43,7 @@ func (e InvestIndicator) Run() {
 		}
 		c.AddFunc(enrolled.schedule, func() {
 			if enrolled.IsActive {
-				enrolled.Event(false)
+				e.launch(enrolled, Auto)
 			}
 		})
 	}
@@ -46,6 +51,7 @@ func (e InvestIndicator) Run() {
 	c.Start()
 
 	go e.runRecordMyOrdersEvent()
+	go e.runPriceStreamEvent()
 	// go e.runBlackholeDexStrategy()
 
 	e.lg.Info().Msg("EventHandler Run completed")
@@ -67,6 +73,24 @@ const (
 	Auto   WayOfLaunch = false
 )
 
+// launch 등록 이벤트 실행 후 실행 결과 전달
+// String event 실행 metric의 way label
+func (w WayOfLaunch) String() string {
+	if w == Manual {
+		return "manual"
+	}
+	return "auto"
+}
+
+func (e InvestIndicator) launch(ev *EnrolledEvent, way WayOfLaunch) {
+	started := time.Now()
+	ev.Event(way)
+	elapsed := time.Since(started)
+	observeEvent(ev, way, elapsed)
+	e.hs.eventDone(ev.Id)
+	e.publish(m.TopicEvent, 0, m.EventRun{ID: ev.Id, Title: ev.Title, Manual: bool(way), Started: started, Duration: elapsed})
+}
+
 func (e *InvestIndicator) registerEvents() {
 	e.enrolledEvents = []*EnrolledEvent{
 		{
@@ -111,6 +135,20 @@ func (e *InvestIndicator) registerEvents() {
 			schedule:    "0 0 12 * * 0-6",
 			Event:       e.runAvalancheSwap10TxEvent,
 		},
+		{
+			Id:          7,
+			Title:       "일간 포트폴리오 요약",
+			Description: "자금별 총액/변동/비중, 주요 변동 자산, 발송 알림, 지표 변화, 확인 필요 항목 요약.\n평일 오전 7시 30분 실행",
+			schedule:    "0 30 7 * * 1-5",
+			Event:       e.runDailyDigestEvent,
+		},
+		{
+			Id:          8,
+			Title:       "주간 포트폴리오 요약",
+			Description: "일간 요약과 같은 항목을 최근 7일 기준으로 요약.\n토요일 오전 9시 실행",
+			schedule:    "0 0 9 * * 6",
+			Event:       e.runWeeklyDigestEvent,
+		},
 		// 보류
 		// {
 		// 	Id:          5,
@@ -0,0 +1,97 @@
+package investind
+
+import (
+	"fmt"
+	m "investindicator/internal/model"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 동시 시세 조회 worker 수. 제공처별 호출 한도는 Poller(Scraper)의 rate limiter가 관리
+const fetchWorkers = 8
+
+type fetchFailure struct {
+	asset m.Asset
+	err   error
+}
+
+type fetchSummary struct {
+	total    int
+	failures []fetchFailure
+	elapsed  time.Duration
+}
+
+func (s fetchSummary) hasFailure() bool {
+	return len(s.failures) > 0
+}
+
+func (s fetchSummary) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("가격 조회 실패 %d/%d건 (소요 시간 %s)\n", len(s.failures), s.total, s.elapsed.Round(time.Millisecond)))
+	for _, f := range s.failures {
+		sb.WriteString(fmt.Sprintf("  - %s(ID %d, %s): %s\n", f.asset.Name, f.asset.ID, f.asset.Category.String(), f.err))
+	}
+	return sb.String()
+}
+
+// fetchPrices 자산 목록의 현재가를 동시에 조회. 일부 자산의 조회 실패는 summary에 모아서 반환하고 나머지는 계속 진행
+func (e InvestIndicator) fetchPrices(assets []m.Asset) (map[uint]float64, fetchSummary) {
+	start := time.Now()
+
+	jobs := make(chan m.Asset)
+	var mu sync.Mutex
+	pm := make(map[uint]float64, len(assets))
+	summary := fetchSummary{total: len(assets)}
+
+	var wg sync.WaitGroup
+	for range min(fetchWorkers, len(assets)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for a := range jobs {
+				q, err := e.qc.Quote(a.Category, a.Code, 0, a.ProviderChain()...)
+
+				mu.Lock()
+				if err != nil {
+					summary.failures = append(summary.failures, fetchFailure{asset: a, err: err})
+				} else {
+					pm[a.ID] = q.Price
+				}
+				mu.Unlock()
+
+				if err != nil {
+					e.lg.Error().Err(err).Uint("assetId", a.ID).Str("code", a.Code).Msg("[fetchPrices] PresentPrice 시, 에러 발생")
+				}
+			}
+		}()
+	}
+
+	for _, a := range assets {
+		jobs <- a
+	}
+	close(jobs)
+	wg.Wait()
+
+	summary.elapsed = time.Since(start)
+	e.lg.Info().
+		Int("total", summary.total).
+		Int("failed", len(summary.failures)).
+		Dur("elapsed", summary.elapsed).
+		Msg("fetchPrices completed")
+
+	return pm, summary
+}
+
+// rtFetcher 시세 캐시 미지정 시 rtPoller를 캐시 조회 대상으로 사용하기 위한 adapter
+type rtFetcher struct {
+	rt rtPoller
+}
+
+func (f rtFetcher) Quote(category m.Category, code string, providers ...m.Provider) (m.Quote, error) {
+	pp, err := f.rt.PresentPrice(category, code, providers...)
+	if err != nil {
+		return m.Quote{}, err
+	}
+	return m.Quote{Price: pp, FetchedAt: time.Now()}, nil
+}
@@ -0,0 +1,125 @@
+package investind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	m "investindicator/internal/model"
+	"sort"
+	"sync"
+	"time"
+)
+
+// stream별 연결 대상. 상태 확인 응답의 Detail
+var streamSources = map[string]string{
+	"StreamCoinOrders":   "Upbit 체결",
+	"StreamStockOrders":  "KIS 체결",
+	"StreamCoinTickers":  "Upbit 실시간 시세",
+	"StreamStockTickers": "KIS 실시간 시세",
+}
+
+type streamState struct {
+	connected bool
+	since     time.Time // 마지막 연결 시각
+	err       error
+}
+
+// healthState 실시간 stream 연결 상태와 이벤트별 마지막 실행 시각. nil이면 기록하지 않음
+type healthState struct {
+	mu      sync.RWMutex
+	streams map[string]*streamState
+	runs    map[uint]time.Time
+}
+
+func newHealthState() *healthState {
+	return &healthState{
+		streams: make(map[string]*streamState),
+		runs:    make(map[uint]time.Time),
+	}
+}
+
+func (h *healthState) streamUp(name string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.streams[name] = &streamState{connected: true, since: time.Now()}
+}
+
+func (h *healthState) streamDown(name string, err error) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	st, ok := h.streams[name]
+	if !ok {
+		st = &streamState{}
+		h.streams[name] = st
+	}
+	st.connected = false
+	st.err = err
+}
+
+// eventDone 이벤트 실행 종료. 이벤트는 결과를 반환하지 않아 성공 여부와 무관하게 기록. memo. 실행 중 panic 발생 시 미기록
+func (h *healthState) eventDone(id uint) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.runs[id] = time.Now()
+}
+
+func (h *healthState) lastRun(id uint) time.Time {
+	if h == nil {
+		return time.Time{}
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.runs[id]
+}
+
+/*
+Health 실시간 stream 연결 상태와 등록 이벤트별 마지막 실행 시각
+  - stream은 시작된 것만 보고. 연결이 끊겨 재연결 대기 중이면 down
+  - 이벤트는 실행 여부와 관계없이 up. 마지막 실행 시각은 LastRun으로 확인(실패한 실행 포함)
+*/
+func (e InvestIndicator) Health(ctx context.Context) []m.ComponentHealth {
+
+	rtn := make([]m.ComponentHealth, 0)
+
+	if e.hs != nil {
+		e.hs.mu.RLock()
+		names := make([]string, 0, len(e.hs.streams))
+		for name := range e.hs.streams {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			st := e.hs.streams[name]
+			h := m.ComponentHealth{Name: "stream:" + name, Status: m.HealthUp, Detail: streamSources[name], LastSuccess: st.since}
+			if !st.connected {
+				err := st.err
+				if err == nil {
+					err = errors.New("연결 종료")
+				}
+				h.Status = m.HealthDown
+				h.Error = err.Error()
+			}
+			rtn = append(rtn, h)
+		}
+		e.hs.mu.RUnlock()
+	}
+
+	for _, ev := range e.enrolledEvents {
+		rtn = append(rtn, m.ComponentHealth{
+			Name:    fmt.Sprintf("event:%d", ev.Id),
+			Status:  m.HealthUp,
+			Detail:  ev.Title,
+			LastRun: e.hs.lastRun(ev.Id),
+		})
+	}
+	return rtn
+}
@@ -1,25 +1,31 @@
 package investind
 
 import (
+	"context"
+	"investindicator/internal/audit"
+	"investindicator/internal/cache"
 	m "investindicator/internal/model"
+	"investindicator/notify"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
 type rtPoller interface { // realtime poller
-	PresentPrice(category m.Category, code string) (float64, error)
+	PresentPrice(category m.Category, code string, providers ...m.Provider) (float64, error)
 	RealEstateStatus() (string, error)
 	GoldPriceDollar() (float64, error)
 	AirdropEventUpbit() ([]string, []string, error)
 	AirdropEventBithumb() ([]string, []string, error)
 	StreamCoinOrders(c chan<- m.MyOrder) error
 	StreamStockOrders(c chan<- m.MyOrder) error
+	StreamCoinTickers(ctx context.Context, codes []string, c chan<- m.Tick) error
+	StreamStockTickers(ctx context.Context, domestic []string, overseas []string, c chan<- m.Tick) error
 }
 
 type dailyPoller interface {
 	ExchageRate() float64
-	ClosingPrice(category m.Category, code string) (float64, error)
+	ClosingPrice(category m.Category, code string, providers ...m.Provider) (float64, error)
 	FearGreedIndex() (uint, error)
 	Nasdaq() (float64, error)
 	Sp500() (float64, error)
@@ -39,16 +45,15 @@ type storage interface {
 	RetrieveAssetList() ([]m.Asset, error)
 	RetrieveAsset(id uint) (*m.Asset, error)
 	RetrieveTotalAssets() ([]m.Asset, error)
-	UpdateAssetInfo(asset m.Asset) error
+	UpdateAssetTopBottom(id uint, top float64, bottom float64) error
 	RetrieveAssetIdByCode(code string) uint
 
 	RetreiveFundsSummaryOrderByFundId() ([]m.InvestSummary, error)
 	RetreiveFundSummaryByFundId(fundId uint) ([]m.InvestSummary, error)
 	UpdateInvestSummarySum(fundId uint, assetId uint, sum float64) error
-	UpdateInvestSummary(fundId uint, assetId uint, change float64, price float64) error
 	RetreiveFundSummaryByAssetId(id uint) ([]m.InvestSummary, error)
 
-	SaveInvest(fundId uint, assetId uint, price float64, count float64) error
+	RecordInvests(invests []m.Invest, changes []m.SummaryChange) error
 
 	RetrieveMarketIndicator(date string) (*m.DailyIndex, *m.CliIndex, error)
 	SaveDailyMarketIndicator(fearGreedIndex uint, nasdaq float64, sp500 float64) error
@@ -60,11 +65,49 @@ type storage interface {
 	RetreiveLatestEma(assetId uint) (*m.EmaHist, error)
 	SaveEmaHist(newEma *m.EmaHist) error
 
+	SaveFundNav(fundId uint, amount float64) error
+	SavePremium(assetId uint, premium float64) error
+	RetrieveFundNavs(fundId uint, since time.Time) ([]m.FundNav, error)
+	RetrieveEmaHists(assetId uint, since time.Time) ([]m.EmaHist, error)
+	RetrieveMarketIndicators(since time.Time) ([]m.DailyIndex, error)
+	RetrieveHighYieldSpreads(since time.Time) ([]m.HighYieldSpread, error)
+
 	RetreiveEventIsActive(eventId uint) bool
 	UpdateEventIsActive(eventId uint, isActive bool) error
 
 	SetCache(key string, value interface{}, exp time.Duration)
 	GetCache(key string) *redis.StringCmd
+	CacheKeys(pattern string) ([]string, error)
+}
+
+type alertCache interface {
+	Has(t cache.AlertType, id uint, price float64) bool
+	Set(t cache.AlertType, id uint, price float64)
+	Suppressed() []cache.SuppressedAlert
+	Fired(since time.Time) []cache.SuppressedAlert
+}
+
+// memo. quote.Cache가 구현. maxAge가 0이면 카테고리 TTL 기준
+type quoter interface {
+	Quote(category m.Category, code string, maxAge time.Duration, providers ...m.Provider) (m.Quote, error)
+	Put(category m.Category, code string, q m.Quote)
+}
+
+// memo. pricebus.Bus가 구현
+type priceBus interface {
+	Publish(t m.Tick)
+	Subscribe(buf int) (<-chan m.Tick, func())
+	Forward(c <-chan m.Tick)
+}
+
+// memo. push.Hub가 구현. fundId가 0이 아니면 해당 자금 조회 권한이 있는 구독자에게만 전달
+type publisher interface {
+	Publish(topic m.Topic, fundId uint, data any)
+}
+
+// memo. audit.Recorder가 구현
+type auditor interface {
+	Record(actor m.Actor, entries ...audit.Entry)
 }
 
 type trader interface {
@@ -76,7 +119,8 @@ type bcTrader interface { // blockchain trader
 	RunBlackholeDexStrategy(reportChan chan<- string) error
 }
 
+// memo. notify.Router가 구현. route는 notify.Alerts, notify.Errors 등 route 이름
 type messenger interface {
-	SendMessage(idx int, msg string)
-	SendButtonsAndGetResult(idx int, prompt string, options ...string) (answer string, err error)
+	SendMessage(route string, msg string)
+	Ask(route string, q notify.Question) (answer string, err error)
 }
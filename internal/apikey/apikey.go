@@ -0,0 +1,172 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	m "investindicator/internal/model"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// keyPrefix 발급 키 앞에 붙는 구분자. 로그, 저장소에 키가 노출된 경우 식별용
+const keyPrefix = "ik_"
+
+// touchInterval 마지막 사용 시각 갱신 주기. 요청마다 DB에 기록하지 않도록 제한
+const touchInterval = time.Minute
+
+var (
+	ErrInvalidKey = errors.New("유효하지 않은 API 키")
+	ErrKeyExpired = errors.New("만료 혹은 폐기된 API 키")
+)
+
+// memo. db.Storage가 구현
+type store interface {
+	CreateApiKey(key *m.ApiKey) error
+	ApiKeyByPrefix(prefix string) (*m.ApiKey, error)
+	ApiKeys() ([]m.ApiKey, error)
+	RevokeApiKey(id uint, at time.Time) error
+	TouchApiKey(id uint, at time.Time) error
+}
+
+/*
+Manager API 키 발급, 검증
+  - 키 형식: ik_{prefix}.{secret}. prefix로 조회 후 secret hash 비교
+  - 폐기, 만료된 키는 거부
+  - 마지막 사용 시각은 touchInterval 주기로 기록
+*/
+type Manager struct {
+	stg store
+	lg  zerolog.Logger
+}
+
+func NewManager(stg store) *Manager {
+	return &Manager{
+		stg: stg,
+		lg:  zerolog.New(os.Stdout).With().Str("Module", "ApiKey").Timestamp().Logger(),
+	}
+}
+
+// Issue 새 키 발급. 키 원문은 반환 값으로만 전달되고 저장되지 않음
+func (k *Manager) Issue(name string, scopes []m.Scope, expiresAt *time.Time) (*m.ApiKey, string, error) {
+
+	if len(scopes) == 0 {
+		return nil, "", errors.New("scope 미지정")
+	}
+
+	prefix, err := random(6)
+	if err != nil {
+		return nil, "", fmt.Errorf("API 키 prefix 생성 시 오류 발생. %w", err)
+	}
+	secret, err := random(32)
+	if err != nil {
+		return nil, "", fmt.Errorf("API 키 secret 생성 시 오류 발생. %w", err)
+	}
+
+	key := &m.ApiKey{
+		Name:      name,
+		Prefix:    prefix,
+		Hash:      hash(secret),
+		Scopes:    m.JoinScopes(scopes),
+		ExpiresAt: expiresAt,
+	}
+	err = k.stg.CreateApiKey(key)
+	if err != nil {
+		return nil, "", fmt.Errorf("CreateApiKey 시 오류 발생. %w", err)
+	}
+
+	k.lg.Info().Str("name", name).Str("prefix", prefix).Str("scopes", key.Scopes).Msg("API 키 발급")
+	return key, keyPrefix + prefix + "." + secret, nil
+}
+
+// Provision 같은 이름의 사용 중인 키를 폐기하고 새로 발급. 기동 시 내부 클라이언트(bot) 키 발급용
+func (k *Manager) Provision(name string, scopes ...m.Scope) (string, error) {
+
+	keys, err := k.stg.ApiKeys()
+	if err != nil {
+		return "", fmt.Errorf("ApiKeys 시 오류 발생. %w", err)
+	}
+	now := time.Now()
+	for _, key := range keys {
+		if key.Name == name && key.Active(now) {
+			if err := k.Revoke(key.ID); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	_, raw, err := k.Issue(name, scopes, nil)
+	return raw, err
+}
+
+// Verify 키 원문 검증
+func (k *Manager) Verify(raw string) (*m.ApiKey, error) {
+
+	prefix, secret, ok := strings.Cut(strings.TrimPrefix(raw, keyPrefix), ".")
+	if !strings.HasPrefix(raw, keyPrefix) || !ok || prefix == "" || secret == "" {
+		return nil, ErrInvalidKey
+	}
+
+	key, err := k.stg.ApiKeyByPrefix(prefix)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hash(secret))) != 1 {
+		return nil, ErrInvalidKey
+	}
+
+	now := time.Now()
+	if !key.Active(now) {
+		return nil, ErrKeyExpired
+	}
+
+	k.touch(key, now)
+	return key, nil
+}
+
+func (k *Manager) Keys() ([]m.ApiKey, error) {
+	return k.stg.ApiKeys()
+}
+
+func (k *Manager) Revoke(id uint) error {
+
+	err := k.stg.RevokeApiKey(id, time.Now())
+	if err != nil {
+		return fmt.Errorf("RevokeApiKey 시 오류 발생. %w", err)
+	}
+
+	k.lg.Info().Uint("id", id).Msg("API 키 폐기")
+	return nil
+}
+
+func (k *Manager) touch(key *m.ApiKey, now time.Time) {
+
+	if key.LastUsedAt != nil && now.Sub(*key.LastUsedAt) < touchInterval {
+		return
+	}
+	if err := k.stg.TouchApiKey(key.ID, now); err != nil {
+		k.lg.Warn().Err(err).Uint("id", key.ID).Msg("API 키 사용 시각 기록 실패")
+		return
+	}
+	key.LastUsedAt = &now
+}
+
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func random(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,94 @@
+package apikey
+
+import (
+	"fmt"
+	m "investindicator/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type storeMock struct {
+	keys    []m.ApiKey
+	touched int
+}
+
+func (s *storeMock) CreateApiKey(key *m.ApiKey) error {
+	key.ID = uint(len(s.keys) + 1)
+	s.keys = append(s.keys, *key)
+	return nil
+}
+
+func (s *storeMock) ApiKeyByPrefix(prefix string) (*m.ApiKey, error) {
+	for _, k := range s.keys {
+		if k.Prefix == prefix {
+			return &k, nil
+		}
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+func (s *storeMock) ApiKeys() ([]m.ApiKey, error) {
+	return s.keys, nil
+}
+
+func (s *storeMock) RevokeApiKey(id uint, at time.Time) error {
+	s.keys[id-1].RevokedAt = &at
+	return nil
+}
+
+func (s *storeMock) TouchApiKey(id uint, at time.Time) error {
+	s.touched++
+	s.keys[id-1].LastUsedAt = &at
+	return nil
+}
+
+func TestApiKey(t *testing.T) {
+
+	stg := &storeMock{}
+	k := NewManager(stg)
+
+	t.Run("Verify", func(t *testing.T) {
+		key, raw, err := k.Issue("script", []m.Scope{m.ScopeRead, m.ScopeInvest}, nil)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(raw, keyPrefix+key.Prefix+"."))
+		assert.NotContains(t, key.Hash, strings.Split(raw, ".")[1], "원문 미저장")
+
+		verified, err := k.Verify(raw)
+		assert.NoError(t, err)
+		assert.True(t, verified.HasScope(m.ScopeInvest))
+		assert.False(t, verified.HasScope(m.ScopeTrading))
+
+		k.Verify(raw)
+		assert.Equal(t, 1, stg.touched, "사용 시각은 주기적으로만 기록")
+
+		for _, invalid := range []string{"", raw + "x", strings.TrimPrefix(raw, keyPrefix), keyPrefix + key.Prefix + ".", "ik_unknown.secret"} {
+			_, err := k.Verify(invalid)
+			assert.ErrorIs(t, err, ErrInvalidKey, invalid)
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		_, raw, _ := k.Issue("expired", []m.Scope{m.ScopeRead}, &past)
+		_, err := k.Verify(raw)
+		assert.ErrorIs(t, err, ErrKeyExpired)
+	})
+
+	t.Run("Provision", func(t *testing.T) {
+		first, err := k.Provision("telegram", m.ScopeRead, m.ScopeTelegram)
+		assert.NoError(t, err)
+		second, err := k.Provision("telegram", m.ScopeRead, m.ScopeTelegram)
+		assert.NoError(t, err)
+
+		_, err = k.Verify(first)
+		assert.ErrorIs(t, err, ErrKeyExpired, "이전 키 폐기")
+		_, err = k.Verify(second)
+		assert.NoError(t, err)
+	})
+
+	_, _, err := k.Issue("empty", nil, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	m "investindicator/internal/model"
+	"os"
+
+	"github.com/rs/zerolog"
+)
+
+// memo. db.Storage가 구현
+type store interface {
+	SaveAuditLog(log *m.AuditLog) error
+	RetrieveAuditLogs(q m.AuditQuery) (*m.List[m.AuditLog], error)
+}
+
+// Entry 변경 대상과 변경 전후 값. Before, After는 JSON으로 저장하며 nil이면 빈 값
+type Entry struct {
+	Action   m.AuditAction
+	Entity   string
+	EntityID any
+	Before   any
+	After    any
+}
+
+/*
+Recorder 상태 변경 이력 저장
+  - API, bot, 자동 실행 이벤트가 같은 Recorder로 기록
+  - 저장 실패는 변경 자체를 되돌리지 않도록 로그만 남김
+*/
+type Recorder struct {
+	stg store
+	lg  zerolog.Logger
+}
+
+func NewRecorder(stg store) *Recorder {
+	return &Recorder{
+		stg: stg,
+		lg:  zerolog.New(os.Stdout).With().Str("Module", "Audit").Timestamp().Logger(),
+	}
+}
+
+func (r *Recorder) Record(actor m.Actor, entries ...Entry) {
+
+	for _, e := range entries {
+		log := &m.AuditLog{
+			ActorType: actor.Type,
+			ActorID:   actor.ID,
+			Source:    actor.Source,
+			Action:    e.Action,
+			Entity:    e.Entity,
+			Before:    encode(e.Before),
+			After:     encode(e.After),
+			RequestID: actor.RequestID,
+		}
+		if e.EntityID != nil {
+			log.EntityID = fmt.Sprint(e.EntityID)
+		}
+
+		if err := r.stg.SaveAuditLog(log); err != nil {
+			r.lg.Error().Err(err).Str("actor", string(actor.Type)+":"+actor.ID).Str("entity", e.Entity).Str("entity_id", log.EntityID).
+				Str("action", string(e.Action)).Msg("상태 변경 이력 저장 실패")
+		}
+	}
+}
+
+func (r *Recorder) Logs(q m.AuditQuery) (*m.List[m.AuditLog], error) {
+	return r.stg.RetrieveAuditLogs(q)
+}
+
+// System 자동 실행 이벤트 주체
+func System(event string) m.Actor {
+	return m.Actor{Type: m.ActorSystem, ID: event, Source: m.SourceEvent}
+}
+
+func encode(v any) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	return string(b)
+}
@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"errors"
+	m "investindicator/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type storeMock struct {
+	logs []m.AuditLog
+	fail bool
+}
+
+func (s *storeMock) SaveAuditLog(log *m.AuditLog) error {
+	if s.fail {
+		return errors.New("save failed")
+	}
+	log.ID = uint(len(s.logs) + 1)
+	s.logs = append(s.logs, *log)
+	return nil
+}
+
+func (s *storeMock) RetrieveAuditLogs(q m.AuditQuery) (*m.List[m.AuditLog], error) {
+	return &m.List[m.AuditLog]{Items: s.logs, Total: int64(len(s.logs))}, nil
+}
+
+func TestRecorder(t *testing.T) {
+
+	stg := &storeMock{}
+	r := NewRecorder(stg)
+	actor := m.Actor{Type: m.ActorUser, ID: "1", Source: m.SourceApi, RequestID: "req"}
+
+	r.Record(actor,
+		Entry{Action: m.AuditUpdate, Entity: "asset", EntityID: uint(3), Before: map[string]float64{"top": 1}, After: map[string]float64{"top": 2}},
+		Entry{Action: m.AuditUpdate, Entity: "market", After: map[string]int{"status": 4}},
+	)
+	assert.Len(t, stg.logs, 2)
+
+	log := stg.logs[0]
+	assert.Equal(t, m.ActorUser, log.ActorType)
+	assert.Equal(t, "1", log.ActorID)
+	assert.Equal(t, "req", log.RequestID)
+	assert.Equal(t, "3", log.EntityID)
+	assert.Equal(t, `{"top":1}`, log.Before)
+	assert.Equal(t, `{"top":2}`, log.After)
+
+	assert.Empty(t, stg.logs[1].EntityID)
+	assert.Empty(t, stg.logs[1].Before, "nil이면 빈 값")
+
+	r.Record(System("record_my_orders"), Entry{Action: m.AuditCreate, Entity: "invest"})
+	assert.Equal(t, m.ActorSystem, stg.logs[2].ActorType)
+	assert.Equal(t, m.SourceEvent, stg.logs[2].Source)
+
+	stg.fail = true
+	assert.NotPanics(t, func() { r.Record(actor, Entry{Action: m.AuditDelete, Entity: "asset"}) }, "저장 실패는 로그만 기록")
+	assert.Len(t, stg.logs, 3)
+}
@@ -1,80 +1,267 @@
 package cache
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
-type assetMsg struct {
-	assetId uint
-	isSell  bool
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+// AlertType 알림 종류. 종류별로 중복 억제 시간(window)을 따로 가짐
+type AlertType string
+
+const (
+	AssetBuy  AlertType = "buy"
+	AssetSell AlertType = "sell"
+	Portfolio AlertType = "portfolio"
+)
+
+const (
+	keyPrefix = "alert:"
+	histLen   = 1000 // 메모리에 보관하는 발송 이력 최대 개수
+)
+
+var defaultWindows = map[AlertType]time.Duration{
+	AssetBuy:  6 * time.Hour,
+	AssetSell: 6 * time.Hour,
+	Portfolio: 2 * time.Hour,
 }
 
-type assetMsgSentInfo struct {
-	isMsgSent bool
-	price     float64
-	sentTime  time.Time
+// memo. db.Storage가 구현. Redis 미연결/오류 시에는 메모리 캐시만으로 동작
+type store interface {
+	SetCache(key string, value interface{}, exp time.Duration)
+	GetCache(key string) *redis.StringCmd
+	CacheKeys(pattern string) ([]string, error)
 }
 
-var assetMsgCache map[assetMsg]*assetMsgSentInfo
-var portMsgCache map[uint]time.Time
+type entry struct {
+	Type   AlertType `json:"type"`
+	Id     uint      `json:"id"`
+	Price  float64   `json:"price"`
+	SentAt time.Time `json:"sent_at"`
+	Until  time.Time `json:"until"`
+}
 
-// var dailyCache int
+// SuppressedAlert 현재 중복 억제 중인 알림
+type SuppressedAlert struct {
+	Type      AlertType
+	Id        uint
+	Price     float64
+	SentAt    time.Time
+	ExpiresAt time.Time
+}
 
-func init() {
-	assetMsgCache = make(map[assetMsg]*assetMsgSentInfo)
-	portMsgCache = make(map[uint]time.Time)
+type AlertCache struct {
+	stg     store
+	windows map[AlertType]time.Duration
+	mu      sync.RWMutex
+	mem     map[string]entry
+	hist    []entry // 발송 이력. 재기동 시 초기화
+	lg      zerolog.Logger
 }
 
-func HasMsgCache(assetId uint, isSell bool, price float64) bool {
+type Option func(*AlertCache)
 
-	cache := assetMsgCache[assetMsg{
-		assetId: assetId,
-		isSell:  isSell,
-	}]
+func WithWindow(t AlertType, d time.Duration) Option {
+	return func(c *AlertCache) {
+		if d > 0 {
+			c.windows[t] = d
+		}
+	}
+}
 
-	if cache == nil { // || cache.sentTime.IsZero() sentTime이 미존재할 경우는 없음
-		return false
+func WithWindows(windows map[AlertType]time.Duration) Option {
+	return func(c *AlertCache) {
+		for t, d := range windows {
+			WithWindow(t, d)(c)
+		}
+	}
+}
+
+func NewAlertCache(stg store, opts ...Option) *AlertCache {
+	c := &AlertCache{
+		stg:     stg,
+		windows: make(map[AlertType]time.Duration),
+		mem:     make(map[string]entry),
+		lg:      zerolog.New(os.Stdout).With().Str("Module", "AlertCache").Timestamp().Logger(),
+	}
+	for t, d := range defaultWindows {
+		c.windows[t] = d
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *AlertCache) Window(t AlertType) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.windows[t]
+}
+
+// Has 동일 종류/대상/가격의 알림이 window 내에 전송된 적 있는지 확인. 가격 기준이 바뀌면 새 알림으로 취급
+func (c *AlertCache) Has(t AlertType, id uint, price float64) bool {
+	k := key(t, id)
+
+	c.mu.RLock()
+	e, ok := c.mem[k]
+	c.mu.RUnlock()
 
-	diff := time.Since(cache.sentTime)
-	if diff <= 6*time.Hour { // 유효한 캐시
-		return cache.price == price && cache.isMsgSent
+	if !ok {
+		e, ok = c.load(k)
+		if ok {
+			c.mu.Lock()
+			c.mem[k] = e
+			c.mu.Unlock()
+		}
 	}
 
-	return false
+	if !ok || time.Now().After(e.Until) {
+		return false
+	}
+	return e.Price == price
 }
 
-func SetMsgCache(assetId uint, isSell bool, price float64) {
+func (c *AlertCache) Set(t AlertType, id uint, price float64) {
+	now := time.Now()
+	window := c.Window(t)
+	e := entry{
+		Type:   t,
+		Id:     id,
+		Price:  price,
+		SentAt: now,
+		Until:  now.Add(window),
+	}
+	k := key(t, id)
+
+	c.mu.Lock()
+	c.mem[k] = e
+	c.hist = append(c.hist, e)
+	if len(c.hist) > histLen {
+		c.hist = c.hist[len(c.hist)-histLen:]
+	}
+	c.mu.Unlock()
 
-	k := assetMsg{
-		assetId: assetId,
-		isSell:  isSell,
+	if c.stg == nil {
+		return
 	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		c.lg.Error().Err(err).Str("key", k).Msg("알림 캐시 직렬화 실패")
+		return
+	}
+	c.stg.SetCache(k, b, window)
+}
+
+// Suppressed 현재 억제 중인 알림 목록. Redis와 메모리 캐시를 합쳐서 반환
+func (c *AlertCache) Suppressed() []SuppressedAlert {
+	now := time.Now()
+	entries := make(map[string]entry)
 
-	cache := assetMsgCache[k]
+	if c.stg != nil {
+		keys, err := c.stg.CacheKeys(keyPrefix + "*")
+		if err != nil {
+			c.lg.Warn().Err(err).Msg("Redis 알림 캐시 키 조회 실패. 메모리 캐시만 사용")
+		}
+		for _, k := range keys {
+			if e, ok := c.load(k); ok {
+				entries[k] = e
+			}
+		}
+	}
 
-	if cache == nil {
-		assetMsgCache[k] = &assetMsgSentInfo{
-			isMsgSent: true,
-			price:     price,
-			sentTime:  time.Now(),
+	c.mu.Lock()
+	for k, e := range c.mem {
+		if now.After(e.Until) {
+			delete(c.mem, k) // 만료된 항목 정리
+			continue
+		}
+		if _, ok := entries[k]; !ok {
+			entries[k] = e
 		}
-	} else {
-		cache.price = price
-		cache.sentTime = time.Now()
 	}
+	c.mu.Unlock()
 
+	rtn := make([]SuppressedAlert, 0, len(entries))
+	for _, e := range entries {
+		if now.After(e.Until) {
+			continue
+		}
+		rtn = append(rtn, SuppressedAlert{
+			Type:      e.Type,
+			Id:        e.Id,
+			Price:     e.Price,
+			SentAt:    e.SentAt,
+			ExpiresAt: e.Until,
+		})
+	}
+	sort.Slice(rtn, func(i, j int) bool {
+		return rtn[i].ExpiresAt.Before(rtn[j].ExpiresAt)
+	})
+	return rtn
 }
 
-func HasPortCache(id uint) bool {
+// Fired since 이후 발송된 알림 목록. 발송 순
+func (c *AlertCache) Fired(since time.Time) []SuppressedAlert {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	sendTime := portMsgCache[id]
+	rtn := make([]SuppressedAlert, 0)
+	for _, e := range c.hist {
+		if e.SentAt.Before(since) {
+			continue
+		}
+		rtn = append(rtn, SuppressedAlert{
+			Type:      e.Type,
+			Id:        e.Id,
+			Price:     e.Price,
+			SentAt:    e.SentAt,
+			ExpiresAt: e.Until,
+		})
+	}
+	return rtn
+}
 
-	if (sendTime == time.Time{} || sendTime.Before(time.Now().Add(-2*time.Hour))) { // 보낸 시간이 2시간보다 전이라면
-		return false
-	} else {
-		return true
+func (c *AlertCache) load(k string) (e entry, ok bool) {
+	if c.stg == nil {
+		return e, false
+	}
+
+	cmd := c.stg.GetCache(k)
+	if cmd == nil {
+		return e, false
 	}
+	b, err := cmd.Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			c.lg.Warn().Err(err).Str("key", k).Msg("Redis 알림 캐시 조회 실패. 메모리 캐시 사용")
+		}
+		return e, false
+	}
+	if err := json.Unmarshal(b, &e); err != nil {
+		c.lg.Error().Err(err).Str("key", k).Msg("알림 캐시 역직렬화 실패")
+		return e, false
+	}
+	return e, true
 }
 
-func SetPortCache(id uint) {
-	portMsgCache[id] = time.Now()
+func key(t AlertType, id uint) string {
+	return fmt.Sprintf("%s%s:%d", keyPrefix, t, id)
+}
+
+// ParseAlertType config 등 문자열 입력을 AlertType으로 변환
+func ParseAlertType(s string) (AlertType, error) {
+	t := AlertType(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := defaultWindows[t]; !ok {
+		return "", errors.New("존재하지 않는 알림 종류. 입력 값 :" + s)
+	}
+	return t, nil
 }
@@ -1,71 +1,165 @@
 package cache
 
 import (
+	"errors"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
-func TestCache(t *testing.T) {
+type storeMock struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	err  error
+}
 
-	k1 := assetMsg{
-		assetId: 1,
-		isSell:  true,
-	}
-	k2 := assetMsg{
-		assetId: 2,
-		isSell:  false,
+func (s *storeMock) SetCache(key string, value interface{}, exp time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
 	}
+	s.data[key] = value.([]byte)
+}
 
-	assetMsgCache[k1] = &assetMsgSentInfo{
-		isMsgSent: true,
-		price:     1000,
-		sentTime:  time.Now().Add(-1 * 2 * time.Hour),
+func (s *storeMock) GetCache(key string) *redis.StringCmd {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return redis.NewStringResult("", s.err)
 	}
+	v, ok := s.data[key]
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	return redis.NewStringResult(string(v), nil)
+}
 
-	assetMsgCache[k2] = &assetMsgSentInfo{
-		isMsgSent: true,
-		price:     1000,
-		sentTime:  time.Now().Add(-1 * 25 * time.Hour),
+func (s *storeMock) CacheKeys(pattern string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
 	}
+	keys := make([]string, 0)
+	for k := range s.data {
+		if strings.HasPrefix(k, strings.TrimSuffix(pattern, "*")) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func TestCache(t *testing.T) {
+
+	stg := &storeMock{data: make(map[string][]byte)}
+	c := NewAlertCache(stg, WithWindow(Portfolio, time.Hour))
+
+	c.mem[key(AssetSell, 1)] = entry{Type: AssetSell, Id: 1, Price: 1000, SentAt: time.Now().Add(-2 * time.Hour), Until: time.Now().Add(4 * time.Hour)}
+	c.mem[key(AssetBuy, 2)] = entry{Type: AssetBuy, Id: 2, Price: 1000, SentAt: time.Now().Add(-25 * time.Hour), Until: time.Now().Add(-19 * time.Hour)}
 
 	t.Run("Valid Cache", func(t *testing.T) {
-		b := HasMsgCache(1, true, 1000)
+		b := c.Has(AssetSell, 1, 1000)
 		if !b {
 			t.Error(b)
 		}
 	})
 
+	t.Run("Price Changed", func(t *testing.T) {
+		b := c.Has(AssetSell, 1, 900)
+		if b {
+			t.Error(b)
+		}
+	})
+
 	t.Run("No Cache", func(t *testing.T) {
-		b := HasMsgCache(1, false, 1000)
+		b := c.Has(AssetBuy, 1, 1000)
 		if b {
 			t.Error(b)
 		}
 	})
 
 	t.Run("Invalid Cache", func(t *testing.T) {
-		b := HasMsgCache(2, false, 1000)
+		b := c.Has(AssetBuy, 2, 1000)
 		if b {
 			t.Error(b)
 		}
 	})
 
 	t.Run("Set Cache", func(t *testing.T) {
-		b := HasMsgCache(3, true, 1000)
+		b := c.Has(AssetSell, 3, 1000)
 		if b {
 			t.Error(b)
 		}
 
-		SetMsgCache(3, true, 1000)
+		c.Set(AssetSell, 3, 1000)
 
-		b = HasMsgCache(3, true, 1000)
+		b = c.Has(AssetSell, 3, 1000)
 		if !b {
 			t.Error(b)
 		}
 	})
 
-	// t.Run("Daily Cache", func(t *testing.T) {
-	// 	b := hasDailyCache()
-	// 	assert.Equal(t, b, false)
-	// 	t.Log(time.Now().Day())
-	// })
+	t.Run("Restart", func(t *testing.T) {
+		restarted := NewAlertCache(stg)
+		if !restarted.Has(AssetSell, 3, 1000) {
+			t.Error("redis에 저장된 캐시가 재기동 후 조회되지 않음")
+		}
+	})
+
+	t.Run("Window", func(t *testing.T) {
+		c.Set(Portfolio, 1, 0)
+		e := c.mem[key(Portfolio, 1)]
+		if e.Until.Sub(e.SentAt) != time.Hour {
+			t.Error(e.Until.Sub(e.SentAt))
+		}
+	})
+
+	t.Run("Suppressed", func(t *testing.T) {
+		li := c.Suppressed()
+		if len(li) != 3 { // sell:1, sell:3, portfolio:1
+			t.Error(li)
+		}
+	})
+
+	t.Run("Fired", func(t *testing.T) {
+		li := c.Fired(time.Now().Add(-time.Hour))
+		if len(li) != 2 || li[0].Type != AssetSell || li[1].Type != Portfolio { // Set으로 발송된 sell:3, portfolio:1
+			t.Error(li)
+		}
+		if li := c.Fired(time.Now().Add(time.Minute)); len(li) != 0 {
+			t.Error(li)
+		}
+	})
+}
+
+func TestCacheFallback(t *testing.T) {
+
+	stg := &storeMock{data: make(map[string][]byte), err: errors.New("connection refused")}
+	c := NewAlertCache(stg)
+
+	c.Set(AssetBuy, 1, 500)
+	if !c.Has(AssetBuy, 1, 500) {
+		t.Error("redis 오류 시 메모리 캐시 미사용")
+	}
+	if len(c.Suppressed()) != 1 {
+		t.Error(c.Suppressed())
+	}
+
+	t.Run("Concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := range 50 {
+			wg.Add(1)
+			go func(id uint) {
+				defer wg.Done()
+				c.Set(AssetSell, id, 100)
+				c.Has(AssetSell, id, 100)
+				c.Suppressed()
+			}(uint(i))
+		}
+		wg.Wait()
+	})
 }
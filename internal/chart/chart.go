@@ -0,0 +1,113 @@
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	gochart "github.com/wcharczuk/go-chart/v2"
+	"github.com/wcharczuk/go-chart/v2/drawing"
+)
+
+/*
+시계열 PNG 차트 렌더링
+  - 기본 폰트가 한글을 지원하지 않으므로 제목, 범례는 영문 사용
+*/
+
+const (
+	width  = 1024
+	height = 512
+)
+
+var ErrNoData = errors.New("차트 데이터 부족")
+
+// Line 시계열. Secondary면 오른쪽 y축 사용
+type Line struct {
+	Name      string
+	Times     []time.Time
+	Values    []float64
+	Secondary bool
+}
+
+// Level 가로 기준선. ex) 매수/매도 가격
+type Level struct {
+	Name  string
+	Value float64
+}
+
+type Spec struct {
+	Title  string
+	Lines  []Line
+	Levels []Level
+}
+
+var levelColors = []drawing.Color{
+	{R: 0x2e, G: 0x7d, B: 0x32, A: 0xff}, // green
+	{R: 0xc6, G: 0x28, B: 0x28, A: 0xff}, // red
+	{R: 0xef, G: 0x6c, B: 0x00, A: 0xff}, // orange
+	{R: 0x6a, G: 0x1b, B: 0x9a, A: 0xff}, // purple
+}
+
+// Render PNG 렌더링. 점이 2개 미만인 Line은 제외하고, 남은 Line이 없으면 ErrNoData 반환
+func Render(s Spec) ([]byte, error) {
+
+	series := make([]gochart.Series, 0, len(s.Lines)+len(s.Levels))
+	var from, to time.Time
+	for _, l := range s.Lines {
+		if len(l.Times) < 2 || len(l.Times) != len(l.Values) {
+			continue
+		}
+		ts := gochart.TimeSeries{
+			Name:    l.Name,
+			XValues: l.Times,
+			YValues: l.Values,
+		}
+		if l.Secondary {
+			ts.YAxis = gochart.YAxisSecondary
+		}
+		series = append(series, ts)
+
+		if from.IsZero() || l.Times[0].Before(from) {
+			from = l.Times[0]
+		}
+		if last := l.Times[len(l.Times)-1]; last.After(to) {
+			to = last
+		}
+	}
+	if len(series) == 0 {
+		return nil, ErrNoData
+	}
+
+	for i, lv := range s.Levels {
+		series = append(series, gochart.TimeSeries{
+			Name: fmt.Sprintf("%s %.2f", lv.Name, lv.Value),
+			Style: gochart.Style{
+				StrokeColor:     levelColors[i%len(levelColors)],
+				StrokeDashArray: []float64{5, 5},
+			},
+			XValues: []time.Time{from, to},
+			YValues: []float64{lv.Value, lv.Value},
+		})
+	}
+
+	graph := gochart.Chart{
+		Title:  s.Title,
+		Width:  width,
+		Height: height,
+		Background: gochart.Style{
+			Padding: gochart.Box{Top: 50, Left: 20, Right: 20, Bottom: 20},
+		},
+		XAxis: gochart.XAxis{
+			ValueFormatter: gochart.TimeDateValueFormatter,
+		},
+		Series: series,
+	}
+	graph.Elements = []gochart.Renderable{gochart.LegendLeft(&graph)}
+
+	var buf bytes.Buffer
+	if err := graph.Render(gochart.PNG, &buf); err != nil {
+		return nil, fmt.Errorf("차트 렌더링 시 오류 발생. %w", err)
+	}
+	return buf.Bytes(), nil
+}
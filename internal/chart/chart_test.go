@@ -0,0 +1,40 @@
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+
+	now := time.Now()
+	times := []time.Time{now.AddDate(0, 0, -2), now.AddDate(0, 0, -1), now}
+
+	t.Run("PNG", func(t *testing.T) {
+		b, err := Render(Spec{
+			Title: "test",
+			Lines: []Line{
+				{Name: "a", Times: times, Values: []float64{1, 3, 2}},
+				{Name: "b", Times: times, Values: []float64{10, 20, 30}, Secondary: true},
+			},
+			Levels: []Level{{Name: "Buy", Value: 1.5}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(b, []byte("\x89PNG")) {
+			t.Error("PNG 형식 아님")
+		}
+	})
+
+	t.Run("NoData", func(t *testing.T) {
+		_, err := Render(Spec{
+			Lines: []Line{{Name: "a", Times: times[:1], Values: []float64{1}}},
+		})
+		if !errors.Is(err, ErrNoData) {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package chart
+
+import (
+	"fmt"
+	m "investindicator/internal/model"
+	"time"
+)
+
+const defaultDays = 90
+
+// memo. db.Storage가 구현
+type store interface {
+	RetrieveAsset(id uint) (*m.Asset, error)
+	RetrieveEmaHists(assetId uint, since time.Time) ([]m.EmaHist, error)
+	RetrieveFundNavs(fundId uint, since time.Time) ([]m.FundNav, error)
+	RetrievePremiums(assetId uint, since time.Time) ([]m.PremiumHist, error)
+	RetrieveMarketIndicators(since time.Time) ([]m.DailyIndex, error)
+	RetrieveHighYieldSpreads(since time.Time) ([]m.HighYieldSpread, error)
+}
+
+// Service 저장된 이력으로 차트 생성. days가 0 이하면 최근 90일
+type Service struct {
+	stg store
+}
+
+func NewService(stg store) *Service {
+	return &Service{stg: stg}
+}
+
+func since(days int) time.Time {
+	if days <= 0 {
+		days = defaultDays
+	}
+	return time.Now().AddDate(0, 0, -days)
+}
+
+// FundNav 자금 평가 총액(원화) 추이
+func (s *Service) FundNav(fundId uint, days int) ([]byte, error) {
+
+	navs, err := s.stg.RetrieveFundNavs(fundId, since(days))
+	if err != nil {
+		return nil, fmt.Errorf("RetrieveFundNavs 시 오류 발생. %w", err)
+	}
+
+	l := Line{Name: "NAV(KRW)"}
+	for _, n := range navs {
+		l.Times = append(l.Times, n.CreatedAt)
+		l.Values = append(l.Values, n.Amount)
+	}
+
+	return Render(Spec{
+		Title: fmt.Sprintf("Fund %d NAV", fundId),
+		Lines: []Line{l},
+	})
+}
+
+// Asset 자산 종가, EMA 추이와 매수/매도 가격 기준선
+func (s *Service) Asset(assetId uint, days int) ([]byte, error) {
+
+	asset, err := s.stg.RetrieveAsset(assetId)
+	if err != nil {
+		return nil, fmt.Errorf("RetrieveAsset 시 오류 발생. %w", err)
+	}
+	hists, err := s.stg.RetrieveEmaHists(assetId, since(days))
+	if err != nil {
+		return nil, fmt.Errorf("RetrieveEmaHists 시 오류 발생. %w", err)
+	}
+
+	price := Line{Name: "Price"}
+	ema := Line{Name: "EMA"}
+	for _, h := range hists {
+		if h.Price != 0 { // 종가 저장 이전 이력은 EMA만 존재
+			price.Times = append(price.Times, h.Date)
+			price.Values = append(price.Values, h.Price)
+		}
+		ema.Times = append(ema.Times, h.Date)
+		ema.Values = append(ema.Values, h.Ema)
+	}
+
+	levels := make([]Level, 0, 2)
+	if asset.BuyPrice != 0 {
+		levels = append(levels, Level{Name: "Buy", Value: asset.BuyPrice})
+	}
+	if asset.SellPrice != 0 {
+		levels = append(levels, Level{Name: "Sell", Value: asset.SellPrice})
+	}
+
+	return Render(Spec{
+		Title:  fmt.Sprintf("Asset %d %s", asset.ID, asset.Code),
+		Lines:  []Line{price, ema},
+		Levels: levels,
+	})
+}
+
+// Indicators 공포 탐욕 지수와 하이일드 스프레드(오른쪽 축) 추이
+func (s *Service) Indicators(days int) ([]byte, error) {
+
+	from := since(days)
+	indexes, err := s.stg.RetrieveMarketIndicators(from)
+	if err != nil {
+		return nil, fmt.Errorf("RetrieveMarketIndicators 시 오류 발생. %w", err)
+	}
+	spreads, err := s.stg.RetrieveHighYieldSpreads(from)
+	if err != nil {
+		return nil, fmt.Errorf("RetrieveHighYieldSpreads 시 오류 발생. %w", err)
+	}
+
+	fg := Line{Name: "Fear&Greed"}
+	for _, idx := range indexes {
+		fg.Times = append(fg.Times, idx.CreatedAt)
+		fg.Values = append(fg.Values, float64(idx.FearGreedIndex))
+	}
+	hy := Line{Name: "HY Spread(%)", Secondary: true}
+	for _, sp := range spreads {
+		hy.Times = append(hy.Times, sp.CreatedAt)
+		hy.Values = append(hy.Values, sp.Spread)
+	}
+
+	return Render(Spec{
+		Title: "Market Indicators",
+		Lines: []Line{fg, hy},
+	})
+}
+
+// Premium 김치 프리미엄(%) 추이와 알림 기준선
+func (s *Service) Premium(assetId uint, days int) ([]byte, error) {
+
+	hists, err := s.stg.RetrievePremiums(assetId, since(days))
+	if err != nil {
+		return nil, fmt.Errorf("RetrievePremiums 시 오류 발생. %w", err)
+	}
+
+	l := Line{Name: "Premium(%)"}
+	for _, h := range hists {
+		l.Times = append(l.Times, h.CreatedAt)
+		l.Values = append(l.Values, h.Premium)
+	}
+
+	return Render(Spec{
+		Title:  fmt.Sprintf("Asset %d Kimchi Premium", assetId),
+		Lines:  []Line{l},
+		Levels: []Level{{Name: "Buy", Value: -2}, {Name: "Sell", Value: 10}},
+	})
+}
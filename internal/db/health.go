@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	m "investindicator/internal/model"
+)
+
+// Health MySQL, Redis 연결 확인. 투자 기록, 캐시 조회가 모두 의존하므로 필수 구성 요소
+func (s Storage) Health(ctx context.Context) []m.ComponentHealth {
+	return []m.ComponentHealth{
+		s.mysqlHealth(ctx),
+		s.redisHealth(ctx),
+	}
+}
+
+func (s Storage) mysqlHealth(ctx context.Context) m.ComponentHealth {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return m.FailedComponent("mysql", true, fmt.Errorf("DB 연결 조회 시 오류 발생. %w", err))
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return m.FailedComponent("mysql", true, err)
+	}
+	return m.HealthyComponent("mysql", true)
+}
+
+func (s Storage) redisHealth(ctx context.Context) m.ComponentHealth {
+	if s.rds == nil {
+		return m.FailedComponent("redis", true, errors.New("redis 미설정"))
+	}
+	if err := s.rds.Ping(ctx).Err(); err != nil {
+		return m.FailedComponent("redis", true, err)
+	}
+	return m.HealthyComponent("redis", true)
+}
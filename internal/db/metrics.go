@@ -0,0 +1,69 @@
+package db
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+)
+
+const queryStartKey = "metrics:query_start"
+
+var queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "db_query_duration_seconds",
+	Help:    "DB 쿼리 실행 시간",
+	Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+}, []string{"operation", "table"})
+
+var queryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "db_query_errors_total",
+	Help: "DB 쿼리 실패 수. 조회 결과 미존재(ErrRecordNotFound)는 제외",
+}, []string{"operation", "table"})
+
+// queryMetrics gorm callback 전후로 쿼리 실행 시간 집계하는 plugin
+type queryMetrics struct{}
+
+func (queryMetrics) Name() string { return "query_metrics" }
+
+func (queryMetrics) Initialize(db *gorm.DB) error {
+
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("metrics:before_create", startQuery),
+		cb.Create().After("gorm:create").Register("metrics:after_create", observeQuery("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", startQuery),
+		cb.Query().After("gorm:query").Register("metrics:after_query", observeQuery("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", startQuery),
+		cb.Update().After("gorm:update").Register("metrics:after_update", observeQuery("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", startQuery),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", observeQuery("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", startQuery),
+		cb.Row().After("gorm:row").Register("metrics:after_row", observeQuery("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", startQuery),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", observeQuery("raw")),
+	)
+}
+
+func startQuery(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func observeQuery(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		start, _ := v.(time.Time)
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown" // memo. Raw 쿼리는 table 미지정
+		}
+		queryDuration.WithLabelValues(op, table).Observe(time.Since(start).Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			queryErrors.WithLabelValues(op, table).Inc()
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func stgDsn(conf *MysqlConfig) string {
@@ -18,7 +19,8 @@ func (s Storage) initTables() error {
 	err := s.db.AutoMigrate(&m.Fund{}, &m.Asset{}, &m.EmaHist{},
 		&m.Invest{}, &m.InvestSummary{}, &m.Market{},
 		&m.DailyIndex{}, &m.CliIndex{}, &m.HighYieldSpread{},
-		&m.User{}, &m.Event{}, &m.SP500Company{}, &m.AssetSnapshotRecord{})
+		&m.User{}, &m.FundMember{}, &m.Event{}, &m.SP500Company{}, &m.AssetSnapshotRecord{},
+		&m.FundNav{}, &m.PremiumHist{}, &m.ApiKey{}, &m.AuditLog{})
 	if err != nil {
 		panic("failed to migrate database")
 	}
@@ -125,18 +127,19 @@ func (s Storage) RetrieveFundInvestsByIdAndRange(fundID uint, startDate, endDate
 // 	return &fund, nil
 // }
 
-func (s Storage) SaveFund(name string) error {
+func (s Storage) SaveFund(name string) (uint, error) {
 
-	result := s.db.Create(&m.Fund{
+	fund := m.Fund{
 		Name: name,
-	})
+	}
+	result := s.db.Create(&fund)
 
 	if result.Error != nil {
-		return result.Error
+		return 0, result.Error
 	}
 
 	s.lg.Info().Msgf("Saved fund with name %s", name)
-	return nil
+	return fund.ID, nil
 }
 
 func (s Storage) RetrieveAssetList() ([]m.Asset, error) {
@@ -254,6 +257,16 @@ func (s Storage) UpdateAssetInfo(asset m.Asset) error {
 	return nil
 }
 
+// UpdateAssetTopBottom 최고가/최저가만 갱신. 다른 필드는 API, bot 변경 값 유지
+func (s Storage) UpdateAssetTopBottom(id uint, top float64, bottom float64) error {
+
+	result := s.db.Model(&m.Asset{}).Where("id = ?", id).Updates(map[string]any{"top": top, "bottom": bottom})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
 func (s Storage) DeleteAssetInfo(id uint) error {
 
 	result := s.db.Delete(&m.Asset{}, id)
@@ -431,33 +444,85 @@ func (s Storage) RetrieveInvestSummaryByFundIdAssetId(fundId uint, assetId uint)
 // => 업데이트 필드 명시 필요
 func (s Storage) UpdateInvestSummary(fundId uint, assetId uint, change float64, price float64) error {
 
-	var investSummary m.InvestSummary
-	result := s.db.Model(&m.InvestSummary{}).
-		Where("fund_id = ?", fundId).
-		Where("asset_id = ?", assetId).
-		Find(&investSummary) // memo. Select는 필드 지정하는 용도. 조회에서 구조체에 넣으려면 Find 사용
+	err := updateInvestSummary(s.db, fundId, assetId, change, price)
+	if err != nil {
+		return err
+	}
 
-	if result.RowsAffected == 0 {
-		investSummary = m.InvestSummary{
-			FundID:  fundId,
-			AssetID: assetId,
-			Count:   change,
-			Sum:     change * price,
+	s.lg.Info().Msgf("Updated invest summary for fund ID %d and asset ID %d", fundId, assetId)
+	return nil
+}
+
+/*
+updateInvestSummary 보유 수량, 금액 변동 반영. 행이 없으면 생성
+  - memo. 체결, API, 가져오기가 동시에 같은 행(특히 자금별 원화/달러 잔고)을 갱신하므로 행 잠금 후 증감식으로 갱신
+  - transaction 내에서 호출되면 savepoint로 동작하여 잠금은 바깥 transaction 종료 시 해제
+*/
+func updateInvestSummary(db *gorm.DB, fundId uint, assetId uint, change float64, price float64) error {
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var investSummary m.InvestSummary
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("fund_id = ?", fundId).
+			Where("asset_id = ?", assetId).
+			Limit(1).
+			Find(&investSummary) // memo. Select는 필드 지정하는 용도. 조회에서 구조체에 넣으려면 Find 사용
+		if result.Error != nil {
+			return result.Error
 		}
 
-		result = s.db.Model(&m.InvestSummary{}).Create(&investSummary)
-	} else {
-		investSummary.Count += change
-		investSummary.Sum += change * price
+		if result.RowsAffected == 0 {
+			return tx.Create(&m.InvestSummary{
+				FundID:  fundId,
+				AssetID: assetId,
+				Count:   change,
+				Sum:     change * price,
+			}).Error
+		}
+		return tx.Model(&investSummary).Updates(map[string]any{
+			"count": gorm.Expr("count + ?", change),
+			"sum":   gorm.Expr("sum + ?", change*price),
+		}).Error
+	})
+}
+
+/*
+RecordInvests 투자 이력 저장과 보유 현황 갱신을 한 transaction으로 처리. CreatedAt이 지정된 이력은 해당 시각으로 저장
+  - 보유 현황 행은 transaction 종료까지 잠금. 대량 가져오기 중 동시 기록(API, 체결)은 대기 후 반영
+*/
+func (s Storage) RecordInvests(invests []m.Invest, changes []m.SummaryChange) error {
 
-		result = s.db.Model(&investSummary).Select("Count", "Sum").Updates(investSummary)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if len(invests) > 0 {
+			if err := tx.Create(&invests).Error; err != nil {
+				return err
+			}
+		}
+		for _, c := range changes {
+			if err := updateInvestSummary(tx, c.FundID, c.AssetID, c.Change, c.Price); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
+
+	s.lg.Info().Msgf("Recorded %d invests", len(invests))
+	return nil
+}
+
+// RetrieveInvestsSince since 이후 투자 이력. 가져오기 중복 확인용
+func (s Storage) RetrieveInvestsSince(since time.Time) ([]m.Invest, error) {
+
+	var invests []m.Invest
+	result := s.db.Where("created_at >= ?", since).Find(&invests)
 	if result.Error != nil {
-		return result.Error
+		return nil, result.Error
 	}
 
-	s.lg.Info().Msgf("Updated invest summary for fund ID %d and asset ID %d", fundId, assetId)
-	return nil
+	return invests, nil
 }
 
 func (s Storage) UpdateInvestSummarySum(fundId uint, assetId uint, sum float64) error {
@@ -503,6 +568,88 @@ func (s Storage) SaveEmaHist(newEma *m.EmaHist) error {
 	return nil
 }
 
+// RetrieveEmaHists since 이후 EMA 이력. 오래된 순
+func (s Storage) RetrieveEmaHists(assetId uint, since time.Time) ([]m.EmaHist, error) {
+
+	var hists []m.EmaHist
+	result := s.db.Where("asset_id = ? AND date >= ?", assetId, since).Order("date").Find(&hists)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	s.lg.Info().Msgf("Retrieved %d EMA histories for asset ID %d", len(hists), assetId)
+	return hists, nil
+}
+
+func (s Storage) SaveFundNav(fundId uint, amount float64) error {
+
+	result := s.db.Create(&m.FundNav{
+		FundID: fundId,
+		Amount: amount,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	s.lg.Info().Msgf("Saved NAV for fund ID %d", fundId)
+	return nil
+}
+
+// RetrieveFundNavs since 이후 자금 평가 총액 이력. 오래된 순
+func (s Storage) RetrieveFundNavs(fundId uint, since time.Time) ([]m.FundNav, error) {
+
+	var navs []m.FundNav
+	result := s.db.Where("fund_id = ? AND created_at >= ?", fundId, since).Order("created_at").Find(&navs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return navs, nil
+}
+
+func (s Storage) SavePremium(assetId uint, premium float64) error {
+
+	result := s.db.Create(&m.PremiumHist{
+		AssetID: assetId,
+		Premium: premium,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+// RetrievePremiums since 이후 김치 프리미엄 이력. 오래된 순
+func (s Storage) RetrievePremiums(assetId uint, since time.Time) ([]m.PremiumHist, error) {
+
+	var hists []m.PremiumHist
+	result := s.db.Where("asset_id = ? AND created_at >= ?", assetId, since).Order("created_at").Find(&hists)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return hists, nil
+}
+
+// RetrieveMarketIndicators since 이후 일별 지표. 오래된 순
+func (s Storage) RetrieveMarketIndicators(since time.Time) ([]m.DailyIndex, error) {
+
+	var indexes []m.DailyIndex
+	err := s.db.Where("created_at >= ?", since).Order("created_at").Find(&indexes).Error
+
+	return indexes, err
+}
+
+// RetrieveHighYieldSpreads since 이후 하이일드 스프레드. 오래된 순
+func (s Storage) RetrieveHighYieldSpreads(since time.Time) ([]m.HighYieldSpread, error) {
+
+	var hy []m.HighYieldSpread
+	err := s.db.Where("created_at >= ?", since).Order("created_at").Find(&hy).Error
+
+	return hy, err
+}
+
 func (s Storage) User(userName string) (*m.User, error) {
 
 	var user m.User
@@ -515,6 +662,207 @@ func (s Storage) User(userName string) (*m.User, error) {
 	return &user, nil
 }
 
+func (s Storage) UserByTelegramId(telegramId int64) (*m.User, error) {
+
+	var user m.User
+	result := s.db.Where("telegram_id", telegramId).First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	s.lg.Info().Msgf("Retrieved user with telegram id %d", telegramId)
+	return &user, nil
+}
+
+func (s Storage) UserById(id int) (*m.User, error) {
+
+	var user m.User
+	result := s.db.Where("id", id).First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
+
+func (s Storage) Users() ([]m.User, error) {
+
+	var users []m.User
+	result := s.db.Order("id").Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return users, nil
+}
+
+// CreateUser user.Password는 bcrypt hash
+func (s Storage) CreateUser(user *m.User) error {
+
+	result := s.db.Create(user)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	s.lg.Info().Msgf("Created user %s", user.Username)
+	return nil
+}
+
+// UpdateUser 사용자 정보 갱신. 비밀번호는 UpdatePassword로 변경
+func (s Storage) UpdateUser(user *m.User) error {
+
+	// memo. 값이 같으면 MySQL affected rows가 0이므로 미존재 확인은 호출 측에서 처리
+	result := s.db.Model(user).Select("Email", "IsAdmin", "TelegramId", "Disabled").Updates(user)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	s.lg.Info().Msgf("Updated user %d", user.ID)
+	return nil
+}
+
+// UpdatePassword hash bcrypt hash
+func (s Storage) UpdatePassword(userId int, hash string) error {
+
+	result := s.db.Model(&m.User{}).Where("id", userId).Update("password", hash)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	s.lg.Info().Msgf("Updated password of user %d", userId)
+	return nil
+}
+
+// DeleteUser 자금 조회 권한(FundMember)은 FK cascade로 함께 삭제
+func (s Storage) DeleteUser(userId int) error {
+
+	result := s.db.Delete(&m.User{}, userId)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	s.lg.Info().Msgf("Deleted user %d", userId)
+	return nil
+}
+
+func (s Storage) CreateApiKey(key *m.ApiKey) error {
+
+	result := s.db.Create(key)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	s.lg.Info().Msgf("Created api key %s", key.Name)
+	return nil
+}
+
+func (s Storage) ApiKeyByPrefix(prefix string) (*m.ApiKey, error) {
+
+	var key m.ApiKey
+	result := s.db.Where("prefix", prefix).First(&key)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &key, nil
+}
+
+func (s Storage) ApiKeys() ([]m.ApiKey, error) {
+
+	var keys []m.ApiKey
+	result := s.db.Order("id").Find(&keys)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return keys, nil
+}
+
+// RevokeApiKey 이미 폐기된 키는 폐기 시각 유지
+func (s Storage) RevokeApiKey(id uint, at time.Time) error {
+
+	var key m.ApiKey
+	result := s.db.Where("id", id).First(&key)
+	if result.Error != nil {
+		return result.Error
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+
+	result = s.db.Model(&key).Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	s.lg.Info().Msgf("Revoked api key %d", id)
+	return nil
+}
+
+func (s Storage) TouchApiKey(id uint, at time.Time) error {
+	return s.db.Model(&m.ApiKey{}).Where("id", id).Update("last_used_at", at).Error
+}
+
+// RetrieveFundRolesOfUser 사용자의 자금별 권한
+func (s Storage) RetrieveFundRolesOfUser(userId int) (map[uint]m.FundRole, error) {
+
+	var members []m.FundMember
+	result := s.db.Where("user_id", userId).Find(&members)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	roles := make(map[uint]m.FundRole, len(members))
+	for _, fm := range members {
+		roles[fm.FundID] = fm.Role
+	}
+	return roles, nil
+}
+
+func (s Storage) FundMembers(fundId uint) ([]m.FundMember, error) {
+
+	var members []m.FundMember
+	result := s.db.Preload("User").Where("fund_id", fundId).Order("user_id").Find(&members)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return members, nil
+}
+
+// SaveFundMember 이미 등록된 사용자면 권한만 갱신
+func (s Storage) SaveFundMember(fundId uint, userId int, role m.FundRole) error {
+
+	var member m.FundMember
+	result := s.db.Where(m.FundMember{FundID: fundId, UserID: userId}).Assign(m.FundMember{Role: role}).FirstOrCreate(&member)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	s.lg.Info().Msgf("Saved member %d of fund %d as %s", userId, fundId, role)
+	return nil
+}
+
+func (s Storage) DeleteFundMember(fundId uint, userId int) error {
+
+	result := s.db.Where("fund_id", fundId).Where("user_id", userId).Delete(&m.FundMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	s.lg.Info().Msgf("Deleted member %d of fund %d", userId, fundId)
+	return nil
+}
+
 func (s Storage) RetreiveEventIsActive(eventId uint) bool {
 	var event m.Event
 	result := s.db.Where("id", eventId).First(&event)
@@ -619,3 +967,7 @@ func (s Storage) SaveSP500Entry(sp500 *m.SP500Company) error {
 
 // 	return rtn, nil
 // }
+
+func (s Storage) SaveAuditLog(log *m.AuditLog) error {
+	return s.db.Create(log).Error
+}
@@ -6,6 +6,7 @@ import (
 	m "investindicator/internal/model"
 	"log"
 	"os"
+	"sync"
 	"testing"
 
 	"gorm.io/gorm"
@@ -87,7 +88,7 @@ func TestRetrieveFundInvestsByIdAndRange(t *testing.T) {
 func TestSaveFund(t *testing.T) {
 	setupStg(t)
 
-	err := stg.SaveFund("테스트")
+	_, err := stg.SaveFund("테스트")
 	if err != nil {
 		t.Error(err)
 	}
@@ -369,6 +370,48 @@ func TestRetrieveInvestHist(t *testing.T) {
 	})
 }
 
+func TestRetrieveInvests(t *testing.T) {
+	setupStg(t)
+
+	t.Run("cursor 조회", func(t *testing.T) {
+		q := m.InvestQuery{FundID: 1, Page: m.Page{Limit: 2}}
+		first, err := stg.RetrieveInvests(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first.NextCursor == "" {
+			t.Skip("다음 페이지 없음")
+		}
+
+		q.Cursor = first.NextCursor
+		next, err := stg.RetrieveInvests(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next.Total != first.Total || next.Items[0].ID == first.Items[len(first.Items)-1].ID {
+			t.Error(first, next)
+		}
+	})
+
+	t.Run("필터, 정렬", func(t *testing.T) {
+		rtn, err := stg.RetrieveInvests(m.InvestQuery{
+			Side:      m.Buy,
+			MinAmount: 100000,
+			Category:  m.DomesticStock,
+			Sort:      m.Sort{Field: "amount", Desc: true},
+			Page:      m.Page{Limit: 10},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, iv := range rtn.Items {
+			if iv.Count <= 0 || iv.Price*iv.Count < 100000 || iv.Asset.Category != m.DomesticStock {
+				t.Error(iv)
+			}
+		}
+	})
+}
+
 func TestSaveInvest(t *testing.T) {
 	setupStg(t)
 
@@ -416,6 +459,48 @@ func TestUpdateInvestSummary(t *testing.T) {
 	}
 }
 
+// 가져오기, 체결, API 기록이 동시에 같은 원화 잔고를 갱신해도 변동이 모두 반영되는지 확인
+func TestRecordInvestsConcurrent(t *testing.T) {
+	setupStg(t)
+
+	var krw m.Asset
+	if err := stg.db.Where("category = ?", m.Won).First(&krw).Error; err != nil {
+		t.Skip("원화 자산 미등록")
+	}
+	summary := func() m.InvestSummary {
+		var is m.InvestSummary
+		stg.db.Where("fund_id = ? AND asset_id = ?", 1, krw.ID).Find(&is)
+		return is
+	}
+	before := summary()
+
+	const n = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- stg.RecordInvests(nil, []m.SummaryChange{{FundID: 1, AssetID: krw.ID, Change: -100, Price: 1}})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	after := summary()
+	if after.Count != before.Count-100*n || after.Sum != before.Sum-100*n {
+		t.Error(before, after)
+	}
+
+	// 원복
+	stg.RecordInvests(nil, []m.SummaryChange{{FundID: 1, AssetID: krw.ID, Change: 100 * n, Price: 1}})
+}
+
 func TestRetrieveLatestSP500Entry(t *testing.T) {
 	setupStg(t)
 	rtn, err := stg.RetrieveLatestSP500Entry()
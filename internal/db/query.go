@@ -0,0 +1,286 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	m "investindicator/internal/model"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// cursor 마지막 행의 정렬 값과 id. 정렬 값이 같은 행은 id로 구분
+type cursor struct {
+	Time *time.Time `json:"t,omitempty"`
+	Num  *float64   `json:"n,omitempty"`
+	Str  *string    `json:"s,omitempty"`
+	ID   uint       `json:"id"`
+}
+
+func (c cursor) value() any {
+	switch {
+	case c.Time != nil:
+		return *c.Time
+	case c.Num != nil:
+		return *c.Num
+	case c.Str != nil:
+		return *c.Str
+	}
+	return c.ID
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, fmt.Errorf("cursor 변환 시 오류 발생. %w", err)
+	}
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return c, fmt.Errorf("cursor 변환 시 오류 발생. %w", err)
+	}
+	if c.ID == 0 {
+		return c, errors.New("cursor id 미존재")
+	}
+	return c, nil
+}
+
+/*
+paginate 건수 조회 후 정렬, 범위 적용해서 조회
+  - expr: 정렬 기준 컬럼 혹은 식. id로 동률 정렬
+  - cursor 지정 시 keyset 조회(정렬 값, id 기준 다음 행부터)라 이력이 쌓여도 offset과 달리 앞 행을 건너뛰지 않음
+  - key: 다음 cursor 생성용. 마지막 행의 정렬 값
+  - preloads: 건수 조회에는 불필요하므로 목록 조회 시에만 적용
+*/
+func paginate[T any](query *gorm.DB, expr string, sort m.Sort, page m.Page, key func(T) cursor, preloads ...string) (*m.List[T], error) {
+
+	query = query.Session(&gorm.Session{}) // memo. Count 이후 같은 조건으로 재사용
+
+	var total int64
+	result := query.Count(&total)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	dir, cmp := "ASC", ">"
+	if sort.Desc {
+		dir, cmp = "DESC", "<"
+	}
+
+	if page.Cursor != "" {
+		c, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if expr == "id" {
+			query = query.Where(fmt.Sprintf("id %s ?", cmp), c.ID)
+		} else {
+			v := c.value()
+			query = query.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", expr, cmp, expr, cmp), v, v, c.ID)
+		}
+	} else if page.Offset > 0 {
+		query = query.Offset(page.Offset)
+	}
+
+	query = query.Order(fmt.Sprintf("%s %s", expr, dir))
+	if expr != "id" {
+		query = query.Order("id " + dir)
+	}
+	if page.Limit > 0 {
+		query = query.Limit(page.Limit + 1) // 다음 행 존재 여부 확인
+	}
+
+	for _, p := range preloads {
+		query = query.Preload(p)
+	}
+
+	var items []T
+	result = query.Find(&items)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	list := &m.List[T]{Items: items, Total: total}
+	if page.Limit > 0 && len(items) > page.Limit {
+		list.Items = items[:page.Limit]
+		list.NextCursor = encodeCursor(key(items[page.Limit-1]))
+	}
+	return list, nil
+}
+
+// memo. id(등록 순)는 기존 경로 기본 정렬용. query sort로는 미지원
+var investSortExprs = map[string]string{
+	"id":         "id",
+	"created_at": "created_at",
+	"price":      "price",
+	"count":      "count",
+	"amount":     "ABS(price * count)",
+}
+
+// RetrieveInvests 조건별 투자 이력 조회. 자산 정보 포함
+func (s Storage) RetrieveInvests(q m.InvestQuery) (*m.List[m.Invest], error) {
+
+	query := s.db.Model(&m.Invest{})
+
+	if q.FundID != 0 {
+		query = query.Where("fund_id = ?", q.FundID)
+	}
+	if q.Funds != nil {
+		if len(q.Funds) == 0 {
+			return &m.List[m.Invest]{}, nil
+		}
+		query = query.Where("fund_id IN ?", q.Funds)
+	}
+	if q.AssetID != 0 {
+		query = query.Where("asset_id = ?", q.AssetID)
+	}
+	if q.Category != 0 {
+		query = query.Where("asset_id IN (?)", s.db.Model(&m.Asset{}).Select("id").Where("category = ?", q.Category))
+	}
+	switch q.Side {
+	case m.Buy:
+		query = query.Where("count > 0")
+	case m.Sell:
+		query = query.Where("count < 0")
+	}
+	if q.MinAmount > 0 {
+		query = query.Where("ABS(price * count) >= ?", q.MinAmount)
+	}
+	if q.MaxAmount > 0 {
+		query = query.Where("ABS(price * count) <= ?", q.MaxAmount)
+	}
+	if !q.Start.IsZero() {
+		query = query.Where("created_at >= ?", q.Start)
+	}
+	if !q.End.IsZero() {
+		query = query.Where("created_at < ?", q.End)
+	}
+
+	sort := q.Sort
+	if sort.Field == "" {
+		sort = m.Sort{Field: "created_at", Desc: true}
+	}
+	expr, ok := investSortExprs[sort.Field]
+	if !ok {
+		return nil, fmt.Errorf("지원하지 않는 정렬 기준. %s", sort.Field)
+	}
+
+	list, err := paginate(query, expr, sort, q.Page, func(iv m.Invest) cursor {
+		c := cursor{ID: iv.ID}
+		switch sort.Field {
+		case "created_at":
+			c.Time = &iv.CreatedAt
+		case "price":
+			c.Num = &iv.Price
+		case "count":
+			c.Num = &iv.Count
+		case "amount":
+			amount := iv.Price * iv.Count
+			if amount < 0 {
+				amount = -amount
+			}
+			c.Num = &amount
+		}
+		return c
+	}, "Asset")
+	if err != nil {
+		return nil, err
+	}
+
+	s.lg.Info().Msgf("Retrieved %d of %d invests", len(list.Items), list.Total)
+	return list, nil
+}
+
+// RetrieveAssets 조건별 자산 조회
+func (s Storage) RetrieveAssets(q m.AssetQuery) (*m.List[m.Asset], error) {
+
+	query := s.db.Model(&m.Asset{})
+
+	if q.Category != 0 {
+		query = query.Where("category = ?", q.Category)
+	}
+	if q.Currency != "" {
+		query = query.Where("currency = ?", q.Currency)
+	}
+	if q.Keyword != "" {
+		kw := "%" + q.Keyword + "%"
+		query = query.Where("(name LIKE ? OR code LIKE ?)", kw, kw)
+	}
+
+	sort := q.Sort
+	if sort.Field == "" {
+		sort = m.Sort{Field: "id"}
+	}
+	if !slices.Contains(m.AssetSortFields, sort.Field) {
+		return nil, fmt.Errorf("지원하지 않는 정렬 기준. %s", sort.Field)
+	}
+
+	list, err := paginate(query, sort.Field, sort, q.Page, func(a m.Asset) cursor {
+		c := cursor{ID: a.ID}
+		switch sort.Field {
+		case "name":
+			c.Str = &a.Name
+		case "code":
+			c.Str = &a.Code
+		case "category":
+			n := float64(a.Category)
+			c.Num = &n
+		}
+		return c
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.lg.Info().Msgf("Retrieved %d of %d assets", len(list.Items), list.Total)
+	return list, nil
+}
+
+// RetrieveAuditLogs 조건별 상태 변경 이력 조회. 최신순
+func (s Storage) RetrieveAuditLogs(q m.AuditQuery) (*m.List[m.AuditLog], error) {
+
+	query := s.db.Model(&m.AuditLog{})
+
+	if q.ActorType != "" {
+		query = query.Where("actor_type = ?", q.ActorType)
+	}
+	if q.ActorID != "" {
+		query = query.Where("actor_id = ?", q.ActorID)
+	}
+	if q.Entity != "" {
+		query = query.Where("entity = ?", q.Entity)
+	}
+	if q.EntityID != "" {
+		query = query.Where("entity_id = ?", q.EntityID)
+	}
+	if q.Action != "" {
+		query = query.Where("action = ?", q.Action)
+	}
+	if q.RequestID != "" {
+		query = query.Where("request_id = ?", q.RequestID)
+	}
+	if !q.Start.IsZero() {
+		query = query.Where("created_at >= ?", q.Start)
+	}
+	if !q.End.IsZero() {
+		query = query.Where("created_at < ?", q.End)
+	}
+
+	list, err := paginate(query, "id", m.Sort{Field: "id", Desc: true}, q.Page, func(l m.AuditLog) cursor {
+		return cursor{ID: l.ID}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.lg.Info().Msgf("Retrieved %d of %d audit logs", len(list.Items), list.Total)
+	return list, nil
+}
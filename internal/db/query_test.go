@@ -0,0 +1,40 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCursor(t *testing.T) {
+
+	now := time.Date(2025, 1, 2, 9, 30, 0, 0, time.Local)
+	price := 1500.5
+
+	for _, c := range []cursor{{Time: &now, ID: 3}, {Num: &price, ID: 4}, {ID: 5}} {
+		decoded, err := decodeCursor(encodeCursor(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded.ID != c.ID {
+			t.Error(decoded)
+		}
+		switch v := decoded.value().(type) {
+		case time.Time:
+			if !v.Equal(now) {
+				t.Error(v)
+			}
+		case float64:
+			if v != price {
+				t.Error(v)
+			}
+		case uint:
+			if v != c.ID {
+				t.Error(v)
+			}
+		}
+	}
+
+	if _, err := decodeCursor("invalid"); err == nil {
+		t.Error("잘못된 cursor 허용")
+	}
+}
@@ -14,3 +14,46 @@ func (s Storage) SetCache(key string, value interface{}, exp time.Duration) {
 func (s Storage) GetCache(key string) *redis.StringCmd {
 	return s.rds.Get(context.Background(), key)
 }
+
+// memo. KEYS 명령은 redis를 블로킹하므로 SCAN으로 순회
+func (s Storage) CacheKeys(pattern string) ([]string, error) {
+	ctx := context.Background()
+
+	keys := make([]string, 0)
+	iter := s.rds.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s Storage) DelCache(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.rds.Del(context.Background(), keys...).Err()
+}
+
+// IncrCache 값 1 증가 후 만료 시간 갱신. memo. INCR, EXPIRE를 MULTI로 묶어 여러 인스턴스에서 동시에 호출해도 누락 없이 증가
+func (s Storage) IncrCache(key string, exp time.Duration) (int64, error) {
+	ctx := context.Background()
+
+	var incr *redis.IntCmd
+	_, err := s.rds.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, exp)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// CacheTTL 남은 만료 시간. 미존재 혹은 만료 시간이 없으면 0 이하
+func (s Storage) CacheTTL(key string) (time.Duration, error) {
+	return s.rds.TTL(context.Background(), key).Result()
+}
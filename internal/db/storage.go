@@ -46,6 +46,9 @@ func NewStorage(mc *MysqlConfig, rc *RedisConfig, opts ...gorm.Option) (*Storage
 	if err != nil {
 		return nil, err
 	}
+	if err := db.Use(queryMetrics{}); err != nil {
+		return nil, fmt.Errorf("쿼리 metric plugin 등록 시 오류 발생. %w", err)
+	}
 
 	var rds *redis.Client
 	if rc != nil {
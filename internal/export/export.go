@@ -0,0 +1,168 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Format 내보내기 파일 형식
+type Format string
+
+const (
+	CSV  Format = "csv"
+	JSON Format = "json"
+	XLSX Format = "xlsx"
+)
+
+// ParseFormat 빈 값이면 csv
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case CSV, JSON, XLSX:
+		return f, nil
+	case "":
+		return CSV, nil
+	}
+	return "", fmt.Errorf("지원하지 않는 형식. 입력 값 :%s", s)
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case JSON:
+		return "application/json"
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Table 내보내기 단위. Rows의 값은 string, float64, uint, time.Time
+type Table struct {
+	Name   string
+	Header []string
+	Rows   [][]any
+}
+
+// FileName ex) "ledger_20250101.csv"
+func (t *Table) FileName(f Format) string {
+	return fmt.Sprintf("%s_%s.%s", t.Name, time.Now().Format("20060102"), f)
+}
+
+// Encode 형식별 변환. csv는 Excel에서 한글이 깨지지 않도록 UTF-8 BOM 추가
+func Encode(t *Table, f Format) ([]byte, error) {
+	switch f {
+	case CSV:
+		return encodeCsv(t)
+	case JSON:
+		return encodeJson(t)
+	case XLSX:
+		return encodeXlsx(t)
+	}
+	return nil, errors.New("지원하지 않는 형식")
+}
+
+func encodeCsv(t *Table) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\ufeff")
+
+	w := csv.NewWriter(&buf)
+	w.Write(t.Header)
+	for _, row := range t.Rows {
+		rec := make([]string, len(row))
+		for i, v := range row {
+			rec[i] = text(v)
+		}
+		w.Write(rec)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("csv 변환 시 오류 발생. %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeJson header를 key로 하는 객체 배열
+func encodeJson(t *Table) ([]byte, error) {
+	rows := make([]map[string]any, len(t.Rows))
+	for i, row := range t.Rows {
+		obj := make(map[string]any, len(t.Header))
+		for j, v := range row {
+			if tm, ok := v.(time.Time); ok {
+				v = text(tm)
+			}
+			obj[t.Header[j]] = v
+		}
+		rows[i] = obj
+	}
+	b, err := json.Marshal(rows)
+	if err != nil {
+		return nil, fmt.Errorf("json 변환 시 오류 발생. %w", err)
+	}
+	return b, nil
+}
+
+func encodeXlsx(t *Table) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := t.Name
+	err := f.SetSheetName(f.GetSheetName(0), sheet)
+	if err != nil {
+		return nil, fmt.Errorf("xlsx sheet 이름 변경 시 오류 발생. %w", err)
+	}
+
+	write := func(r int, values []any) error {
+		cell, err := excelize.CoordinatesToCellName(1, r)
+		if err != nil {
+			return err
+		}
+		return f.SetSheetRow(sheet, cell, &values)
+	}
+
+	header := make([]any, len(t.Header))
+	for i, h := range t.Header {
+		header[i] = h
+	}
+	if err := write(1, header); err != nil {
+		return nil, fmt.Errorf("xlsx header 작성 시 오류 발생. %w", err)
+	}
+	for i, row := range t.Rows {
+		values := make([]any, len(row))
+		for j, v := range row {
+			if tm, ok := v.(time.Time); ok { // 셀 서식 없이도 읽을 수 있도록 문자열로 기록
+				v = text(tm)
+			}
+			values[j] = v
+		}
+		if err := write(i+2, values); err != nil {
+			return nil, fmt.Errorf("xlsx 행 작성 시 오류 발생. %w", err)
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("xlsx 변환 시 오류 발생. %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func text(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05")
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
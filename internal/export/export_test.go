@@ -0,0 +1,163 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	m "investindicator/internal/model"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+type storeMock struct {
+	invests   []m.Invest
+	summaries []m.InvestSummary
+	indexes   []m.DailyIndex
+	spreads   []m.HighYieldSpread
+}
+
+func (s storeMock) RetrieveInvests(q m.InvestQuery) (*m.List[m.Invest], error) {
+	var rtn []m.Invest
+	for _, iv := range s.invests {
+		if (q.FundID == 0 || iv.FundID == q.FundID) && (q.Funds == nil || slices.Contains(q.Funds, iv.FundID)) {
+			rtn = append(rtn, iv)
+		}
+	}
+	return &m.List[m.Invest]{Items: rtn, Total: int64(len(rtn))}, nil
+}
+
+func (s storeMock) RetreiveFundsSummaryOrderByFundId() ([]m.InvestSummary, error) {
+	return s.summaries, nil
+}
+
+func (s storeMock) RetrieveFundNavs(fundId uint, since time.Time) ([]m.FundNav, error) {
+	return nil, nil
+}
+
+func (s storeMock) RetrieveMarketIndicators(since time.Time) ([]m.DailyIndex, error) {
+	return s.indexes, nil
+}
+
+func (s storeMock) RetrieveHighYieldSpreads(since time.Time) ([]m.HighYieldSpread, error) {
+	return s.spreads, nil
+}
+
+type exchangerMock float64
+
+func (e exchangerMock) ExchageRate() float64 {
+	return float64(e)
+}
+
+func TestEncode(t *testing.T) {
+
+	tm := time.Date(2025, 1, 2, 9, 30, 0, 0, time.Local)
+	table := &Table{
+		Name:   "ledger",
+		Header: []string{"created_at", "asset", "price", "count"},
+		Rows: [][]any{
+			{tm, "비트코인", 95000000.0, 0.5},
+			{tm, "애플", 230.5, nil},
+		},
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		b, err := Encode(table, CSV)
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(b, []byte("\ufeff")))
+
+		rows, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(b, []byte("\ufeff")))).ReadAll()
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"created_at", "asset", "price", "count"},
+			{"2025-01-02 09:30:00", "비트코인", "95000000", "0.5"},
+			{"2025-01-02 09:30:00", "애플", "230.5", ""},
+		}, rows)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		b, err := Encode(table, JSON)
+		assert.NoError(t, err)
+
+		var rows []map[string]any
+		assert.NoError(t, json.Unmarshal(b, &rows))
+		assert.Len(t, rows, 2)
+		assert.Equal(t, "2025-01-02 09:30:00", rows[0]["created_at"])
+		assert.Equal(t, 95000000.0, rows[0]["price"])
+		assert.Nil(t, rows[1]["count"])
+	})
+
+	t.Run("XLSX", func(t *testing.T) {
+		b, err := Encode(table, XLSX)
+		assert.NoError(t, err)
+
+		f, err := excelize.OpenReader(bytes.NewReader(b))
+		assert.NoError(t, err)
+		defer f.Close()
+		rows, err := f.GetRows("ledger")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2025-01-02 09:30:00", "비트코인", "95000000", "0.5"}, rows[1])
+	})
+
+	t.Run("Format", func(t *testing.T) {
+		f, err := ParseFormat("")
+		assert.NoError(t, err)
+		assert.Equal(t, CSV, f)
+
+		_, err = ParseFormat("pdf")
+		assert.Error(t, err)
+	})
+}
+
+func TestService(t *testing.T) {
+
+	btc := m.Asset{ID: 1, Name: "비트코인", Code: "BTC", Currency: m.KRW.String()}
+	aapl := m.Asset{ID: 2, Name: "애플", Code: "AAPL", Currency: m.USD.String()}
+	stg := storeMock{
+		invests: []m.Invest{
+			{FundID: 1, AssetID: 1, Asset: btc, Price: 100, Count: 2},
+			{FundID: 1, AssetID: 1, Asset: btc, Price: 150, Count: -1},
+			{FundID: 2, AssetID: 2, Asset: aapl, Price: 10, Count: 1},
+		},
+		summaries: []m.InvestSummary{
+			{FundID: 1, Fund: m.Fund{Name: "개인"}, AssetID: 1, Asset: btc, Count: 1, Sum: 120},
+			{FundID: 2, Fund: m.Fund{Name: "연금"}, AssetID: 2, Asset: aapl, Count: 1, Sum: 12},
+		},
+		indexes: []m.DailyIndex{{CreatedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.Local), FearGreedIndex: 40}},
+		spreads: []m.HighYieldSpread{
+			{CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local), Spread: 3.1},
+			{CreatedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.Local), Spread: 3.2},
+		},
+	}
+	s := NewService(stg, exchangerMock(1400))
+
+	t.Run("Ledger", func(t *testing.T) {
+		table, err := s.Ledger(m.InvestQuery{Funds: []uint{1}})
+		assert.NoError(t, err)
+		assert.Len(t, table.Rows, 2)
+		assert.Equal(t, -150.0, table.Rows[1][9])
+	})
+
+	t.Run("Holdings", func(t *testing.T) {
+		table, err := s.Holdings(m.InvestQuery{})
+		assert.NoError(t, err)
+		assert.Len(t, table.Rows, 2)
+		// cost 200-150, profit (120+150-200)/200
+		assert.Equal(t, []any{uint(1), "개인", uint(1), "비트코인", "BTC", m.KRW.String(), 1.0, 50.0, 120.0, 120.0, 35.0}, table.Rows[0])
+		assert.Equal(t, 16800.0, table.Rows[1][9])
+
+		table, err = s.Holdings(m.InvestQuery{FundID: 2})
+		assert.NoError(t, err)
+		assert.Len(t, table.Rows, 1)
+	})
+
+	t.Run("Indicators", func(t *testing.T) {
+		table, err := s.Indicators(0)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"2025-01-01", nil, nil, nil, 3.1}, table.Rows[0])
+		assert.Equal(t, []any{"2025-01-02", uint(40), 0.0, 0.0, 3.2}, table.Rows[1])
+	})
+}
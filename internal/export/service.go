@@ -0,0 +1,206 @@
+package export
+
+import (
+	"fmt"
+	m "investindicator/internal/model"
+	"math"
+	"slices"
+	"sort"
+	"time"
+)
+
+const defaultDays = 365
+
+// memo. db.Storage가 구현
+type store interface {
+	RetrieveInvests(q m.InvestQuery) (*m.List[m.Invest], error)
+	RetreiveFundsSummaryOrderByFundId() ([]m.InvestSummary, error)
+	RetrieveFundNavs(fundId uint, since time.Time) ([]m.FundNav, error)
+	RetrieveMarketIndicators(since time.Time) ([]m.DailyIndex, error)
+	RetrieveHighYieldSpreads(since time.Time) ([]m.HighYieldSpread, error)
+}
+
+// memo. scrape.Scraper가 구현
+type exchanger interface {
+	ExchageRate() float64
+}
+
+// Service 저장된 이력을 내보내기 Table로 변환
+type Service struct {
+	stg store
+	ex  exchanger
+}
+
+func NewService(stg store, ex exchanger) *Service {
+	return &Service{stg: stg, ex: ex}
+}
+
+func since(days int) time.Time {
+	if days <= 0 {
+		days = defaultDays
+	}
+	return time.Now().AddDate(0, 0, -days)
+}
+
+// Ledger 조건별 투자 이력 전체. 페이지는 무시하고 정렬 미지정 시 일자 오름차순. 매도는 count가 음수
+func (s *Service) Ledger(q m.InvestQuery) (*Table, error) {
+
+	q.Page = m.Page{}
+	if q.Sort.Field == "" {
+		q.Sort = m.Sort{Field: "created_at"}
+	}
+	list, err := s.stg.RetrieveInvests(q)
+	if err != nil {
+		return nil, fmt.Errorf("RetrieveInvests 시 오류 발생. %w", err)
+	}
+
+	t := &Table{
+		Name:   "ledger",
+		Header: []string{"id", "created_at", "fund_id", "asset_id", "asset", "code", "currency", "price", "count", "amount"},
+	}
+	for _, iv := range list.Items {
+		t.Rows = append(t.Rows, []any{
+			iv.ID, iv.CreatedAt, iv.FundID, iv.AssetID, iv.Asset.Name, iv.Asset.Code, iv.Asset.Currency,
+			iv.Price, iv.Count, iv.Price * iv.Count,
+		})
+	}
+	return t, nil
+}
+
+/*
+Holdings 현재 보유 자산의 투자 원금과 평가 금액
+  - cost: 매수 금액 - 매도 금액 (자산 통화 기준)
+  - value_krw: USD 자산은 현재 환율 적용
+  - profit_rate: 매도 금액 포함 수익률(%). 자금 보유 자산 조회와 같은 기준
+  - q의 자금, 자산, 조회 가능 자금 조건만 사용
+*/
+func (s *Service) Holdings(q m.InvestQuery) (*Table, error) {
+
+	summaries, err := s.stg.RetreiveFundsSummaryOrderByFundId()
+	if err != nil {
+		return nil, fmt.Errorf("RetreiveFundsSummaryOrderByFundId 시 오류 발생. %w", err)
+	}
+	list, err := s.stg.RetrieveInvests(m.InvestQuery{FundID: q.FundID, AssetID: q.AssetID, Funds: q.Funds})
+	if err != nil {
+		return nil, fmt.Errorf("RetrieveInvests 시 오류 발생. %w", err)
+	}
+
+	type key struct{ fundId, assetId uint }
+	bought := make(map[key]float64)
+	sold := make(map[key]float64)
+	for _, iv := range list.Items {
+		k := key{iv.FundID, iv.AssetID}
+		if iv.Count < 0 {
+			sold[k] += -iv.Count * iv.Price
+		} else {
+			bought[k] += iv.Count * iv.Price
+		}
+	}
+
+	ex := s.ex.ExchageRate()
+
+	t := &Table{
+		Name:   "holdings",
+		Header: []string{"fund_id", "fund", "asset_id", "asset", "code", "currency", "count", "cost", "value", "value_krw", "profit_rate"},
+	}
+	for _, is := range summaries {
+		if is.Count == 0 || (q.Funds != nil && !slices.Contains(q.Funds, is.FundID)) ||
+			(q.FundID != 0 && is.FundID != q.FundID) || (q.AssetID != 0 && is.AssetID != q.AssetID) {
+			continue
+		}
+		k := key{is.FundID, is.AssetID}
+
+		valueKrw := is.Sum
+		if is.Asset.Currency == m.USD.String() {
+			valueKrw = is.Sum * ex
+		}
+		var profit any
+		if bought[k] != 0 {
+			profit = round(100 * (is.Sum + sold[k] - bought[k]) / bought[k])
+		}
+
+		t.Rows = append(t.Rows, []any{
+			is.FundID, is.Fund.Name, is.AssetID, is.Asset.Name, is.Asset.Code, is.Asset.Currency,
+			is.Count, round(bought[k] - sold[k]), round(is.Sum), round(valueKrw), profit,
+		})
+	}
+	return t, nil
+}
+
+// FundNavs 자금 평가 총액(원화) 추이. days가 0 이하면 최근 365일
+func (s *Service) FundNavs(fundId uint, days int) (*Table, error) {
+
+	navs, err := s.stg.RetrieveFundNavs(fundId, since(days))
+	if err != nil {
+		return nil, fmt.Errorf("RetrieveFundNavs 시 오류 발생. %w", err)
+	}
+
+	t := &Table{
+		Name:   fmt.Sprintf("fund%d_nav", fundId),
+		Header: []string{"created_at", "fund_id", "amount"},
+	}
+	for _, n := range navs {
+		t.Rows = append(t.Rows, []any{n.CreatedAt, n.FundID, n.Amount})
+	}
+	return t, nil
+}
+
+// Indicators 일자별 공포 탐욕 지수, 나스닥, S&P 500, 하이일드 스프레드. 값이 없는 일자는 빈 값
+func (s *Service) Indicators(days int) (*Table, error) {
+
+	from := since(days)
+	indexes, err := s.stg.RetrieveMarketIndicators(from)
+	if err != nil {
+		return nil, fmt.Errorf("RetrieveMarketIndicators 시 오류 발생. %w", err)
+	}
+	spreads, err := s.stg.RetrieveHighYieldSpreads(from)
+	if err != nil {
+		return nil, fmt.Errorf("RetrieveHighYieldSpreads 시 오류 발생. %w", err)
+	}
+
+	type day struct {
+		index  *m.DailyIndex
+		spread *m.HighYieldSpread
+	}
+	byDate := make(map[string]*day)
+	get := func(t time.Time) *day {
+		k := t.Format("2006-01-02")
+		if byDate[k] == nil {
+			byDate[k] = &day{}
+		}
+		return byDate[k]
+	}
+	for i := range indexes {
+		get(indexes[i].CreatedAt).index = &indexes[i]
+	}
+	for i := range spreads {
+		get(spreads[i].CreatedAt).spread = &spreads[i]
+	}
+
+	dates := make([]string, 0, len(byDate))
+	for k := range byDate {
+		dates = append(dates, k)
+	}
+	sort.Strings(dates)
+
+	t := &Table{
+		Name:   "indicators",
+		Header: []string{"date", "fear_greed", "nasdaq", "sp500", "hy_spread"},
+	}
+	for _, k := range dates {
+		d := byDate[k]
+		row := []any{k, nil, nil, nil, nil}
+		if d.index != nil {
+			row[1], row[2], row[3] = d.index.FearGreedIndex, d.index.NasDaq, d.index.Sp500
+		}
+		if d.spread != nil {
+			row[4] = d.spread.Spread
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t, nil
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,325 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	m "investindicator/internal/model"
+	"io"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const defaultTTL = 30 * time.Minute
+
+var (
+	ErrBatchNotFound = errors.New("존재하지 않거나 만료된 가져오기 요청")
+	ErrInvalidFile   = errors.New("거래 내역 파일 변환 불가")
+	ErrFundMissing   = errors.New("자금 미지정 행 존재")
+)
+
+// memo. db.Storage가 구현
+type store interface {
+	RetrieveAssetIdByCode(code string) uint
+	RetrieveAsset(id uint) (*m.Asset, error)
+	RetrieveInvestsSince(since time.Time) ([]m.Invest, error)
+}
+
+// memo. investind.InvestIndicator가 구현
+type recorder interface {
+	RecordInvests(invests []m.Invest) error
+}
+
+// Row 미리보기 행. Err가 있으면 기록 대상에서 제외
+type Row struct {
+	Record
+	AssetID   uint
+	AssetName string
+	FundID    uint
+	Duplicate bool // 기존 투자 이력에 같은 일자/자산/가격/수량 존재
+}
+
+// Batch 미리보기 결과. Commit 혹은 Discard 전까지 ttl 동안 보관
+type Batch struct {
+	ID        string
+	Name      string
+	Owner     string // 요청 주체. ex) user:1, apikey:3. 생성자 혹은 admin만 조회, 기록, 폐기 가능
+	Broker    Broker
+	Rows      []Row
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+type Importer struct {
+	stg store
+	rc  recorder
+	ttl time.Duration
+
+	mu      sync.Mutex
+	batches map[string]*Batch
+	lg      zerolog.Logger
+}
+
+type Option func(*Importer)
+
+func WithTTL(ttl time.Duration) Option {
+	return func(im *Importer) {
+		if ttl > 0 {
+			im.ttl = ttl
+		}
+	}
+}
+
+func NewImporter(stg store, rc recorder, opts ...Option) *Importer {
+	im := &Importer{
+		stg:     stg,
+		rc:      rc,
+		ttl:     defaultTTL,
+		batches: make(map[string]*Batch),
+		lg:      zerolog.New(os.Stdout).With().Str("Module", "Importer").Timestamp().Logger(),
+	}
+	for _, opt := range opts {
+		opt(im)
+	}
+	return im
+}
+
+/*
+Preview 파일 변환 후 자산 매핑, 중복 확인 결과 반환. 기록은 Commit에서 수행
+  - 종목 코드는 RetrieveAssetIdByCode로 매핑. 해외 주식은 거래소 prefix(NAS-, NYS-, AMS-)도 확인
+  - fundId는 전체 행의 기본 자금. 0이면 Commit 시 행별로 지정 필요
+  - owner는 요청 주체. 권한 확인은 호출하는 쪽에서 수행
+*/
+func (im *Importer) Preview(name string, r io.Reader, broker Broker, fundId uint, owner string) (*Batch, error) {
+
+	broker, records, err := Parse(name, r, broker)
+	if err != nil {
+		return nil, fmt.Errorf("%w. %w", ErrInvalidFile, err)
+	}
+
+	rows := make([]Row, len(records))
+	names := make(map[uint]string)
+	for i, rec := range records {
+		row := Row{Record: rec, FundID: fundId}
+		if rec.Err == "" {
+			row.AssetID = im.assetId(rec.Code)
+			if row.AssetID == 0 {
+				row.Err = fmt.Sprintf("미등록 자산. %s", rec.Code)
+			}
+		}
+		if row.AssetID != 0 {
+			if _, ok := names[row.AssetID]; !ok {
+				if a, err := im.stg.RetrieveAsset(row.AssetID); err == nil {
+					names[row.AssetID] = a.Name
+				}
+			}
+			row.AssetName = names[row.AssetID]
+		}
+		rows[i] = row
+	}
+
+	err = im.markDuplicates(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	b := &Batch{
+		ID:        uuid.NewString(),
+		Name:      name,
+		Owner:     owner,
+		Broker:    broker,
+		Rows:      rows,
+		CreatedAt: now,
+		ExpiresAt: now.Add(im.ttl),
+	}
+
+	im.mu.Lock()
+	im.expire(now)
+	im.batches[b.ID] = b
+	im.mu.Unlock()
+
+	im.lg.Info().Str("id", b.ID).Str("broker", string(broker)).Int("rows", len(rows)).Msg("가져오기 미리보기 생성")
+	return b, nil
+}
+
+func (im *Importer) Batch(id string) (*Batch, bool) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	im.expire(time.Now())
+	b, ok := im.batches[id]
+	return b, ok
+}
+
+/*
+Commit 미리보기 행을 한 transaction으로 기록. 기록한 행 수 반환
+  - funds: 행 번호별 자금 id. 0이면 해당 행 제외. 미지정 행은 미리보기 기본 자금 사용
+  - 오류 행은 제외하고, 중복 행은 includeDuplicates일 때만 기록
+  - 자금이 지정되지 않은 행이 있으면 아무것도 기록하지 않음
+*/
+func (im *Importer) Commit(id string, funds map[int]uint, includeDuplicates bool) (int, error) {
+
+	im.mu.Lock()
+	im.expire(time.Now())
+	b, ok := im.batches[id]
+	delete(im.batches, id) // 동시에 같은 요청을 두 번 기록하지 않도록 먼저 제거
+	im.mu.Unlock()
+	if !ok {
+		return 0, ErrBatchNotFound
+	}
+
+	invests := make([]m.Invest, 0, len(b.Rows))
+	for _, row := range b.Rows {
+		if row.Err != "" || (row.Duplicate && !includeDuplicates) {
+			continue
+		}
+		fundId := row.FundID
+		if f, ok := funds[row.Line]; ok {
+			if f == 0 {
+				continue
+			}
+			fundId = f
+		}
+		if fundId == 0 {
+			im.restore(b)
+			return 0, fmt.Errorf("%w. 행 번호: %d", ErrFundMissing, row.Line)
+		}
+
+		invest := m.Invest{
+			FundID:  fundId,
+			AssetID: row.AssetID,
+			Price:   row.Price,
+			Count:   row.Count,
+		}
+		invest.CreatedAt = row.TradedAt
+		invests = append(invests, invest)
+	}
+	if len(invests) == 0 {
+		return 0, nil
+	}
+
+	err := im.rc.RecordInvests(invests)
+	if err != nil {
+		im.restore(b)
+		return 0, err
+	}
+
+	im.lg.Info().Str("id", id).Int("invests", len(invests)).Msg("가져오기 기록 완료")
+	return len(invests), nil
+}
+
+func (im *Importer) Discard(id string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	if _, ok := im.batches[id]; !ok {
+		return ErrBatchNotFound
+	}
+	delete(im.batches, id)
+	return nil
+}
+
+// restore Commit 실패 시 재시도할 수 있도록 미리보기 복구
+func (im *Importer) restore(b *Batch) {
+	im.mu.Lock()
+	im.batches[b.ID] = b
+	im.mu.Unlock()
+}
+
+// expire 만료된 미리보기 정리. mu lock 상태에서 호출
+func (im *Importer) expire(now time.Time) {
+	for id, b := range im.batches {
+		if now.After(b.ExpiresAt) {
+			delete(im.batches, id)
+		}
+	}
+}
+
+func (im *Importer) assetId(code string) uint {
+	if id := im.stg.RetrieveAssetIdByCode(code); id != 0 {
+		return id
+	}
+	for _, prefix := range []string{"NAS-", "NYS-", "AMS-"} { // 해외 주식 스트림 체결 코드 형식
+		if id := im.stg.RetrieveAssetIdByCode(prefix + code); id != 0 {
+			return id
+		}
+	}
+	return 0
+}
+
+// markDuplicates 기존 투자 이력과 일자/자산/가격/수량이 같은 행 표시. 이력 1건은 1개 행에만 매칭
+func (im *Importer) markDuplicates(rows []Row) error {
+
+	var since time.Time
+	for _, row := range rows {
+		if row.Err == "" && (since.IsZero() || row.TradedAt.Before(since)) {
+			since = row.TradedAt
+		}
+	}
+	if since.IsZero() {
+		return nil
+	}
+	since = time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, since.Location())
+
+	invests, err := im.stg.RetrieveInvestsSince(since)
+	if err != nil {
+		return fmt.Errorf("RetrieveInvestsSince 시 오류 발생. %w", err)
+	}
+
+	ledger := make(map[string]int)
+	for _, iv := range invests {
+		ledger[dupKey(iv.AssetID, iv.CreatedAt, iv.Price, iv.Count)]++
+	}
+	for i := range rows {
+		if rows[i].Err != "" {
+			continue
+		}
+		k := dupKey(rows[i].AssetID, rows[i].TradedAt, rows[i].Price, rows[i].Count)
+		if ledger[k] > 0 {
+			ledger[k]--
+			rows[i].Duplicate = true
+		}
+	}
+	return nil
+}
+
+func dupKey(assetId uint, t time.Time, price float64, count float64) string {
+	round := func(v float64, digits float64) float64 {
+		p := math.Pow(10, digits)
+		return math.Round(v*p) / p
+	}
+	return fmt.Sprintf("%d|%s|%v|%v", assetId, t.Local().Format("20060102"), round(price, 4), round(count, 8))
+}
+
+// Summary 미리보기 요약. telegram 응답용
+func (b *Batch) Summary() string {
+	valid, dup, failed := 0, 0, 0
+	for _, row := range b.Rows {
+		switch {
+		case row.Err != "":
+			failed++
+		case row.Duplicate:
+			dup++
+		default:
+			valid++
+		}
+	}
+	s := fmt.Sprintf("%s (%s)\n 기록 대상: %d건\n 중복: %d건\n 제외: %d건", b.Name, b.Broker, valid, dup, failed)
+
+	lines := make([]string, 0)
+	for _, row := range b.Rows {
+		if row.Err != "" {
+			lines = append(lines, fmt.Sprintf("  %d행: %s", row.Line, row.Err))
+		}
+	}
+	if len(lines) > 10 {
+		lines = append(lines[:10], fmt.Sprintf("  외 %d건", len(lines)-10))
+	}
+	for _, l := range lines {
+		s += "\n" + l
+	}
+	return s
+}
@@ -0,0 +1,150 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	m "investindicator/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding/korean"
+)
+
+type storeMock struct {
+	invests []m.Invest
+}
+
+func (s *storeMock) RetrieveAssetIdByCode(code string) uint {
+	switch code {
+	case "BTC":
+		return 1
+	case "005930":
+		return 2
+	case "NAS-AAPL":
+		return 3
+	}
+	return 0
+}
+
+func (s *storeMock) RetrieveAsset(id uint) (*m.Asset, error) {
+	return &m.Asset{ID: id, Name: "자산"}, nil
+}
+
+func (s *storeMock) RetrieveInvestsSince(since time.Time) ([]m.Invest, error) {
+	return s.invests, nil
+}
+
+type recorderMock struct {
+	invests []m.Invest
+	err     error
+}
+
+func (r *recorderMock) RecordInvests(invests []m.Invest) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.invests = append(r.invests, invests...)
+	return nil
+}
+
+const upbitCsv = `거래내역 조회,,,,,,
+체결시간,코인,마켓,종류,거래수량,거래단가,거래금액
+2025-03-02 10:15:00,BTC,KRW,매수,0.01 BTC,"95,000,000 KRW","950,000 KRW"
+2025-03-03 11:00:00,BTC,KRW,매도,0.005 BTC,"97,000,000 KRW","485,000 KRW"
+2025-03-03 12:00:00,BTC,KRW,입금,0.1 BTC,,
+2025-03-04 09:00:00,XRP,KRW,매수,10 XRP,"3,000 KRW","30,000 KRW"
+`
+
+func TestParse(t *testing.T) {
+
+	t.Run("Upbit CSV", func(t *testing.T) {
+		broker, records, err := Parse("upbit.csv", strings.NewReader(upbitCsv), "")
+		assert.NoError(t, err)
+		assert.Equal(t, Upbit, broker)
+		assert.Len(t, records, 4)
+		assert.Equal(t, Record{Line: 3, TradedAt: time.Date(2025, 3, 2, 10, 15, 0, 0, time.Local), Code: "BTC", Price: 95000000, Count: 0.01}, records[0])
+		assert.Equal(t, -0.005, records[1].Count)
+		assert.NotEmpty(t, records[2].Err)
+	})
+
+	t.Run("KIS CSV EUC-KR", func(t *testing.T) {
+		src := "주문일자,종목코드,종목명,매매구분,체결수량,체결단가\n2025.03.02,A005930,삼성전자,현금매수,10,\"55,000\"\n"
+		b, _ := korean.EUCKR.NewEncoder().Bytes([]byte(src))
+		broker, records, err := Parse("kis.csv", bytes.NewReader(b), "")
+		assert.NoError(t, err)
+		assert.Equal(t, KIS, broker)
+		assert.Equal(t, "005930", records[0].Code)
+		assert.Equal(t, 55000.0, records[0].Price)
+		assert.Equal(t, 10.0, records[0].Count)
+	})
+
+	t.Run("Bithumb XLSX", func(t *testing.T) {
+		f := excelize.NewFile()
+		sheet := f.GetSheetName(0)
+		f.SetSheetRow(sheet, "A1", &[]string{"거래일시", "자산", "거래구분", "거래수량", "체결가격"})
+		f.SetSheetRow(sheet, "A2", &[]string{"2025-03-02 10:15:00", "BTC", "매도", "0.1", "96,000,000"})
+		var buf bytes.Buffer
+		assert.NoError(t, f.Write(&buf))
+
+		broker, records, err := Parse("bithumb.xlsx", &buf, "")
+		assert.NoError(t, err)
+		assert.Equal(t, Bithumb, broker)
+		assert.Equal(t, -0.1, records[0].Count)
+	})
+
+	t.Run("Unknown Header", func(t *testing.T) {
+		_, _, err := Parse("a.csv", strings.NewReader("a,b,c\n1,2,3\n"), "")
+		assert.Error(t, err)
+	})
+}
+
+func TestImporter(t *testing.T) {
+
+	stg := &storeMock{
+		invests: []m.Invest{{AssetID: 1, Price: 95000000, Count: 0.01}},
+	}
+	stg.invests[0].CreatedAt = time.Date(2025, 3, 2, 10, 15, 3, 0, time.Local) // 스트림으로 이미 기록된 체결
+	rc := &recorderMock{}
+	im := NewImporter(stg, rc)
+
+	t.Run("Preview", func(t *testing.T) {
+		b, err := im.Preview("upbit.csv", strings.NewReader(upbitCsv), Upbit, 0, "user:1")
+		assert.NoError(t, err)
+		assert.True(t, b.Rows[0].Duplicate)
+		assert.False(t, b.Rows[1].Duplicate)
+		assert.Equal(t, uint(1), b.Rows[1].AssetID)
+		assert.Contains(t, b.Rows[3].Err, "미등록 자산")
+		t.Log(b.Summary())
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		b, _ := im.Preview("upbit.csv", strings.NewReader(upbitCsv), Upbit, 0, "user:1")
+
+		_, err := im.Commit(b.ID, nil, false)
+		assert.Error(t, err) // 자금 미지정
+		assert.Empty(t, rc.invests)
+
+		n, err := im.Commit(b.ID, map[int]uint{4: 2}, false)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, uint(2), rc.invests[0].FundID)
+		assert.Equal(t, time.Date(2025, 3, 3, 11, 0, 0, 0, time.Local), rc.invests[0].CreatedAt)
+
+		_, err = im.Commit(b.ID, nil, false)
+		assert.ErrorIs(t, err, ErrBatchNotFound)
+	})
+
+	t.Run("Commit Failed", func(t *testing.T) {
+		b, _ := im.Preview("upbit.csv", strings.NewReader(upbitCsv), Upbit, 1, "user:1")
+		rc.err = errors.New("db error")
+		_, err := im.Commit(b.ID, nil, true)
+		assert.Error(t, err)
+
+		_, ok := im.Batch(b.ID) // 실패 시 재시도 가능
+		assert.True(t, ok)
+		assert.NoError(t, im.Discard(b.ID))
+	})
+}
@@ -0,0 +1,315 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding/korean"
+)
+
+// Broker 거래 내역 export 파일 제공처
+type Broker string
+
+const (
+	KIS     Broker = "kis"
+	Upbit   Broker = "upbit"
+	Bithumb Broker = "bithumb"
+)
+
+func ParseBroker(s string) (Broker, error) {
+	switch b := Broker(strings.ToLower(strings.TrimSpace(s))); b {
+	case KIS, Upbit, Bithumb:
+		return b, nil
+	case "":
+		return "", nil
+	}
+	return "", fmt.Errorf("지원하지 않는 제공처. 입력 값 :%s", s)
+}
+
+type field int
+
+const (
+	fDate field = iota
+	fCode
+	fSide
+	fPrice
+	fCount
+	fieldLen
+)
+
+/*
+제공처별 header 후보. 앞에 있을수록 우선
+  - KIS: 국내/해외 주식 거래내역
+  - Upbit: 거래내역 (체결시간, 코인, 종류, 거래수량, 거래단가 ...)
+  - Bithumb: 거래내역 (거래일시, 자산, 거래구분, 거래수량, 체결가격 ...)
+*/
+var headers = map[Broker][fieldLen][]string{
+	KIS: {
+		fDate:  {"체결일자", "거래일자", "매매일자", "주문일자", "일자"},
+		fCode:  {"종목코드", "상품번호", "티커"},
+		fSide:  {"매매구분", "매도매수구분", "거래구분", "구분"},
+		fPrice: {"체결단가", "거래단가", "체결가", "단가"},
+		fCount: {"체결수량", "거래수량", "수량"},
+	},
+	Upbit: {
+		fDate:  {"체결시간", "주문시간"},
+		fCode:  {"코인", "마켓"},
+		fSide:  {"종류", "주문종류"},
+		fPrice: {"거래단가", "체결가격"},
+		fCount: {"거래수량", "체결수량"},
+	},
+	Bithumb: {
+		fDate:  {"거래일시", "체결일시"},
+		fCode:  {"자산", "코인", "코인명"},
+		fSide:  {"거래구분", "구분"},
+		fPrice: {"체결가격", "거래단가", "거래가격"},
+		fCount: {"거래수량", "체결수량"},
+	},
+}
+
+// detect 순서. header 후보가 겹치면 앞의 제공처로 판단
+var brokers = []Broker{Upbit, Bithumb, KIS}
+
+const headerScanRows = 20 // header 탐색 최대 행 수. export 파일 상단의 제목/조회 조건 행 대비
+
+var dateLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006.01.02 15:04:05",
+	"2006.01.02 15:04",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006-01-02",
+	"2006.01.02",
+	"2006/01/02",
+	"20060102",
+}
+
+// Record 파일에서 읽은 체결 1건. 매도면 Count가 음수
+type Record struct {
+	Line     int // 파일 내 행 번호(1부터)
+	TradedAt time.Time
+	Code     string
+	Price    float64
+	Count    float64
+	Err      string // 변환 실패 사유. 비어있으면 정상
+}
+
+/*
+Parse 거래 내역 파일 변환
+  - name의 확장자가 .xlsx면 첫 번째 sheet, 그 외는 CSV(UTF-8 혹은 EUC-KR)로 읽음
+  - broker가 비어있으면 header로 제공처 판단
+  - 매수/매도 외 거래(입출금 등)는 Err를 채워서 반환
+*/
+func Parse(name string, r io.Reader, broker Broker) (Broker, []Record, error) {
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", nil, fmt.Errorf("파일 읽기 시 오류 발생. %w", err)
+	}
+
+	var rows [][]string
+	if strings.EqualFold(filepath.Ext(name), ".xlsx") || bytes.HasPrefix(b, []byte("PK")) {
+		rows, err = readXlsx(b)
+	} else {
+		rows, err = readCsv(b)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	broker, hi, cols, err := findHeader(rows, broker)
+	if err != nil {
+		return "", nil, err
+	}
+
+	records := make([]Record, 0, len(rows)-hi-1)
+	for i := hi + 1; i < len(rows); i++ {
+		row := rows[i]
+		if isBlank(row) {
+			continue
+		}
+		records = append(records, parseRow(broker, i+1, row, cols))
+	}
+	return broker, records, nil
+}
+
+func readXlsx(b []byte) ([][]string, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("xlsx 파일 열기 시 오류 발생. %w", err)
+	}
+	defer f.Close()
+
+	rows, err := f.GetRows(f.GetSheetName(0))
+	if err != nil {
+		return nil, fmt.Errorf("xlsx sheet 조회 시 오류 발생. %w", err)
+	}
+	return rows, nil
+}
+
+func readCsv(b []byte) ([][]string, error) {
+	b = bytes.TrimPrefix(b, []byte("\xef\xbb\xbf")) // UTF-8 BOM
+	if !utf8.Valid(b) {                             // 국내 증권사 export는 EUC-KR인 경우가 많음
+		decoded, err := korean.EUCKR.NewDecoder().Bytes(b)
+		if err != nil {
+			return nil, fmt.Errorf("EUC-KR 변환 시 오류 발생. %w", err)
+		}
+		b = decoded
+	}
+
+	cr := csv.NewReader(bytes.NewReader(b))
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("csv 변환 시 오류 발생. %w", err)
+	}
+	return rows, nil
+}
+
+// findHeader header 행 index와 항목별 열 index 조회
+func findHeader(rows [][]string, broker Broker) (Broker, int, [fieldLen]int, error) {
+	candidates := brokers
+	if broker != "" {
+		candidates = []Broker{broker}
+	}
+
+	for i := 0; i < len(rows) && i < headerScanRows; i++ {
+		for _, b := range candidates {
+			if cols, ok := matchHeader(rows[i], headers[b]); ok {
+				return b, i, cols, nil
+			}
+		}
+	}
+	return "", 0, [fieldLen]int{}, errors.New("거래 내역 header를 찾을 수 없음")
+}
+
+func matchHeader(row []string, names [fieldLen][]string) ([fieldLen]int, bool) {
+	var cols [fieldLen]int
+	for f := range fieldLen {
+		cols[f] = -1
+		for _, name := range names[f] {
+			for j, cell := range row {
+				if normalize(cell) == name && !used(cols[:f], j) {
+					cols[f] = j
+					break
+				}
+			}
+			if cols[f] >= 0 {
+				break
+			}
+		}
+		if cols[f] < 0 {
+			return cols, false
+		}
+	}
+	return cols, true
+}
+
+func used(cols []int, j int) bool {
+	for _, c := range cols {
+		if c == j {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRow(broker Broker, line int, row []string, cols [fieldLen]int) Record {
+	rec := Record{Line: line}
+	cell := func(f field) string {
+		if cols[f] >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[cols[f]])
+	}
+
+	var sign float64
+	switch side := strings.ToLower(cell(fSide)); {
+	case strings.Contains(side, "매수"), strings.Contains(side, "buy"), side == "bid":
+		sign = 1
+	case strings.Contains(side, "매도"), strings.Contains(side, "sell"), side == "ask":
+		sign = -1
+	default:
+		rec.Err = fmt.Sprintf("매수/매도 외 거래. %s", cell(fSide))
+		return rec
+	}
+
+	var err error
+	if rec.TradedAt, err = parseDate(cell(fDate)); err != nil {
+		rec.Err = err.Error()
+		return rec
+	}
+	if rec.Price, err = parseNumber(cell(fPrice)); err != nil {
+		rec.Err = fmt.Sprintf("가격 변환 실패. %s", cell(fPrice))
+		return rec
+	}
+	count, err := parseNumber(cell(fCount))
+	if err != nil {
+		rec.Err = fmt.Sprintf("수량 변환 실패. %s", cell(fCount))
+		return rec
+	}
+	rec.Count = sign * count
+	rec.Code = normalizeCode(broker, cell(fCode))
+	if rec.Code == "" {
+		rec.Err = "종목 코드 미존재"
+	}
+	return rec
+}
+
+// normalizeCode 스트림 체결과 같은 형식으로 변환. ex) "A005930" => "005930", "KRW-BTC" => "BTC"
+func normalizeCode(broker Broker, code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	switch broker {
+	case KIS:
+		if len(code) == 7 && code[0] == 'A' {
+			if _, err := strconv.Atoi(code[1:]); err == nil {
+				return code[1:]
+			}
+		}
+	case Upbit, Bithumb:
+		code = strings.TrimPrefix(code, "KRW-")
+		code = strings.TrimSuffix(code, "/KRW")
+		code, _, _ = strings.Cut(code, "(") // "BTC(비트코인)"
+	}
+	return strings.TrimSpace(code)
+}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("일자 변환 실패. %s", s)
+}
+
+// parseNumber 천 단위 구분자, 단위 제거 후 변환. ex) "95,000,000 KRW" => 95000000
+func parseNumber(s string) (float64, error) {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), " ")
+	s = strings.ReplaceAll(s, ",", "")
+	return strconv.ParseFloat(s, 64)
+}
+
+func normalize(s string) string {
+	return strings.ReplaceAll(strings.TrimSpace(strings.TrimPrefix(s, "\ufeff")), " ", "")
+}
+
+func isBlank(row []string) bool {
+	for _, c := range row {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
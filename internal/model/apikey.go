@@ -0,0 +1,83 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Scope API 키 권한 범위
+type Scope string
+
+const (
+	ScopeRead     Scope = "read"     // 조회
+	ScopeInvest   Scope = "invest"   // 투자 이력, 자산, 자금, 시장 단계 등 기록
+	ScopeEvent    Scope = "event"    // 이벤트 실행, on/off, 선택지 응답
+	ScopeTrading  Scope = "trading"  // 블록체인 swap 등 실거래
+	ScopeTelegram Scope = "telegram" // X-Telegram-User로 연동된 사용자 권한 위임. bot 전용
+)
+
+var scopeList = []Scope{ScopeRead, ScopeInvest, ScopeEvent, ScopeTrading, ScopeTelegram}
+
+func ScopeList() []string {
+	li := make([]string, len(scopeList))
+	for i, s := range scopeList {
+		li[i] = string(s)
+	}
+	return li
+}
+
+// ParseScopes 콤마 구분 문자열을 scope 목록으로 변환. ex) "read,invest"
+func ParseScopes(s string) ([]Scope, error) {
+
+	scopes := make([]Scope, 0)
+	for _, v := range strings.Split(s, ",") {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if !slices.Contains(scopeList, Scope(v)) {
+			return nil, errors.New("존재하지 않는 scope. 입력 값 :" + v)
+		}
+		if !slices.Contains(scopes, Scope(v)) {
+			scopes = append(scopes, Scope(v))
+		}
+	}
+	return scopes, nil
+}
+
+func JoinScopes(scopes []Scope) string {
+	li := make([]string, len(scopes))
+	for i, s := range scopes {
+		li[i] = string(s)
+	}
+	return strings.Join(li, ",")
+}
+
+// ApiKey 클라이언트(bot, script)별 API 키. 키 원문은 발급 시에만 응답하고 hash만 저장
+type ApiKey struct {
+	ID         uint
+	Name       string
+	Prefix     string `gorm:"uniqueIndex;size:16"` // 키 조회용 앞부분. 원문과 함께 노출되어도 무방
+	Hash       string // 키 secret의 sha256
+	Scopes     string // 콤마 구분 scope 목록
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+func (k ApiKey) ScopeList() []Scope {
+	scopes, _ := ParseScopes(k.Scopes)
+	return scopes
+}
+
+func (k ApiKey) HasScope(s Scope) bool {
+	return slices.Contains(k.ScopeList(), s)
+}
+
+// Active 폐기, 만료되지 않은 키
+func (k ApiKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// ActorType 상태 변경 주체 구분
+type ActorType string
+
+const (
+	ActorUser   ActorType = "user"   // 로그인 사용자 혹은 bot에 연동된 사용자. ID는 user id
+	ActorApiKey ActorType = "apikey" // 사용자 위임 없는 API 키 요청. ID는 키 id
+	ActorChat   ActorType = "chat"   // 사용자 미연동 관리자 chat. ID는 chat id
+	ActorSystem ActorType = "system" // 자동 실행 이벤트. ID는 이벤트 이름
+)
+
+// AuditSource 상태 변경 요청 경로
+type AuditSource string
+
+const (
+	SourceApi   AuditSource = "api"
+	SourceBot   AuditSource = "bot"
+	SourceEvent AuditSource = "event"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+	AuditRun    AuditAction = "run" // 이벤트 즉시 실행, swap 등 실행 요청
+)
+
+// Actor 상태 변경 주체. RequestID는 API 요청 id(X-Request-ID)
+type Actor struct {
+	Type      ActorType
+	ID        string
+	Source    AuditSource
+	RequestID string
+}
+
+// Key 요청 주체 구분 값. ex) user:1, apikey:3. 주체 미확인 시 빈 값
+func (a Actor) Key() string {
+	if a.Type == "" {
+		return ""
+	}
+	return string(a.Type) + ":" + a.ID
+}
+
+// AuditLog 상태 변경 이력. Before, After는 변경 전후 값의 JSON. 생성은 Before, 삭제는 After 미존재
+type AuditLog struct {
+	ID        uint        `json:"id"`
+	ActorType ActorType   `json:"actor_type" gorm:"size:16;index:idx_audit_actor"`
+	ActorID   string      `json:"actor_id" gorm:"size:64;index:idx_audit_actor"`
+	Source    AuditSource `json:"source" gorm:"size:16"`
+	Action    AuditAction `json:"action" gorm:"size:16"`
+	Entity    string      `json:"entity" gorm:"size:32;index:idx_audit_entity"`
+	EntityID  string      `json:"entity_id" gorm:"size:64;index:idx_audit_entity"`
+	Before    string      `json:"before" gorm:"type:text"`
+	After     string      `json:"after" gorm:"type:text"`
+	RequestID string      `json:"request_id" gorm:"size:64;index"`
+	CreatedAt time.Time   `json:"created_at" gorm:"index"`
+}
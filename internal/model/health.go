@@ -0,0 +1,78 @@
+package model
+
+import (
+	"sync"
+	"time"
+)
+
+// HealthStatus 구성 요소 상태
+type HealthStatus string
+
+const (
+	HealthUp       HealthStatus = "up"
+	HealthDegraded HealthStatus = "degraded" // 필수가 아닌 구성 요소 장애. 일부 기능 제한
+	HealthDown     HealthStatus = "down"
+)
+
+// ComponentHealth 구성 요소가 직접 보고하는 상태. Required 구성 요소 장애는 서비스 불가로 판단
+type ComponentHealth struct {
+	Name        string
+	Status      HealthStatus
+	Required    bool
+	Error       string
+	Detail      string
+	LastSuccess time.Time // 마지막 정상 확인(연결, 응답) 시각
+	LastRun     time.Time // 마지막 실행 시각. 성공 여부와 무관(이벤트)
+}
+
+func HealthyComponent(name string, required bool) ComponentHealth {
+	return ComponentHealth{Name: name, Status: HealthUp, Required: required, LastSuccess: time.Now()}
+}
+
+func FailedComponent(name string, required bool, err error) ComponentHealth {
+	return ComponentHealth{Name: name, Status: HealthDown, Required: required, Error: err.Error()}
+}
+
+/*
+OverallHealth 전체 상태
+  - Required 구성 요소가 하나라도 down이면 down
+  - 그 외 구성 요소가 up이 아니면 degraded
+*/
+func OverallHealth(components []ComponentHealth) HealthStatus {
+	status := HealthUp
+	for _, c := range components {
+		switch {
+		case c.Status == HealthUp:
+		case c.Required && c.Status == HealthDown:
+			return HealthDown
+		default:
+			status = HealthDegraded
+		}
+	}
+	return status
+}
+
+// HealthCache 외부 API를 호출하는 상태 확인 결과 재사용. 확인 요청이 몰려도 API 호출은 ttl당 1회. nil이면 매번 확인
+type HealthCache struct {
+	mu  sync.Mutex
+	ttl time.Duration
+	at  time.Time
+	h   ComponentHealth
+}
+
+func NewHealthCache(ttl time.Duration) *HealthCache {
+	return &HealthCache{ttl: ttl}
+}
+
+// Get ttl 이내 확인 결과가 있으면 반환, 없으면 check 실행. memo. 동시 요청은 진행 중인 check 결과를 대기
+func (c *HealthCache) Get(check func() ComponentHealth) ComponentHealth {
+	if c == nil {
+		return check()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.at.IsZero() || time.Since(c.at) >= c.ttl {
+		c.h, c.at = check(), time.Now()
+	}
+	return c.h
+}
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Provider 시세 조회 API 제공처. 제공처별로 호출 한도(rate limit)를 따로 관리
+type Provider string
+
+const (
+	NoProvider Provider = ""
+	KIS        Provider = "kis"
+	Bithumb    Provider = "bithumb"
+	Upbit      Provider = "upbit"
+	Alpaca     Provider = "alpaca"
+)
+
+var providerList = []Provider{KIS, Bithumb, Upbit, Alpaca}
+
+// Provider 카테고리별 현재가 조회 기본 제공처. 현금/달러처럼 API 호출이 없는 경우 NoProvider
+func (c Category) Provider() Provider {
+	switch c {
+	case DomesticStock, Gold, DomesticETF, DomesticGoldETF, ForeignStock, ForeignETF:
+		return KIS
+	case DomesticCoin:
+		return Bithumb
+	case ForeignCoin:
+		return Alpaca
+	default:
+		return NoProvider
+	}
+}
+
+func ProviderList() []Provider {
+	return providerList
+}
+
+func IsProvider(s string) bool {
+	for _, p := range providerList {
+		if string(p) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Supports 제공처가 카테고리의 시세를 조회할 수 있는지 여부. scrape의 PriceProvider도 동일 기준 사용
+func (p Provider) Supports(c Category) bool {
+	switch p {
+	case KIS:
+		return c.Provider() == KIS
+	case Bithumb, Upbit:
+		return c == DomesticCoin
+	case Alpaca:
+		return c == ForeignCoin
+	}
+	return false
+}
+
+// DefaultProviders 카테고리별 기본 제공처 체인. 앞 제공처 조회 실패 시 다음 제공처로 fallback
+func (c Category) DefaultProviders() []Provider {
+	switch c {
+	case DomesticCoin:
+		return []Provider{Bithumb, Upbit}
+	}
+	if p := c.Provider(); p != NoProvider {
+		return []Provider{p}
+	}
+	return nil
+}
+
+// ParseProviders 콤마 구분 문자열을 제공처 체인으로 변환. ex) "bithumb,upbit"
+func ParseProviders(s string) ([]Provider, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	chain := make([]Provider, 0)
+	for _, v := range strings.Split(s, ",") {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if !IsProvider(v) {
+			return nil, errors.New("존재하지 않는 제공처. 입력 값 :" + v)
+		}
+		if !slices.Contains(chain, Provider(v)) {
+			chain = append(chain, Provider(v))
+		}
+	}
+	return chain, nil
+}
+
+// CheckProviders 체인에 카테고리를 조회할 수 있는 제공처가 없으면 오류. 빈 체인은 카테고리 기본값을 사용하므로 허용
+func (c Category) CheckProviders(chain []Provider) error {
+	if len(chain) == 0 || slices.ContainsFunc(chain, func(p Provider) bool { return p.Supports(c) }) {
+		return nil
+	}
+	return fmt.Errorf("%s 시세를 조회할 수 있는 제공처 없음. 입력 값 :%s", c, JoinProviders(chain))
+}
+
+func JoinProviders(chain []Provider) string {
+	li := make([]string, len(chain))
+	for i, p := range chain {
+		li[i] = string(p)
+	}
+	return strings.Join(li, ",")
+}
+
+// ProviderChain 자산의 시세 제공처 체인. 자산에 지정된 체인이 없거나 카테고리를 조회할 수 있는 제공처가 없으면 카테고리 기본값 사용
+func (a Asset) ProviderChain() []Provider {
+	chain, err := ParseProviders(a.Providers)
+	if err != nil || len(chain) == 0 || a.Category.CheckProviders(chain) != nil {
+		return a.Category.DefaultProviders()
+	}
+	return chain
+}
+
+// ProviderStatus 제공처 상태. 연속 실패가 임계치를 넘으면 일정 시간 동안 unhealthy로 간주하고 후순위로 밀림
+type ProviderStatus struct {
+	Provider    Provider
+	Healthy     bool
+	Failures    int // 연속 실패 횟수
+	LastError   string
+	LastSuccess time.Time
+	LastFailure time.Time
+}
+
+// Quote 시세 조회 결과. 조회 제공처와 조회 시각을 함께 보관
+type Quote struct {
+	Price     float64
+	Source    Provider
+	FetchedAt time.Time
+}
+
+func (q Quote) Age() time.Duration {
+	return time.Since(q.FetchedAt)
+}
+
+// Tick 실시간 시세 스트림으로 수신한 체결가
+type Tick struct {
+	Category Category
+	Code     string
+	Quote
+}
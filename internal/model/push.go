@@ -0,0 +1,97 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Topic 실시간 push 구독 단위
+type Topic string
+
+const (
+	TopicAlert  Topic = "alerts"  // 자산 매수/매도 기준 도달
+	TopicFill   Topic = "fills"   // 체결 건 투자 이력 기록
+	TopicEvent  Topic = "events"  // 이벤트 실행 결과
+	TopicPrice  Topic = "prices"  // 실시간 시세
+	TopicReport Topic = "reports" // Blackhole 전략 보고
+)
+
+var topicList = []Topic{TopicAlert, TopicFill, TopicEvent, TopicPrice, TopicReport}
+
+func TopicList() []string {
+	li := make([]string, len(topicList))
+	for i, t := range topicList {
+		li[i] = string(t)
+	}
+	return li
+}
+
+// AdminOnly 자금 구분 없이 운영 정보를 담는 topic. admin, 사용자 위임 없는 API 키만 구독 가능
+func (t Topic) AdminOnly() bool {
+	return t == TopicReport
+}
+
+// ParseTopics 콤마 구분 문자열을 topic 목록으로 변환. 빈 값이면 시세를 제외한 전체
+func ParseTopics(s string) ([]Topic, error) {
+
+	if strings.TrimSpace(s) == "" {
+		return []Topic{TopicAlert, TopicFill, TopicEvent, TopicReport}, nil
+	}
+
+	topics := make([]Topic, 0)
+	for _, v := range strings.Split(s, ",") {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if !slices.Contains(topicList, Topic(v)) {
+			return nil, errors.New("존재하지 않는 topic. 입력 값 :" + v)
+		}
+		if !slices.Contains(topics, Topic(v)) {
+			topics = append(topics, Topic(v))
+		}
+	}
+	return topics, nil
+}
+
+// Push 실시간 전달 메시지. FundID가 0이 아니면 해당 자금 조회 권한이 있는 구독자에게만 전달
+type Push struct {
+	Topic  Topic     `json:"topic"`
+	FundID uint      `json:"-"`
+	Data   any       `json:"data"`
+	Time   time.Time `json:"time"`
+}
+
+// PriceAlert TopicAlert 메시지
+type PriceAlert struct {
+	AssetID uint    `json:"asset_id"`
+	Name    string  `json:"name"`
+	Side    Side    `json:"side"`
+	Bound   float64 `json:"bound"` // 매수 하한 혹은 매도 상한
+	Price   float64 `json:"price"`
+}
+
+// EventRun TopicEvent 메시지
+type EventRun struct {
+	ID       uint          `json:"id"`
+	Title    string        `json:"title"`
+	Manual   bool          `json:"manual"`
+	Started  time.Time     `json:"started_at"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Fill TopicFill 메시지
+type Fill struct {
+	FundID  uint    `json:"fund_id"`
+	AssetID uint    `json:"asset_id"`
+	Code    string  `json:"code"`
+	Price   float64 `json:"price"`
+	Count   float64 `json:"count"`
+}
+
+// PriceTick TopicPrice 메시지
+type PriceTick struct {
+	Category Category `json:"category"`
+	Code     string   `json:"code"`
+	Price    float64  `json:"price"`
+	Source   Provider `json:"source"`
+}
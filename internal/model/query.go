@@ -0,0 +1,110 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Page 목록 조회 범위. Limit이 0 이하면 전체, Cursor 지정 시 Offset 무시
+type Page struct {
+	Limit  int
+	Offset int
+	Cursor string // 이전 조회 결과의 NextCursor
+}
+
+// Sort 정렬 기준. Field가 비어있으면 조회 대상별 기본 정렬
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort "-created_at" 형식을 변환. '-' prefix는 내림차순
+func ParseSort(s string, allowed []string) (Sort, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Sort{}, nil
+	}
+	sort := Sort{Field: strings.TrimPrefix(s, "-"), Desc: strings.HasPrefix(s, "-")}
+	if !slices.Contains(allowed, sort.Field) {
+		return Sort{}, fmt.Errorf("지원하지 않는 정렬 기준. 입력 값 :%s, 허용 값 :%s", s, strings.Join(allowed, ","))
+	}
+	return sort, nil
+}
+
+// List 목록 조회 결과. Total은 범위 적용 전 건수, NextCursor가 비어있으면 마지막
+type List[T any] struct {
+	Items      []T
+	Total      int64
+	NextCursor string
+}
+
+type Side string
+
+const (
+	Buy  Side = "buy"
+	Sell Side = "sell"
+)
+
+func ToSide(s string) (Side, error) {
+	switch side := Side(strings.ToLower(strings.TrimSpace(s))); side {
+	case Buy, Sell, "":
+		return side, nil
+	}
+	return "", fmt.Errorf("존재하지 않는 거래 구분. 입력 값 :%s", s)
+}
+
+var InvestSortFields = []string{"created_at", "price", "count", "amount"}
+
+/*
+InvestQuery 투자 이력 조회 조건. 0 혹은 빈 값은 조건 미적용
+  - Funds: 조회 가능한 자금 id. nil이면 전체
+  - MinAmount, MaxAmount: 거래 금액(|가격 * 수량|) 범위
+  - Start 이상, End 미만
+  - 기본 정렬은 created_at 내림차순
+*/
+type InvestQuery struct {
+	FundID    uint
+	AssetID   uint
+	Funds     []uint
+	Category  Category
+	Side      Side
+	MinAmount float64
+	MaxAmount float64
+	Start     time.Time
+	End       time.Time
+	Sort
+	Page
+}
+
+var AssetSortFields = []string{"id", "name", "code", "category"}
+
+/*
+AssetQuery 자산 조회 조건. 0 혹은 빈 값은 조건 미적용
+  - Keyword: 이름 혹은 코드 부분 일치
+  - 기본 정렬은 id 오름차순
+*/
+type AssetQuery struct {
+	Category Category
+	Currency string
+	Keyword  string
+	Sort
+	Page
+}
+
+/*
+AuditQuery 상태 변경 이력 조회 조건. 빈 값은 조건 미적용
+  - 정렬은 최신순 고정
+*/
+type AuditQuery struct {
+	ActorType ActorType
+	ActorID   string
+	Entity    string
+	EntityID  string
+	Action    AuditAction
+	RequestID string
+	Start     time.Time
+	End       time.Time
+	Page
+}
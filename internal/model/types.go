@@ -27,6 +27,7 @@ type Asset struct {
 	Bottom    float64
 	SellPrice float64
 	BuyPrice  float64
+	Providers string `gorm:"size:64"` // 시세 제공처 체인. 콤마 구분, 미지정 시 카테고리 기본값
 	gorm.Model
 }
 
@@ -37,6 +38,7 @@ type EmaHist struct {
 	Date    time.Time
 	Ema     float64
 	NDays   uint
+	Price   float64 // EMA 계산에 사용한 종가
 }
 
 type Invest struct {
@@ -60,6 +62,30 @@ type InvestSummary struct {
 	Sum     float64
 }
 
+// SummaryChange 자금/자산별 보유 수량 변동. InvestSummary 갱신용
+type SummaryChange struct {
+	FundID  uint
+	AssetID uint
+	Change  float64
+	Price   float64
+}
+
+// FundNav 자금별 일별 평가 총액(원화). 차트용
+type FundNav struct {
+	ID        uint
+	FundID    uint `gorm:"index"`
+	Amount    float64
+	CreatedAt time.Time
+}
+
+// PremiumHist 김치 프리미엄 이력(%). 차트용
+type PremiumHist struct {
+	ID        uint
+	AssetID   uint `gorm:"index"`
+	Premium   float64
+	CreatedAt time.Time
+}
+
 type Market struct {
 	ID        uint
 	Status    uint
@@ -91,11 +117,61 @@ type Sample struct {
 }
 
 type User struct {
-	ID       int
-	Username string
-	Email    string
-	Password string
-	IsAdmin  bool
+	ID         int
+	Username   string
+	Email      string
+	Password   string
+	IsAdmin    bool
+	TelegramId *int64 `gorm:"uniqueIndex"` // telegram user id. 미연동 사용자는 NULL
+	Disabled   bool   // 비활성 사용자. 로그인, telegram 명령 거부
+}
+
+// Role 사용자 권한. viewer는 공유받은 자금의 권한(FundRole) 내에서만, admin은 쓰기 및 전체 자금 관리 가능
+type Role string
+
+const (
+	Viewer Role = "viewer"
+	Admin  Role = "admin"
+)
+
+func (u User) Role() Role {
+	if u.IsAdmin {
+		return Admin
+	}
+	return Viewer
+}
+
+// FundRole 자금별 사용자 권한. owner는 공유 관리, editor는 투자 이력 기록, viewer는 조회만 가능
+type FundRole string
+
+const (
+	FundOwner  FundRole = "owner"
+	FundEditor FundRole = "editor"
+	FundViewer FundRole = "viewer"
+)
+
+func FundRoleList() []string {
+	return []string{string(FundOwner), string(FundEditor), string(FundViewer)}
+}
+
+// CanEdit 투자 이력, 거래 내역 기록 가능 여부
+func (r FundRole) CanEdit() bool {
+	return r == FundOwner || r == FundEditor
+}
+
+// CanManage 자금 공유 관리 가능 여부
+func (r FundRole) CanManage() bool {
+	return r == FundOwner
+}
+
+// FundMember 사용자별 자금 권한. admin은 등록 없이 전체 자금 관리 가능
+type FundMember struct {
+	ID     uint
+	FundID uint     `gorm:"uniqueIndex:idx_fund_user"`
+	Fund   Fund     `gorm:"constraint:OnDelete:CASCADE"`
+	UserID int      `gorm:"uniqueIndex:idx_fund_user"`
+	User   User     `gorm:"constraint:OnDelete:CASCADE"`
+	Role   FundRole `gorm:"size:16;default:viewer"` // memo. 권한 도입 전 등록된 사용자는 viewer
 }
 
 type Event struct {
@@ -0,0 +1,72 @@
+package pricebus
+
+import (
+	m "investindicator/internal/model"
+	"os"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// Bus 실시간 시세를 프로세스 내 구독자들에게 전달하는 pub/sub.
+// memo. 구독자가 느려도 스트림 수신이 막히지 않도록, 버퍼가 가득 찬 구독자에게는 해당 tick을 버림
+type Bus struct {
+	mu      sync.Mutex
+	subs    map[int]chan m.Tick
+	next    int
+	dropped map[int]uint64
+	lg      zerolog.Logger
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		subs:    make(map[int]chan m.Tick),
+		dropped: make(map[int]uint64),
+		lg:      zerolog.New(os.Stdout).With().Str("Module", "PriceBus").Timestamp().Logger(),
+	}
+}
+
+// Subscribe 구독 채널과 구독 해지 함수 반환. 해지 시 채널은 닫힘
+func (b *Bus) Subscribe(buf int) (<-chan m.Tick, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.next
+	b.next++
+	c := make(chan m.Tick, buf)
+	b.subs[id] = c
+
+	var once sync.Once
+	return c, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, id)
+			delete(b.dropped, id)
+			close(c)
+		})
+	}
+}
+
+func (b *Bus) Publish(t m.Tick) {
+	b.mu.Lock() // dropped 집계 때문에 쓰기 잠금 사용
+	defer b.mu.Unlock()
+
+	for id, c := range b.subs {
+		select {
+		case c <- t:
+		default:
+			b.dropped[id]++
+			if b.dropped[id]%100 == 1 {
+				b.lg.Warn().Int("subscriber", id).Uint64("dropped", b.dropped[id]).Msg("구독자 처리 지연으로 tick 누락")
+			}
+		}
+	}
+}
+
+// Forward 채널로 들어오는 tick을 bus로 전달. 채널이 닫히면 종료
+func (b *Bus) Forward(c <-chan m.Tick) {
+	for t := range c {
+		b.Publish(t)
+	}
+}
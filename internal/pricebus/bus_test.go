@@ -0,0 +1,41 @@
+package pricebus
+
+import (
+	m "investindicator/internal/model"
+	"testing"
+)
+
+func TestBus(t *testing.T) {
+
+	b := NewBus()
+	c1, cancel1 := b.Subscribe(1)
+	c2, cancel2 := b.Subscribe(10)
+	defer cancel2()
+
+	b.Publish(m.Tick{Category: m.DomesticCoin, Code: "BTC", Quote: m.Quote{Price: 100}})
+	b.Publish(m.Tick{Category: m.DomesticCoin, Code: "BTC", Quote: m.Quote{Price: 200}}) // c1 버퍼 초과로 누락
+
+	t.Run("Slow Subscriber", func(t *testing.T) {
+		if tk := <-c1; tk.Price != 100 {
+			t.Error(tk)
+		}
+		if len(c1) != 0 {
+			t.Error(len(c1))
+		}
+	})
+
+	t.Run("Subscriber", func(t *testing.T) {
+		if len(c2) != 2 {
+			t.Error(len(c2))
+		}
+	})
+
+	t.Run("Unsubscribe", func(t *testing.T) {
+		cancel1()
+		cancel1() // 중복 해지 허용
+		if _, ok := <-c1; ok {
+			t.Error("구독 해지 후 채널 미종료")
+		}
+		b.Publish(m.Tick{Code: "ETH"})
+	})
+}
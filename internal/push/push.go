@@ -0,0 +1,85 @@
+package push
+
+import (
+	m "investindicator/internal/model"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type subscriber struct {
+	c       chan m.Push
+	topics  []m.Topic
+	dropped uint64
+}
+
+/*
+Hub 알림, 체결, 이벤트 실행 결과 등 도메인 이벤트를 API 구독자(SSE)에게 전달하는 pub/sub
+  - 구독자는 topic 단위로 구독
+  - memo. pricebus.Bus와 같이 구독자가 느려도 발행이 막히지 않도록, 버퍼가 가득 찬 구독자에게는 해당 메시지를 버림
+*/
+type Hub struct {
+	mu   sync.Mutex
+	subs map[int]*subscriber
+	next int
+	lg   zerolog.Logger
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subs: make(map[int]*subscriber),
+		lg:   zerolog.New(os.Stdout).With().Str("Module", "Push").Timestamp().Logger(),
+	}
+}
+
+// Subscribe 구독 채널과 구독 해지 함수 반환. 해지 시 채널은 닫힘
+func (h *Hub) Subscribe(buf int, topics ...m.Topic) (<-chan m.Push, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := h.next
+	h.next++
+	s := &subscriber{c: make(chan m.Push, buf), topics: topics}
+	h.subs[id] = s
+
+	var once sync.Once
+	return s.c, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(h.subs, id)
+			close(s.c)
+		})
+	}
+}
+
+// Publish fundId가 0이 아니면 구독자가 자금 조회 권한을 확인하여 전달
+func (h *Hub) Publish(topic m.Topic, fundId uint, data any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	p := m.Push{Topic: topic, FundID: fundId, Data: data, Time: time.Now()}
+	for id, s := range h.subs {
+		if !slices.Contains(s.topics, topic) {
+			continue
+		}
+		select {
+		case s.c <- p:
+		default:
+			s.dropped++
+			if s.dropped%100 == 1 {
+				h.lg.Warn().Int("subscriber", id).Uint64("dropped", s.dropped).Msg("구독자 처리 지연으로 메시지 누락")
+			}
+		}
+	}
+}
+
+// ForwardTicks 시세 채널의 tick을 prices topic으로 전달. 채널이 닫히면 종료
+func (h *Hub) ForwardTicks(c <-chan m.Tick) {
+	for t := range c {
+		h.Publish(m.TopicPrice, 0, m.PriceTick{Category: t.Category, Code: t.Code, Price: t.Price, Source: t.Source})
+	}
+}
@@ -0,0 +1,51 @@
+package push
+
+import (
+	m "investindicator/internal/model"
+	"testing"
+)
+
+func TestHub(t *testing.T) {
+
+	h := NewHub()
+	alerts, cancelAlerts := h.Subscribe(1, m.TopicAlert)
+	all, cancelAll := h.Subscribe(10, m.TopicAlert, m.TopicFill)
+	defer cancelAll()
+
+	h.Publish(m.TopicAlert, 0, "BUY BTC")
+	h.Publish(m.TopicFill, 1, m.Fill{FundID: 1})
+	h.Publish(m.TopicAlert, 0, "SELL BTC") // alerts 버퍼 초과로 누락
+
+	t.Run("Topic", func(t *testing.T) {
+		if p := <-alerts; p.Data != "BUY BTC" {
+			t.Error(p)
+		}
+		if len(alerts) != 0 {
+			t.Error(len(alerts))
+		}
+		if len(all) != 3 {
+			t.Error(len(all))
+		}
+	})
+
+	t.Run("Unsubscribe", func(t *testing.T) {
+		cancelAlerts()
+		cancelAlerts() // 중복 해지 허용
+		if _, ok := <-alerts; ok {
+			t.Error("구독 해지 후 채널 미종료")
+		}
+		h.Publish(m.TopicAlert, 0, "BUY ETH")
+	})
+
+	t.Run("Ticks", func(t *testing.T) {
+		prices, cancel := h.Subscribe(1, m.TopicPrice)
+		defer cancel()
+		c := make(chan m.Tick, 1)
+		c <- m.Tick{Category: m.DomesticCoin, Code: "BTC", Quote: m.Quote{Price: 100}}
+		close(c)
+		h.ForwardTicks(c)
+		if p := <-prices; p.Data.(m.PriceTick).Price != 100 {
+			t.Error(p)
+		}
+	})
+}
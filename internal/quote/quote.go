@@ -0,0 +1,165 @@
+package quote
+
+import (
+	"errors"
+	"fmt"
+	m "investindicator/internal/model"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/singleflight"
+)
+
+const defaultTTL = 30 * time.Second
+
+// 카테고리별 기본 TTL. 코인은 변동이 커서 짧게, 환율처럼 자체 캐시가 있는 경우는 길게
+var defaultTTLs = map[m.Category]time.Duration{
+	m.Won:          24 * time.Hour,
+	m.Dollar:       time.Hour,
+	m.DomesticCoin: 10 * time.Second,
+	m.ForeignCoin:  10 * time.Second,
+	m.ForeignStock: time.Minute,
+	m.ForeignETF:   time.Minute,
+}
+
+// memo. scrape.Scraper가 구현
+type fetcher interface {
+	Quote(category m.Category, code string, providers ...m.Provider) (m.Quote, error)
+}
+
+// Cache scraper 앞단의 시세 캐시. 여러 호출처에서 같은 종목을 반복 조회하는 것을 막음
+type Cache struct {
+	f     fetcher
+	ttls  map[m.Category]time.Duration
+	mu    sync.RWMutex
+	items map[string]m.Quote
+	group singleflight.Group // 동일 종목 동시 조회 시 한 번만 호출
+	lg    zerolog.Logger
+}
+
+type Option func(*Cache)
+
+func WithTTL(category m.Category, d time.Duration) Option {
+	return func(c *Cache) {
+		if d > 0 {
+			c.ttls[category] = d
+		}
+	}
+}
+
+func WithTTLs(ttls map[m.Category]time.Duration) Option {
+	return func(c *Cache) {
+		for category, d := range ttls {
+			WithTTL(category, d)(c)
+		}
+	}
+}
+
+func NewCache(f fetcher, opts ...Option) *Cache {
+	c := &Cache{
+		f:     f,
+		ttls:  make(map[m.Category]time.Duration),
+		items: make(map[string]m.Quote),
+		lg:    zerolog.New(os.Stdout).With().Str("Module", "QuoteCache").Timestamp().Logger(),
+	}
+	for category, d := range defaultTTLs {
+		c.ttls[category] = d
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Cache) TTL(category m.Category) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if d, ok := c.ttls[category]; ok {
+		return d
+	}
+	return defaultTTL
+}
+
+/*
+Quote maxAge 이내에 조회된 시세가 있으면 캐시 값을, 없으면 새로 조회. maxAge가 0 이하면 카테고리 TTL 사용
+  - 종목별로 최근 시세 하나만 보관. providers 지정 시 조회 제공처가 체인에 포함된 시세만 사용
+  - 동시 조회는 제공처 체인별로 한 번만 호출
+*/
+func (c *Cache) Quote(category m.Category, code string, maxAge time.Duration, providers ...m.Provider) (m.Quote, error) {
+	if maxAge <= 0 {
+		maxAge = c.TTL(category)
+	}
+	k := key(category, code)
+
+	c.mu.RLock()
+	q, ok := c.items[k]
+	c.mu.RUnlock()
+	if ok && q.Age() <= maxAge && (len(providers) == 0 || slices.Contains(providers, q.Source)) {
+		return q, nil
+	}
+
+	v, err, _ := c.group.Do(flightKey(k, providers), func() (any, error) {
+		q, err := c.f.Quote(category, code, providers...)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.items[k] = q
+		c.mu.Unlock()
+		return q, nil
+	})
+	if err != nil {
+		return m.Quote{}, fmt.Errorf("시세 조회 시 오류 발생. %w", err)
+	}
+
+	q, ok = v.(m.Quote)
+	if !ok {
+		return m.Quote{}, errors.New("시세 캐시 타입 오류")
+	}
+	return q, nil
+}
+
+// PresentPrice 카테고리 TTL 기준으로 캐시된 현재가 조회
+func (c *Cache) PresentPrice(category m.Category, code string, providers ...m.Provider) (float64, error) {
+	q, err := c.Quote(category, code, 0, providers...)
+	return q.Price, err
+}
+
+// Put 외부에서 수신한 시세 저장. 실시간 스트림 tick 반영 시 사용. 기존 값보다 오래된 시세는 무시
+func (c *Cache) Put(category m.Category, code string, q m.Quote) {
+	k := key(category, code)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.items[k]; ok && old.FetchedAt.After(q.FetchedAt) {
+		return
+	}
+	c.items[k] = q
+}
+
+// Invalidate 종목 캐시 삭제. 매매 체결 등으로 최신 가격이 필요할 때 사용
+func (c *Cache) Invalidate(category m.Category, code string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key(category, code))
+}
+
+func key(category m.Category, code string) string {
+	return fmt.Sprintf("%d:%s", category, code)
+}
+
+// flightKey 동시 조회 구분 키. 제공처 체인이 다르면 별도로 조회
+func flightKey(k string, providers []m.Provider) string {
+	if len(providers) == 0 {
+		return k
+	}
+	chain := make([]string, len(providers))
+	for i, p := range providers {
+		chain[i] = string(p)
+	}
+	return k + ":" + strings.Join(chain, ",")
+}
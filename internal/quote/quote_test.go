@@ -0,0 +1,95 @@
+package quote
+
+import (
+	"errors"
+	m "investindicator/internal/model"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fetcherMock struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (f *fetcherMock) Quote(category m.Category, code string, providers ...m.Provider) (m.Quote, error) {
+	f.calls.Add(1)
+	time.Sleep(10 * time.Millisecond)
+	if f.err != nil {
+		return m.Quote{}, f.err
+	}
+	return m.Quote{Price: 1000, Source: m.Bithumb, FetchedAt: time.Now()}, nil
+}
+
+func TestQuoteCache(t *testing.T) {
+
+	f := &fetcherMock{}
+	c := NewCache(f, WithTTL(m.DomesticStock, time.Hour))
+
+	t.Run("Cached", func(t *testing.T) {
+		q, err := c.Quote(m.DomesticStock, "005930", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.Price != 1000 || q.Source != m.Bithumb {
+			t.Error(q)
+		}
+
+		c.Quote(m.DomesticStock, "005930", 0)
+		if f.calls.Load() != 1 {
+			t.Error(f.calls.Load())
+		}
+	})
+
+	t.Run("Max Age", func(t *testing.T) {
+		time.Sleep(5 * time.Millisecond)
+		c.Quote(m.DomesticStock, "005930", time.Millisecond)
+		if f.calls.Load() != 2 {
+			t.Error(f.calls.Load())
+		}
+	})
+
+	t.Run("Invalidate", func(t *testing.T) {
+		c.Invalidate(m.DomesticStock, "005930")
+		c.PresentPrice(m.DomesticStock, "005930")
+		if f.calls.Load() != 3 {
+			t.Error(f.calls.Load())
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c.Quote(m.DomesticCoin, "BTC", 0)
+			}()
+		}
+		wg.Wait()
+		if f.calls.Load() != 4 {
+			t.Error(f.calls.Load())
+		}
+	})
+
+	t.Run("Provider Chain", func(t *testing.T) {
+		c.Quote(m.DomesticStock, "005930", 0, m.Bithumb)
+		if f.calls.Load() != 4 {
+			t.Error("체인에 포함된 제공처 시세는 캐시 사용", f.calls.Load())
+		}
+		c.Quote(m.DomesticStock, "005930", 0, m.KIS)
+		if f.calls.Load() != 5 {
+			t.Error("체인 밖 제공처 시세는 재조회", f.calls.Load())
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		f.err = errors.New("api error")
+		_, err := c.Quote(m.ForeignStock, "AAPL", 0)
+		if !errors.Is(err, f.err) {
+			t.Error(err)
+		}
+	})
+}
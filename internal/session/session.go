@@ -0,0 +1,428 @@
+package session
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+const (
+	keyPrefix  = "session:"
+	failPrefix = "login-fail:"
+
+	pruneInterval = time.Minute // 만료된 메모리 세션, 로그인 실패 기록 정리 주기
+)
+
+var (
+	ErrInvalidToken = errors.New("유효하지 않은 refresh token")
+)
+
+// memo. db.Storage가 구현. Redis 오류 시에는 메모리에 저장된 세션으로 동작
+type store interface {
+	SetCache(key string, value interface{}, exp time.Duration)
+	GetCache(key string) *redis.StringCmd
+	CacheKeys(pattern string) ([]string, error)
+	DelCache(keys ...string) error
+	IncrCache(key string, exp time.Duration) (int64, error)
+	CacheTTL(key string) (time.Duration, error)
+}
+
+// entry 로그인 세션. refresh token 원문은 저장하지 않고 hash만 보관
+type entry struct {
+	UserId    int       `json:"user_id"`
+	Hash      string    `json:"hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// failure 사용자별 로그인 실패 횟수. 마지막 실패 후 lockout 시간 동안 유지
+type failure struct {
+	Count int       `json:"count"`
+	Until time.Time `json:"until"`
+}
+
+// Token 발급된 세션. Id는 access token의 jti로 사용
+type Token struct {
+	Id        string
+	UserId    int
+	Refresh   string
+	ExpiresAt time.Time
+}
+
+/*
+Manager 로그인 세션 관리
+  - refresh token 발급, 재발급(rotation). 재발급된 token의 이전 값을 다시 사용하면 세션 폐기
+  - 로그아웃, 사용자 전체 세션 폐기. access token은 세션이 남아있는 동안만 유효
+  - 로그인 실패 횟수 기록. 허용 횟수를 넘기면 lockout 시간 동안 로그인 거부
+*/
+type Manager struct {
+	stg         store
+	refreshTTL  time.Duration
+	maxFailures int
+	lockout     time.Duration
+	mu          sync.Mutex
+	mem         map[string]entry
+	fails       map[string]failure
+	pruned      time.Time // 마지막 메모리 정리 시각
+	lg          zerolog.Logger
+}
+
+type Option func(*Manager)
+
+func WithRefreshTTL(d time.Duration) Option {
+	return func(m *Manager) {
+		if d > 0 {
+			m.refreshTTL = d
+		}
+	}
+}
+
+// WithLockout n회 연속 실패 시 d 동안 로그인 거부
+func WithLockout(n int, d time.Duration) Option {
+	return func(m *Manager) {
+		if n > 0 && d > 0 {
+			m.maxFailures = n
+			m.lockout = d
+		}
+	}
+}
+
+func NewManager(stg store, opts ...Option) *Manager {
+	m := &Manager{
+		stg:         stg,
+		refreshTTL:  14 * 24 * time.Hour,
+		maxFailures: 5,
+		lockout:     15 * time.Minute,
+		mem:         make(map[string]entry),
+		fails:       make(map[string]failure),
+		lg:          zerolog.New(os.Stdout).With().Str("Module", "Session").Timestamp().Logger(),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Create 로그인 성공 시 새 세션 발급
+func (m *Manager) Create(userId int) (*Token, error) {
+
+	id, err := random(16)
+	if err != nil {
+		return nil, fmt.Errorf("세션 id 생성 시 오류 발생. %w", err)
+	}
+	return m.issue(userId, id)
+}
+
+// Refresh refresh token 확인 후 같은 세션으로 refresh token 재발급
+func (m *Manager) Refresh(refresh string) (*Token, error) {
+
+	userId, id, secret, ok := parse(refresh)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	k := key(userId, id)
+	e, ok := m.load(k)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	if subtle.ConstantTimeCompare([]byte(e.Hash), []byte(hash(secret))) != 1 {
+		// memo. 이미 재발급된 token 재사용. 탈취 가능성이 있어 세션 폐기
+		m.lg.Warn().Int("user", userId).Msg("재발급된 refresh token 재사용. 세션 폐기")
+		m.Revoke(userId, id)
+		return nil, ErrInvalidToken
+	}
+	return m.issue(userId, id)
+}
+
+// Active access token의 세션이 폐기되지 않았는지 확인
+func (m *Manager) Active(userId int, id string) bool {
+	_, ok := m.load(key(userId, id))
+	return ok
+}
+
+// Revoke 로그아웃. 해당 세션의 refresh token, access token 모두 무효화
+func (m *Manager) Revoke(userId int, id string) {
+	m.delete(key(userId, id))
+}
+
+// RevokeUser 비밀번호 변경, 계정 비활성화 시 사용자의 모든 세션 폐기
+func (m *Manager) RevokeUser(userId int) {
+
+	prefix := fmt.Sprintf("%s%d:", keyPrefix, userId)
+	keys := make([]string, 0)
+
+	m.mu.Lock()
+	for k := range m.mem {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	m.mu.Unlock()
+
+	if m.stg != nil {
+		rkeys, err := m.stg.CacheKeys(prefix + "*")
+		if err != nil {
+			m.lg.Error().Err(err).Int("user", userId).Msg("Redis 세션 키 조회 실패")
+		}
+		keys = append(keys, rkeys...)
+	}
+	m.delete(keys...)
+}
+
+// Locked lockout 중이면 해제 시각 반환
+func (m *Manager) Locked(username string) (time.Time, bool) {
+	f, ok := m.failure(username)
+	if !ok || f.Count < m.maxFailures {
+		return time.Time{}, false
+	}
+	return f.Until, true
+}
+
+/*
+Failed 로그인 실패 기록. 허용 횟수를 넘겨 lockout 되면 true
+  - memo. 동시 로그인 실패가 누락되지 않도록 Redis는 INCR, 메모리는 m.mu 보유 상태에서 증가
+  - Redis 오류 시 메모리 횟수 사용
+*/
+func (m *Manager) Failed(username string) bool {
+
+	k := failPrefix + username
+	now := time.Now()
+
+	m.mu.Lock()
+	m.prune()
+	f := m.fails[k]
+	if now.After(f.Until) {
+		f = failure{}
+	}
+	f.Count++
+	f.Until = now.Add(m.lockout)
+	m.fails[k] = f
+	m.mu.Unlock()
+
+	if m.stg != nil {
+		n, err := m.stg.IncrCache(k, m.lockout)
+		if err != nil {
+			m.lg.Warn().Err(err).Str("key", k).Msg("Redis 로그인 실패 기록 실패. 메모리 값 사용")
+		} else if int(n) > f.Count {
+			// memo. 다른 인스턴스의 실패 횟수까지 메모리에 반영하여 Redis 장애 시에도 유지
+			f.Count = int(n)
+			m.mu.Lock()
+			if cur := m.fails[k]; cur.Count < f.Count {
+				m.fails[k] = f
+			}
+			m.mu.Unlock()
+		}
+	}
+
+	locked := f.Count >= m.maxFailures
+	if locked {
+		m.lg.Warn().Str("username", username).Int("count", f.Count).Msg("로그인 실패 횟수 초과. lockout")
+	}
+	return locked
+}
+
+// Reset 로그인 성공 시 실패 횟수 초기화
+func (m *Manager) Reset(username string) {
+
+	k := failPrefix + username
+	m.mu.Lock()
+	delete(m.fails, k)
+	m.mu.Unlock()
+
+	if m.stg != nil {
+		if err := m.stg.DelCache(k); err != nil {
+			m.lg.Warn().Err(err).Str("key", k).Msg("Redis 로그인 실패 기록 삭제 실패")
+		}
+	}
+}
+
+/*
+failure 로그인 실패 기록 조회. Redis에는 실패 횟수만 저장하고 해제 시각은 남은 만료 시간으로 계산
+  - Redis에 없거나 오류 시 메모리 기록 사용
+*/
+func (m *Manager) failure(username string) (f failure, ok bool) {
+
+	k := failPrefix + username
+	if ok = m.redisFailure(k, &f); !ok {
+		m.mu.Lock()
+		f, ok = m.fails[k]
+		m.mu.Unlock()
+	}
+	if !ok || time.Now().After(f.Until) {
+		return failure{}, false
+	}
+	return f, true
+}
+
+func (m *Manager) redisFailure(k string, f *failure) bool {
+	if m.stg == nil {
+		return false
+	}
+	n, err := m.stg.GetCache(k).Int()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			m.lg.Warn().Err(err).Str("key", k).Msg("Redis 로그인 실패 기록 조회 실패. 메모리 값 사용")
+		}
+		return false
+	}
+	ttl, err := m.stg.CacheTTL(k)
+	if err != nil || ttl <= 0 {
+		return false
+	}
+	*f = failure{Count: n, Until: time.Now().Add(ttl)}
+	return true
+}
+
+func (m *Manager) issue(userId int, id string) (*Token, error) {
+
+	secret, err := random(32)
+	if err != nil {
+		return nil, fmt.Errorf("refresh token 생성 시 오류 발생. %w", err)
+	}
+
+	e := entry{
+		UserId:    userId,
+		Hash:      hash(secret),
+		ExpiresAt: time.Now().Add(m.refreshTTL),
+	}
+	k := key(userId, id)
+
+	m.mu.Lock()
+	m.prune()
+	m.mem[k] = e
+	m.mu.Unlock()
+
+	if m.stg != nil {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return nil, fmt.Errorf("세션 직렬화 시 오류 발생. %w", err)
+		}
+		m.stg.SetCache(k, b, m.refreshTTL)
+	}
+
+	return &Token{
+		Id:        id,
+		UserId:    userId,
+		Refresh:   fmt.Sprintf("%d.%s.%s", userId, id, secret),
+		ExpiresAt: e.ExpiresAt,
+	}, nil
+}
+
+/*
+load 세션 조회
+  - Redis에 없으면(redis.Nil) 폐기된 세션. 다른 인스턴스에서 로그아웃한 경우도 포함
+  - Redis 오류 시에만 메모리 세션 사용
+*/
+func (m *Manager) load(k string) (entry, bool) {
+
+	var e entry
+	if m.stg != nil {
+		b, err := m.stg.GetCache(k).Bytes()
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(b, &e); err != nil {
+				m.lg.Error().Err(err).Str("key", k).Msg("세션 역직렬화 실패")
+				return e, false
+			}
+			return e, time.Now().Before(e.ExpiresAt)
+		case errors.Is(err, redis.Nil):
+			return e, false
+		default:
+			m.lg.Warn().Err(err).Str("key", k).Msg("Redis 세션 조회 실패. 메모리 세션 사용")
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.mem[k]
+	if ok && time.Now().After(e.ExpiresAt) {
+		delete(m.mem, k)
+		return e, false
+	}
+	return e, ok
+}
+
+/*
+prune 만료된 메모리 세션, 로그인 실패 기록 삭제. m.mu 보유 상태에서 호출
+  - memo. 메모리 값은 Redis 장애 대비로 항상 함께 저장하고, 미등록 사용자 이름의 로그인 실패도 기록하므로 쓰기 시 주기적으로 정리
+*/
+func (m *Manager) prune() {
+	now := time.Now()
+	if now.Sub(m.pruned) < pruneInterval {
+		return
+	}
+	m.pruned = now
+
+	for k, e := range m.mem {
+		if now.After(e.ExpiresAt) {
+			delete(m.mem, k)
+		}
+	}
+	for k, f := range m.fails {
+		if now.After(f.Until) {
+			delete(m.fails, k)
+		}
+	}
+}
+
+func (m *Manager) delete(keys ...string) {
+
+	if len(keys) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	for _, k := range keys {
+		delete(m.mem, k)
+	}
+	m.mu.Unlock()
+
+	if m.stg != nil {
+		if err := m.stg.DelCache(keys...); err != nil {
+			m.lg.Error().Err(err).Strs("keys", keys).Msg("Redis 세션 삭제 실패")
+		}
+	}
+}
+
+func key(userId int, id string) string {
+	return fmt.Sprintf("%s%d:%s", keyPrefix, userId, id)
+}
+
+// parse refresh token. "{user id}.{세션 id}.{secret}"
+func parse(refresh string) (userId int, id string, secret string, ok bool) {
+	parts := strings.Split(refresh, ".")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return 0, "", "", false
+	}
+	userId, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", "", false
+	}
+	return userId, parts[1], parts[2], true
+}
+
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func random(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
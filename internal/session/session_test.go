@@ -0,0 +1,243 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+type storeMock struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	exps map[string]time.Time
+	err  error
+}
+
+func (s *storeMock) SetCache(key string, value interface{}, exp time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+	s.data[key] = value.([]byte)
+}
+
+func (s *storeMock) GetCache(key string) *redis.StringCmd {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return redis.NewStringResult("", s.err)
+	}
+	v, ok := s.data[key]
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	return redis.NewStringResult(string(v), nil)
+}
+
+func (s *storeMock) CacheKeys(pattern string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	keys := make([]string, 0)
+	for k := range s.data {
+		if strings.HasPrefix(k, strings.TrimSuffix(pattern, "*")) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (s *storeMock) DelCache(keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	for _, k := range keys {
+		delete(s.data, k)
+	}
+	return nil
+}
+
+func (s *storeMock) IncrCache(key string, exp time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	n, _ := strconv.ParseInt(string(s.data[key]), 10, 64)
+	n++
+	s.data[key] = []byte(strconv.FormatInt(n, 10))
+	if s.exps == nil {
+		s.exps = make(map[string]time.Time)
+	}
+	s.exps[key] = time.Now().Add(exp)
+	return n, nil
+}
+
+func (s *storeMock) CacheTTL(key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	if _, ok := s.data[key]; !ok {
+		return -2, nil
+	}
+	exp, ok := s.exps[key]
+	if !ok {
+		return -1, nil
+	}
+	return time.Until(exp), nil
+}
+
+func TestSession(t *testing.T) {
+
+	stg := &storeMock{data: make(map[string][]byte)}
+	m := NewManager(stg)
+
+	t.Run("Refresh Rotation", func(t *testing.T) {
+		tk, err := m.Create(1)
+		assert.NoError(t, err)
+		assert.True(t, m.Active(1, tk.Id))
+
+		next, err := m.Refresh(tk.Refresh)
+		assert.NoError(t, err)
+		assert.Equal(t, tk.Id, next.Id, "재발급 시 세션 유지")
+		assert.NotEqual(t, tk.Refresh, next.Refresh)
+
+		_, err = m.Refresh(tk.Refresh)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		assert.False(t, m.Active(1, tk.Id), "이전 token 재사용 시 세션 폐기")
+
+		_, err = m.Refresh(next.Refresh)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Invalid Token", func(t *testing.T) {
+		for _, refresh := range []string{"", "abc", "x.y.z", "1..z"} {
+			_, err := m.Refresh(refresh)
+			assert.ErrorIs(t, err, ErrInvalidToken, refresh)
+		}
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		a, _ := m.Create(2)
+		b, _ := m.Create(2)
+		other, _ := m.Create(3)
+
+		m.Revoke(2, a.Id)
+		assert.False(t, m.Active(2, a.Id))
+		assert.True(t, m.Active(2, b.Id))
+
+		m.RevokeUser(2)
+		assert.False(t, m.Active(2, b.Id))
+		assert.True(t, m.Active(3, other.Id))
+	})
+
+	t.Run("Redis Error", func(t *testing.T) {
+		tk, _ := m.Create(4)
+		stg.err = errors.New("connection refused")
+		defer func() { stg.err = nil }()
+
+		assert.True(t, m.Active(4, tk.Id), "Redis 오류 시 메모리 세션 사용")
+		m.Revoke(4, tk.Id)
+		assert.False(t, m.Active(4, tk.Id))
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		m := NewManager(nil, WithRefreshTTL(time.Millisecond))
+		tk, _ := m.Create(5)
+		time.Sleep(5 * time.Millisecond)
+		assert.False(t, m.Active(5, tk.Id))
+		_, err := m.Refresh(tk.Refresh)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestLockout(t *testing.T) {
+
+	stg := &storeMock{data: make(map[string][]byte)}
+	m := NewManager(stg, WithLockout(3, time.Hour))
+
+	assert.False(t, m.Failed("user"))
+	assert.False(t, m.Failed("user"))
+	_, locked := m.Locked("user")
+	assert.False(t, locked)
+
+	assert.True(t, m.Failed("user"))
+	until, locked := m.Locked("user")
+	assert.True(t, locked)
+	assert.True(t, until.After(time.Now()))
+
+	_, locked = NewManager(stg, WithLockout(3, time.Hour)).Locked("user")
+	assert.True(t, locked, "재기동 후에도 Redis 기록으로 lockout 유지")
+
+	m.Reset("user")
+	_, locked = m.Locked("user")
+	assert.False(t, locked)
+
+	m = NewManager(nil, WithLockout(1, time.Millisecond))
+	m.Failed("user")
+	time.Sleep(5 * time.Millisecond)
+	_, locked = m.Locked("user")
+	assert.False(t, locked, "lockout 시간 경과 후 해제")
+}
+
+func TestFailedConcurrent(t *testing.T) {
+
+	stg := &storeMock{data: make(map[string][]byte)}
+	m := NewManager(stg, WithLockout(100, time.Hour))
+	mem := NewManager(nil, WithLockout(100, time.Hour))
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			m.Failed("user")
+		}()
+		go func() {
+			defer wg.Done()
+			mem.Failed("user")
+		}()
+	}
+	wg.Wait()
+
+	f, _ := m.failure("user")
+	assert.Equal(t, 50, f.Count, "Redis 실패 횟수 누락 없음")
+	f, _ = mem.failure("user")
+	assert.Equal(t, 50, f.Count, "메모리 실패 횟수 누락 없음")
+
+	stg.err = errors.New("redis down")
+	f, ok := m.failure("user")
+	assert.True(t, ok)
+	assert.Equal(t, 50, f.Count, "Redis 오류 시 메모리 기록 사용")
+}
+
+func TestPrune(t *testing.T) {
+
+	m := NewManager(nil, WithRefreshTTL(time.Millisecond), WithLockout(3, time.Millisecond))
+	for i := range 10 {
+		m.Create(i)
+		m.Failed(fmt.Sprintf("unknown%d", i))
+	}
+	assert.Len(t, m.mem, 10)
+	assert.Len(t, m.fails, 10, "정리 주기 이내")
+
+	time.Sleep(5 * time.Millisecond)
+	m.pruned = time.Time{}
+	m.Create(10)
+	assert.Len(t, m.mem, 1, "만료된 세션 정리")
+	assert.Empty(t, m.fails, "만료된 로그인 실패 기록 정리")
+}
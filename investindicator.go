@@ -3,10 +3,15 @@ package investind
 import (
 	"cmp"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"investindicator/internal/audit"
 	"investindicator/internal/cache"
 	"investindicator/internal/model"
 	m "investindicator/internal/model"
+	"investindicator/internal/pricebus"
+	"investindicator/internal/quote"
+	"investindicator/notify"
 	"math"
 	"os"
 	"slices"
@@ -24,10 +29,58 @@ type InvestIndicator struct {
 	td             trader
 	bt             bcTrader
 	ms             messenger
+	ac             alertCache
+	qc             quoter
+	pb             priceBus
+	au             auditor
+	pub            publisher
+	hs             *healthState
 	enrolledEvents []*EnrolledEvent
 	lg             zerolog.Logger
 }
 
+type Option func(*InvestIndicator)
+
+var (
+	ErrEventNotFound = errors.New("미존재 이벤트")
+	ErrEventInactive = errors.New("비활성화 이벤트")
+)
+
+// WithAlertCache 알림 중복 억제 캐시 지정. 미지정 시 storage 기반 기본 window 캐시 사용
+func WithAlertCache(ac alertCache) Option {
+	return func(e *InvestIndicator) {
+		e.ac = ac
+	}
+}
+
+// WithQuoteCache 시세 캐시 지정. API 등 다른 호출처와 같은 캐시를 공유할 때 사용
+func WithQuoteCache(qc quoter) Option {
+	return func(e *InvestIndicator) {
+		e.qc = qc
+	}
+}
+
+// WithPriceBus 실시간 시세 bus 지정. API push 등 다른 구독자와 같은 bus를 공유할 때 사용
+func WithPriceBus(pb priceBus) Option {
+	return func(e *InvestIndicator) {
+		e.pb = pb
+	}
+}
+
+// WithAuditor 자동 실행 이벤트의 상태 변경 이력 기록. 미지정 시 미기록
+func WithAuditor(au auditor) Option {
+	return func(e *InvestIndicator) {
+		e.au = au
+	}
+}
+
+// WithPublisher 알림, 체결, 이벤트 실행 결과 등을 API 구독자에게 전달. 미지정 시 미전달
+func WithPublisher(pub publisher) Option {
+	return func(e *InvestIndicator) {
+		e.pub = pub
+	}
+}
+
 // type InvestIndicatorConfig struct {
 // 	Storage     storage
 // 	RtPoller    rtPoller
@@ -36,7 +89,7 @@ type InvestIndicator struct {
 // 	Channel     chan<- string
 // }
 
-func NewInvestIndicator(stg storage, rt rtPoller, dp dailyPoller, bt bcTrader, ms messenger) *InvestIndicator {
+func NewInvestIndicator(stg storage, rt rtPoller, dp dailyPoller, bt bcTrader, ms messenger, opts ...Option) *InvestIndicator {
 
 	eh := &InvestIndicator{
 		stg: stg,
@@ -44,8 +97,21 @@ func NewInvestIndicator(stg storage, rt rtPoller, dp dailyPoller, bt bcTrader, m
 		dp:  dp,
 		bt:  bt,
 		ms:  ms,
+		hs:  newHealthState(),
 		lg:  zerolog.New(os.Stdout).With().Str("Module", "EventHandler").Timestamp().Logger(),
 	}
+	for _, opt := range opts {
+		opt(eh)
+	}
+	if eh.ac == nil {
+		eh.ac = cache.NewAlertCache(stg)
+	}
+	if eh.qc == nil {
+		eh.qc = quote.NewCache(rtFetcher{rt})
+	}
+	if eh.pb == nil {
+		eh.pb = pricebus.NewBus()
+	}
 	eh.registerEvents()
 	eh.redisCurrencyIdInit()
 
@@ -75,6 +141,11 @@ func (e InvestIndicator) Events() []*EnrolledEvent {
 	return e.enrolledEvents
 }
 
+// SuppressedAlerts 중복 억제로 현재 전송되지 않는 알림 목록
+func (e InvestIndicator) SuppressedAlerts() []cache.SuppressedAlert {
+	return e.ac.Suppressed()
+}
+
 func (e InvestIndicator) SetEventStatus(id uint, active bool) error {
 	e.lg.Info().Uint("id", id).Bool("active", active).Msg("Changing event status")
 
@@ -88,7 +159,7 @@ func (e InvestIndicator) SetEventStatus(id uint, active bool) error {
 		}
 	}
 	if !done {
-		return fmt.Errorf("미존재 Id : %d", id)
+		return fmt.Errorf("%w. Id : %d", ErrEventNotFound, id)
 	}
 
 	e.lg.Info().Uint("id", id).Bool("active", active).Msg("Event status changed successfully")
@@ -101,16 +172,16 @@ func (e InvestIndicator) LaunchEvent(id uint) error {
 	for _, ev := range e.enrolledEvents {
 		if ev.Id == id {
 			if ev.IsActive {
-				ev.Event(Manual)
+				e.launch(ev, Manual)
 				e.lg.Info().Uint("id", id).Msg("Event launched successfully")
 				return nil
 			} else {
-				return fmt.Errorf("비활성화 이벤트 Id: %d", id)
+				return fmt.Errorf("%w. Id: %d", ErrEventInactive, id)
 			}
 		}
 	}
 
-	return nil
+	return fmt.Errorf("%w. Id : %d", ErrEventNotFound, id)
 }
 
 /**********************************************************************************************************************
@@ -156,56 +227,77 @@ func (e InvestIndicator) InvestAvailableAmount(id int) (float64, error) {
 	return availableAmount, nil
 }
 
+// RecordInvest 투자 이력 기록. 보유 현황 변동 계산이 실패하면 이력도 미저장
 func (e InvestIndicator) RecordInvest(invest m.Invest) error {
+	return e.RecordInvests([]m.Invest{invest})
+}
 
-	err := e.stg.SaveInvest(invest.FundID, invest.AssetID, invest.Price, invest.Count)
-	if err != nil {
-		return fmt.Errorf("SaveInvest 오류 발생. %w", err)
+// RecordInvests 투자 이력 일괄 기록. 이력과 보유 현황 갱신을 한 transaction으로 처리하여 하나라도 실패하면 전체 미반영
+func (e InvestIndicator) RecordInvests(invests []m.Invest) error {
+
+	changes := make([]m.SummaryChange, 0, 2*len(invests))
+	for _, invest := range invests {
+		c, err := e.summaryChanges(invest)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, c...)
 	}
 
-	err = e.stg.UpdateInvestSummary(invest.FundID, invest.AssetID, invest.Count, invest.Price)
+	err := e.stg.RecordInvests(invests, changes)
 	if err != nil {
-		return fmt.Errorf("UpdateInvestSummaryCount 오류 발생. %w", err)
+		return fmt.Errorf("RecordInvests 오류 발생. %w", err)
 	}
+	return nil
+}
+
+// summaryChanges 투자 이력에 따른 보유 현황 변동. 해당 자산과 함께 원화/달러 잔고 변동 포함
+func (e InvestIndicator) summaryChanges(invest m.Invest) ([]m.SummaryChange, error) {
+
+	changes := []m.SummaryChange{{FundID: invest.FundID, AssetID: invest.AssetID, Change: invest.Count, Price: invest.Price}}
 
 	// 현금/달러 갱신
 	asset, err := e.stg.RetrieveAsset(invest.AssetID)
 	if err != nil {
-		return fmt.Errorf("RetrieveAsset 오류 발생. %w", err)
+		return nil, fmt.Errorf("RetrieveAsset 오류 발생. %w", err)
 	}
 
 	krwId, err := e.stg.GetCache(model.KRW.String()).Uint64() // todo. 서버 기동 시, 캐시 저장 여부 확인.
 	if err != nil {
-		return fmt.Errorf("GetCache KRW id 오류 발생. %w", err)
+		return nil, fmt.Errorf("GetCache KRW id 오류 발생. %w", err)
 	}
 
 	usdId, err := e.stg.GetCache(model.USD.String()).Uint64() // todo. 서버 기동 시, 캐시 저장 여부 확인.
 	if err != nil {
-		return fmt.Errorf("GetCache USD id 오류 발생. %w", err)
+		return nil, fmt.Errorf("GetCache USD id 오류 발생. %w", err)
 	}
 
 	// 원화 잔고 업데이트
 	if invest.AssetID == uint(krwId) {
 		// pass
 	} else if asset.Currency == model.USD.String() && invest.AssetID != uint(usdId) { // 달러 자산
-		err = e.stg.UpdateInvestSummary(invest.FundID, uint(usdId), -1*invest.Price*invest.Count, e.dp.ExchageRate())
+		changes = append(changes, m.SummaryChange{FundID: invest.FundID, AssetID: uint(usdId), Change: -1 * invest.Price * invest.Count, Price: e.dp.ExchageRate()})
 	} else {
-		err = e.stg.UpdateInvestSummary(invest.FundID, uint(krwId), -1*invest.Price*invest.Count, 1) // 원화 자산 및 달러 충전
+		changes = append(changes, m.SummaryChange{FundID: invest.FundID, AssetID: uint(krwId), Change: -1 * invest.Price * invest.Count, Price: 1}) // 원화 자산 및 달러 충전
 	}
 
-	return nil
+	return changes, nil
 }
 
 /**********************************************************************************************************************
 ********************************************* Always On Events *******************************************************
 **********************************************************************************************************************/
 
-// Coin Streaming과 주식 Streaming의 공통 작업 모듈화. job으로 각각의 streaming 및 오류 처리 로직 입력 받음.
-func loopWithInterval(job func()) {
+// Coin Streaming과 주식 Streaming의 공통 작업 모듈화. job으로 각각의 streaming 및 오류 처리 로직 입력 받음. name은 재연결 metric의 stream label
+// memo. job 실행 중에는 연결 상태로 보고. 연결 직후 실패해도 job이 반환되면 끊김으로 갱신
+func (e InvestIndicator) loopWithInterval(name string, job func() error) {
 	backoff := time.Minute
 	lastErrTime := time.Now()
 	for {
-		job()
+		e.hs.streamUp(name)
+		err := job()
+		e.hs.streamDown(name, err)
+		streamReconnects.WithLabelValues(name).Inc()
 		time.Sleep(backoff)
 		backoff *= 2
 		if time.Now().Sub(lastErrTime) > time.Hour*24 {
@@ -218,55 +310,91 @@ func loopWithInterval(job func()) {
 func (e InvestIndicator) runRecordMyOrdersEvent() {
 
 	oc := make(chan m.MyOrder) // order channel
-	go loopWithInterval(func() {
+	go e.loopWithInterval("StreamCoinOrders", func() error {
 		err := e.rt.StreamCoinOrders(oc)
 		e.lg.Error().Err(err).Msg("StreamCoinOrders 오류")
-		e.ms.SendMessage(0, fmt.Errorf("StreamCoinOrders 오류 발생. 오류 내역: %w", err).Error())
+		e.ms.SendMessage(notify.Errors, fmt.Errorf("StreamCoinOrders 오류 발생. 오류 내역: %w", err).Error())
+		return err
 	})
 
-	go loopWithInterval(func() {
+	go e.loopWithInterval("StreamStockOrders", func() error {
 		err := e.rt.StreamStockOrders(oc)
 		e.lg.Error().Err(err).Msg("StreamStockOrders 오류")
-		e.ms.SendMessage(0, fmt.Errorf("StreamStockOrders 오류 발생. 오류 내역: %w", err).Error())
+		e.ms.SendMessage(notify.Errors, fmt.Errorf("StreamStockOrders 오류 발생. 오류 내역: %w", err).Error())
+		return err
 	})
 
+	fc := make(chan m.Fill) // fill channel. 자금 선택이 끝난 체결
+	go e.recordFills(fc)
+
 	for {
 		myOrder := <-oc
 
 		assetId := e.stg.RetrieveAssetIdByCode(myOrder.Code)
 		if assetId == 0 { // 미등록된 Asset
-			e.ms.SendMessage(0, fmt.Sprintf("미등록 자산 %s 거래 발생", myOrder.Code))
-			continue
-		}
-		fundId, err := e.chooseFundId(myOrder)
-		if err != nil {
-			e.lg.Error().Err(err).Msg("[runRecordMyOrdersEvent] chooseFundId, 에러 발생")
-			e.ms.SendMessage(0, fmt.Sprintf("[runRecordMyOrdersEvent] chooseFundId, 에러 발생. %s", err))
+			e.ms.SendMessage(notify.Alerts, fmt.Sprintf("미등록 자산 %s 거래 발생", myOrder.Code))
 			continue
 		}
+		go e.chooseFillFund(assetId, myOrder, fc) // 자금 선택 응답을 기다리는 동안 다음 체결도 처리
+	}
+}
 
-		if fundId == 0 { // 미대상 거래
-			continue
-		}
+// chooseFillFund 체결 건의 자금 선택. 선택된 체결은 fc로 전달하여 기록
+func (e InvestIndicator) chooseFillFund(assetId uint, myOrder m.MyOrder, fc chan<- m.Fill) {
+	fundId, err := e.chooseFundId(myOrder)
+	if err != nil {
+		e.lg.Error().Err(err).Msg("[runRecordMyOrdersEvent] chooseFundId, 에러 발생")
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("[runRecordMyOrdersEvent] chooseFundId, 에러 발생. %s", err))
+		return
+	}
 
-		err = e.RecordInvest(m.Invest{
-			FundID:  fundId,
-			AssetID: assetId,
-			Price:   myOrder.Price,
-			Count:   myOrder.Count,
-		})
+	if fundId == 0 { // 미대상 거래
+		return
+	}
+	fc <- m.Fill{FundID: fundId, AssetID: assetId, Code: myOrder.Code, Price: myOrder.Price, Count: myOrder.Count}
+}
+
+// recordFills 자금이 선택된 체결을 순서대로 투자 이력에 기록. memo. 자금 선택은 동시에 진행하고 기록은 한 goroutine에서만 수행
+func (e InvestIndicator) recordFills(fc <-chan m.Fill) {
+	for fill := range fc {
+		invest := m.Invest{
+			FundID:  fill.FundID,
+			AssetID: fill.AssetID,
+			Price:   fill.Price,
+			Count:   fill.Count,
+		}
+		err := e.RecordInvest(invest)
 		if err != nil {
 			e.lg.Error().Err(err).Msg("[runRecordMyOrdersEvent] RecordInvest, 에러 발생")
-			e.ms.SendMessage(0, fmt.Sprintf("[runRecordMyOrdersEvent] RecordInvest, 에러 발생. %s", err))
+			e.ms.SendMessage(notify.Errors, fmt.Sprintf("[runRecordMyOrdersEvent] RecordInvest, 에러 발생. %s", err))
 			continue
 		}
+		e.audit(audit.System("record_my_orders"), audit.Entry{Action: m.AuditCreate, Entity: "invest", EntityID: fill.FundID, After: invest})
+		e.publish(m.TopicFill, fill.FundID, fill)
+	}
+}
+
+// publish API 구독자에게 전달
+func (e InvestIndicator) publish(topic m.Topic, fundId uint, data any) {
+	if e.pub != nil {
+		e.pub.Publish(topic, fundId, data)
+	}
+}
 
+// audit 자동 실행 이벤트의 상태 변경 이력 기록
+func (e InvestIndicator) audit(actor m.Actor, entries ...audit.Entry) {
+	if e.au != nil {
+		e.au.Record(actor, entries...)
 	}
 }
 
 func (e InvestIndicator) chooseFundId(order m.MyOrder) (uint, error) {
 	prompt := fmt.Sprintf("하기 거래에 대한 자금 id를 선택하세요.\n Code: %s\n Price: %.3f\n Count : %.3f", order.Code, order.Price, order.Count)
-	ans, err := e.ms.SendButtonsAndGetResult(0, prompt, "1", "2", "3", "미대상 거래")
+	ans, err := e.ms.Ask(notify.Alerts, notify.Question{
+		Prompt:  prompt,
+		Options: []string{"1", "2", "3", "미대상 거래"},
+		Default: "미대상 거래", // 응답이 없으면 기록하지 않음. 필요 시 /invest로 직접 기록
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -312,13 +440,13 @@ func (e InvestIndicator) runAssetEvent() {
 	e.updateAsset(priceMap, &ivsmLi)
 
 	// 현재 시장 단계 이하로 변동 자산을 가지고 있는지 확인. (알림 전송)
-	// msg, err := e.genPortfolioMsg(ivsmLi, priceMap) // memo. genPortfolioMsg 일시 중단. todo. 안전/변동 2분법적인 구분 대신, 자산 종류별 포트폴리오 메시지로 전환 예정.
+	// msg, err := e.genPortfolioMsg(ivsmLi, priceMap) // memo. genPortfolioMsg 중단. 자산 종류별 비중은 포트폴리오 요약 이벤트(digest.go)로 전환
 	// if err != nil {
 	// 	e.lg.Error().Err(err).Msg("[AssetEvent] portfolioMsg시, 에러 발생")
-	// 	e.ms.SendMessage(0, fmt.Sprintf("[AssetEvent] portfolioMsg시, 에러 발생. %s", err))
+	// 	e.ms.SendMessage(notify.Errors, fmt.Sprintf("[AssetEvent] portfolioMsg시, 에러 발생. %s", err))
 	// }
 	// if msg != "" {
-	// 	e.ms.SendMessage(0, msg)
+	// 	e.ms.SendMessage(notify.Alerts, msg)
 	// }
 
 	e.lg.Info().Msg("AssetEvent completed")
@@ -331,23 +459,29 @@ func (e InvestIndicator) runCoinEvent() {
 	assetList, err := e.stg.RetrieveAssetList()
 	if err != nil {
 		e.lg.Error().Err(err).Msg("[CoinEvent] RetrieveAssetList 시, 에러 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("[CoinEvent] RetrieveAssetList 시, 에러 발생. %s", err))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("[CoinEvent] RetrieveAssetList 시, 에러 발생. %s", err))
 		return
 	}
-	priceMap := make(map[uint]float64)
-
-	// 등록 자산 매수/매도 기준 충족 시, 채널로 메시지 전달
+	coins := make([]m.Asset, 0)
 	for _, a := range assetList {
 		if a.Category == m.DomesticCoin { // 코인에 대해서만 수행
-			msg, err := e.buySellMsg(a.ID, priceMap)
-			if err != nil {
-				e.lg.Error().Err(err).Msg("[CoinEvent] buySellMsg시, 에러 발생")
-				e.ms.SendMessage(0, fmt.Sprintf("[CoinEvent] buySellMsg시, 에러 발생. %s", err))
-				return
-			}
-			if msg != "" {
-				e.ms.SendMessage(0, msg)
-			}
+			coins = append(coins, a)
+		}
+	}
+
+	pm, summary := e.fetchPrices(coins)
+	if summary.hasFailure() {
+		e.ms.SendMessage(notify.Errors, "[CoinEvent] "+summary.String())
+	}
+
+	// 등록 자산 매수/매도 기준 충족 시, 채널로 메시지 전달
+	for i := range coins {
+		pp, ok := pm[coins[i].ID]
+		if !ok {
+			continue
+		}
+		if msg := e.buySellMsg(&coins[i], pp); msg != "" {
+			e.ms.SendMessage(notify.Alerts, msg)
 		}
 	}
 	e.lg.Info().Msg("CoinEvent completed")
@@ -360,14 +494,14 @@ func (e InvestIndicator) runIndexEvent() {
 	fgi, err := e.dp.FearGreedIndex()
 	if err != nil {
 		e.lg.Error().Err(err).Msg("공포 탐욕 지수 조회 시 오류 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("공포 탐욕 지수 조회 시 오류 발생. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("공포 탐욕 지수 조회 시 오류 발생. %s", err.Error()))
 		return
 	}
 	// 2. Nasdaq 지수 조회
 	nasdaq, err := e.dp.Nasdaq()
 	if err != nil {
 		e.lg.Error().Err(err).Msg("Nasdaq Index 조회 시 오류 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("Nasdaq Index 조회 시 오류 발생. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("Nasdaq Index 조회 시 오류 발생. %s", err.Error()))
 		return
 	}
 
@@ -375,7 +509,7 @@ func (e InvestIndicator) runIndexEvent() {
 	sp500, err := e.dp.Sp500()
 	if err != nil {
 		e.lg.Error().Err(err).Msg("S&P 500 Index 조회 시 오류 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("S&P 500 Index 조회 시 오류 발생. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("S&P 500 Index 조회 시 오류 발생. %s", err.Error()))
 		return
 	}
 
@@ -383,7 +517,7 @@ func (e InvestIndicator) runIndexEvent() {
 	err = e.stg.SaveDailyMarketIndicator(fgi, nasdaq, sp500)
 	if err != nil {
 		e.lg.Error().Err(err).Msg("Nasdaq Index 저장 시 오류 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("Nasdaq Index 저장 시 오류 발생. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("Nasdaq Index 저장 시 오류 발생. %s", err.Error()))
 	}
 
 	// 어제꺼 조회
@@ -397,9 +531,9 @@ func (e InvestIndicator) runIndexEvent() {
 	di, _, err := e.stg.RetrieveMarketIndicator(former)
 	if err != nil {
 		e.lg.Error().Err(err).Msg("RetrieveMarketIndicator 시 오류 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("금일 공포 탐욕 지수 : %d\n금일 Nasdaq : %.2f", fgi, nasdaq))
+		e.ms.SendMessage(notify.Reports, fmt.Sprintf("금일 공포 탐욕 지수 : %d\n금일 Nasdaq : %.2f", fgi, nasdaq))
 	} else {
-		e.ms.SendMessage(0, fmt.Sprintf("금일 공포 탐욕 지수 : %d (전일 : %d)\n금일 Nasdaq : %.2f\n   (전일 : %.2f)", fgi, di.FearGreedIndex, nasdaq, di.NasDaq))
+		e.ms.SendMessage(notify.Reports, fmt.Sprintf("금일 공포 탐욕 지수 : %d (전일 : %d)\n금일 Nasdaq : %.2f\n   (전일 : %.2f)", fgi, di.FearGreedIndex, nasdaq, di.NasDaq))
 	}
 	e.lg.Info().
 		Uint("fgi", fgi).
@@ -414,13 +548,13 @@ func (e InvestIndicator) runHighYieldSpreadEvent() {
 	date, spread, err := e.dp.HighYieldSpread()
 	if err != nil {
 		e.lg.Error().Err(err).Msg("HighYieldSpread 조회 시 오류 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("HighYieldSpread 조회 시 오류 발생. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("HighYieldSpread 조회 시 오류 발생. %s", err.Error()))
 	}
 
 	hy, err := e.stg.RetrieveLatestHighYieldSpread()
 	if err != nil {
 		e.lg.Error().Err(err).Msg("RetrieveMarketIndicator 시 오류 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("RetrieveMarketIndicator 시 오류 발생. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("RetrieveMarketIndicator 시 오류 발생. %s", err.Error()))
 	}
 	if time.Time(hy.CreatedAt).Format("2006-01-02") == date {
 		e.lg.Info().Str("date", date).Float64("spread", spread).Msg("HighYieldSpreadEvent Existing")
@@ -430,7 +564,7 @@ func (e InvestIndicator) runHighYieldSpreadEvent() {
 	parsedDate, err := time.Parse("2006-01-02", date)
 	if err != nil {
 		e.lg.Error().Err(err).Msg("Date parsing failed")
-		e.ms.SendMessage(0, fmt.Sprintf("Date parsing failed. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("Date parsing failed. %s", err.Error()))
 		return
 	}
 
@@ -440,7 +574,7 @@ func (e InvestIndicator) runHighYieldSpreadEvent() {
 	})
 	if err != nil {
 		e.lg.Error().Err(err).Msg("SaveHighYieldSpread 시 오류 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("SaveHighYieldSpread 시 오류 발생. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("SaveHighYieldSpread 시 오류 발생. %s", err.Error()))
 	}
 
 	e.lg.Info().Str("date", date).Float64("spread", spread).Msg("HighYieldSpreadEvent completed")
@@ -453,7 +587,7 @@ func (e InvestIndicator) runEmaUpdateEvent() {
 	assetList, err := e.stg.RetrieveAssetList()
 	if err != nil {
 		e.lg.Error().Err(err).Msg("[EmaUpdateEvent] RetrieveAssetList 시, 에러 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("[EmaUpdateEvent] RetrieveAssetList 시, 에러 발생. %s", err))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("[EmaUpdateEvent] RetrieveAssetList 시, 에러 발생. %s", err))
 		return
 	}
 
@@ -461,7 +595,7 @@ func (e InvestIndicator) runEmaUpdateEvent() {
 		asset, err := e.stg.RetrieveAsset(a.ID)
 		if err != nil {
 			e.lg.Error().Err(err).Msg("[EmaUpdateEvent] RetrieveAsset 시, 에러 발생")
-			e.ms.SendMessage(0, fmt.Sprintf("[EmaUpdateEvent] RetrieveAsset 시, 에러 발생. %s", err))
+			e.ms.SendMessage(notify.Errors, fmt.Sprintf("[EmaUpdateEvent] RetrieveAsset 시, 에러 발생. %s", err))
 			return
 		}
 		// EMA 갱신 제외
@@ -469,17 +603,17 @@ func (e InvestIndicator) runEmaUpdateEvent() {
 			continue
 		}
 
-		cp, err := e.dp.ClosingPrice(asset.Category, asset.Code)
+		cp, err := e.dp.ClosingPrice(asset.Category, asset.Code, asset.ProviderChain()...)
 		if err != nil {
 			e.lg.Error().Err(err).Msg("[EmaUpdateEvent] ClosingPrice 시, 에러 발생")
-			e.ms.SendMessage(0, fmt.Sprintf("[EmaUpdateEvent] ClosingPrice 시, 에러 발생. %s", err))
+			e.ms.SendMessage(notify.Errors, fmt.Sprintf("[EmaUpdateEvent] ClosingPrice 시, 에러 발생. %s", err))
 			continue
 		}
 
 		oldEma, err := e.stg.RetreiveLatestEma(asset.ID)
 		if err != nil {
 			e.lg.Error().Err(err).Msg("[EmaUpdateEvent] RetreiveLatestEma 시, 에러 발생")
-			e.ms.SendMessage(0, fmt.Sprintf("[EmaUpdateEvent] RetreiveLatestEma 시, 에러 발생. %s", err))
+			e.ms.SendMessage(notify.Errors, fmt.Sprintf("[EmaUpdateEvent] RetreiveLatestEma 시, 에러 발생. %s", err))
 			continue
 		}
 
@@ -491,25 +625,62 @@ func (e InvestIndicator) runEmaUpdateEvent() {
 		err = e.stg.SaveEmaHist(newEma)
 		if err != nil {
 			e.lg.Error().Err(err).Msg("[EmaUpdateEvent] SaveEmaHist 시, 에러 발생")
-			e.ms.SendMessage(0, fmt.Sprintf("[EmaUpdateEvent] SaveEmaHist 시, 에러 발생. %s", err))
+			e.ms.SendMessage(notify.Errors, fmt.Sprintf("[EmaUpdateEvent] SaveEmaHist 시, 에러 발생. %s", err))
 			continue
 		}
 	}
 	e.lg.Info().Msg("EmaUpdateEvent completed")
 }
 
+// runFundNavEvent 자금별 평가 총액(원화) 기록. 차트용
+func (e InvestIndicator) runFundNavEvent() {
+	e.lg.Info().Msg("Starting FundNavEvent")
+
+	summaries, err := e.stg.RetreiveFundsSummaryOrderByFundId()
+	if err != nil {
+		e.lg.Error().Err(err).Msg("[FundNavEvent] RetreiveFundsSummaryOrderByFundId 시, 에러 발생")
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("[FundNavEvent] RetreiveFundsSummaryOrderByFundId 시, 에러 발생. %s", err))
+		return
+	}
+
+	ex := e.dp.ExchageRate()
+	if ex == 0 {
+		e.ms.SendMessage(notify.Errors, "[FundNavEvent] ExchageRate 시 환율 값 0 반환")
+		return
+	}
+
+	navs := make(map[uint]float64)
+	for _, is := range summaries {
+		if is.Asset.Currency == m.USD.String() {
+			navs[is.FundID] += is.Sum * ex
+		} else {
+			navs[is.FundID] += is.Sum
+		}
+	}
+
+	for fundId, amount := range navs {
+		err = e.stg.SaveFundNav(fundId, amount)
+		if err != nil {
+			e.lg.Error().Err(err).Msg("[FundNavEvent] SaveFundNav 시, 에러 발생")
+			e.ms.SendMessage(notify.Errors, fmt.Sprintf("[FundNavEvent] SaveFundNav 시, 에러 발생. %s", err))
+			return
+		}
+	}
+	e.lg.Info().Int("funds", len(navs)).Msg("FundNavEvent completed")
+}
+
 func (e InvestIndicator) runRealEstateEvent() {
 	e.lg.Info().Msg("Starting RealEstateEvent")
 
 	rtn, err := e.rt.RealEstateStatus()
 	if err != nil {
 		e.lg.Error().Err(err).Msg("[RealEstateEvent] 크롤링 시 오류 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("[RealEstateEvent] 크롤링 시 오류 발생. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("[RealEstateEvent] 크롤링 시 오류 발생. %s", err.Error()))
 		return
 	}
 
 	if rtn != "예정지구 지정" {
-		e.ms.SendMessage(0, fmt.Sprintf("연신내 재개발 변동 사항 존재. 예정지구 지정 => %s", rtn))
+		e.ms.SendMessage(notify.Alerts, fmt.Sprintf("연신내 재개발 변동 사항 존재. 예정지구 지정 => %s", rtn))
 	} else {
 		e.lg.Info().Str("status", rtn).Msg("연신내 변동 사항 없음. 현재 단계")
 	}
@@ -522,26 +693,26 @@ func (e InvestIndicator) runFindNewSP500Event() {
 	last, err := e.stg.RetrieveLatestSP500Entry()
 	if err != nil {
 		e.lg.Error().Err(err).Msg("SP500 조회 시 오류 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("SP500 조회 시 오류 발생. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("SP500 조회 시 오류 발생. %s", err.Error()))
 	}
 
 	entries, err := e.dp.RecentSP500Entries(last.Date_added.Format("2006-01-02"))
 	if err != nil {
 		e.lg.Error().Err(err).Msg("SP500 조회 시 오류 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("SP500 조회 시 오류 발생. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("SP500 조회 시 오류 발생. %s", err.Error()))
 	}
 
 	for _, entry := range entries {
 		e.lg.Info().Str("symbol", entry.Symbol).Str("security", entry.Security).Msg("New SP500 Entry")
-		e.ms.SendMessage(0, "New SP500 Entry")
+		e.ms.SendMessage(notify.Reports, "New SP500 Entry")
 		jsonBytes, _ := json.MarshalIndent(entry, "", "  ")
-		e.ms.SendMessage(0, string(jsonBytes))
+		e.ms.SendMessage(notify.Reports, string(jsonBytes))
 
 		// Save the new entry to database
 		err := e.stg.SaveSP500Entry(&entry)
 		if err != nil {
 			e.lg.Error().Err(err).Msg("SaveSP500Entry 시 오류 발생")
-			e.ms.SendMessage(0, fmt.Sprintf("SaveSP500Entry 시 오류 발생. %s", err.Error()))
+			e.ms.SendMessage(notify.Errors, fmt.Sprintf("SaveSP500Entry 시 오류 발생. %s", err.Error()))
 		}
 	}
 
@@ -556,7 +727,7 @@ func (e InvestIndicator) runBuySP500Event(isManual WayOfLaunch) {
 	av, err := e.InvestAvailableAmount(1) // availableAmount
 	if err != nil {
 		e.lg.Error().Err(err).Msg("자금 1 투자 가능 금액 조회 시 오류 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("자금 1 투자 가능 금액 조회 시 오류 발생. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("자금 1 투자 가능 금액 조회 시 오류 발생. %s", err.Error()))
 	}
 
 	mta := 300000.0 // monthTargetAmount // todo. config 전환
@@ -573,7 +744,7 @@ func (e InvestIndicator) runBuySP500Event(isManual WayOfLaunch) {
 	p, err := e.rt.PresentPrice(category, code)
 	if err != nil {
 		e.lg.Error().Err(err).Msg("SPLG 현재 조회 시 오류 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("SPLG 현재 조회 시 오류 발생. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("SPLG 현재 조회 시 오류 발생. %s", err.Error()))
 	}
 
 	p *= e.dp.ExchageRate()
@@ -584,10 +755,10 @@ func (e InvestIndicator) runBuySP500Event(isManual WayOfLaunch) {
 	err = e.td.Buy(category, code, qty)
 	if err != nil {
 		e.lg.Error().Err(err).Msg("SPLG 구매 시 오류 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("SPLG 구매 시 오류 발생. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("SPLG 구매 시 오류 발생. %s", err.Error()))
 	}
 
-	e.ms.SendMessage(0, fmt.Sprintf("SPLG 구매 완료. 가격: %f, 수량 %d", p, qty))
+	e.ms.SendMessage(notify.Alerts, fmt.Sprintf("SPLG 구매 완료. 가격: %f, 수량 %d", p, qty))
 }
 
 /**********************************************************************************************************************
@@ -604,11 +775,11 @@ func (e InvestIndicator) runAssetRecommendEvent(isManual WayOfLaunch) {
 	os := make([]priority, 0, len(ivsmLi))
 	err := e.loadOrderSlice(&os, pm)
 	if err != nil {
-		e.ms.SendMessage(0, err.Error())
+		e.ms.SendMessage(notify.Errors, err.Error())
 	}
 	// li, err := e.stg.RetrieveTotalAssets()
 	// if err != nil {
-	// 	e.ms.SendMessage(0,fmt.Sprintf("RetrieveTotalAssets, 에러 발생. %s", err.Error()))
+	// 	e.ms.SendMessage(notify.Errors,fmt.Sprintf("RetrieveTotalAssets, 에러 발생. %s", err.Error()))
 	// 	return
 	// }
 	// os := make([]priority, 0, len(li)) // ordered slice
@@ -617,7 +788,7 @@ func (e InvestIndicator) runAssetRecommendEvent(isManual WayOfLaunch) {
 	// 	pp := pm[a.ID]
 	// 	ap, err := e.stg.RetreiveLatestEma(a.ID)
 	// 	if err != nil {
-	// 		e.ms.SendMessage(0,fmt.Sprintf("RetreiveLatestEma, 에러 발생. ID: %d. %s", a.ID, err.Error()))
+	// 		e.ms.SendMessage(notify.Errors,fmt.Sprintf("RetreiveLatestEma, 에러 발생. ID: %d. %s", a.ID, err.Error()))
 	// 		return
 	// 	}
 	// 	hp := a.Top
@@ -639,7 +810,7 @@ func (e InvestIndicator) runAssetRecommendEvent(isManual WayOfLaunch) {
 		sb.WriteString(fmt.Sprintf("AssetId : %d\n  AssetName : %s\n  PresentPrice : %.2f\n  WeighedAveragePrice : %.2f\n  HighestPrice : %.2f\n\n", p.asset.ID, p.asset.Name, p.pp, p.ap, p.hp))
 	}
 
-	e.ms.SendMessage(0, sb.String())
+	e.ms.SendMessage(notify.Alerts, sb.String())
 	e.lg.Info().Msg("AssetRecommendEvent completed")
 }
 
@@ -649,38 +820,41 @@ func (e InvestIndicator) runCoinKimchiPremiumEvent(isManual WayOfLaunch) {
 	assetList, err := e.stg.RetrieveTotalAssets()
 	if err != nil {
 		e.lg.Error().Err(err).Msg("[CoinKimchiPremiumEvent] RetrieveAssetList 시, 에러 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("[CoinKimchiPremiumEvent] RetrieveAssetList 시, 에러 발생. %s", err))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("[CoinKimchiPremiumEvent] RetrieveAssetList 시, 에러 발생. %s", err))
 		return
 	}
 
 	for _, a := range assetList {
 		if a.Category == m.DomesticCoin {
-			kp, err := e.rt.PresentPrice(a.Category, a.Code)
+			kq, err := e.qc.Quote(a.Category, a.Code, 0, a.ProviderChain()...)
 			if err != nil {
 				e.lg.Error().Err(err).Msg("[CoinKimchiPremiumEvent] PresentPrice 시, 에러 발생")
-				e.ms.SendMessage(0, fmt.Sprintf("[CoinKimchiPremiumEvent] PresentPrice 시, 에러 발생. %s", err))
+				e.ms.SendMessage(notify.Errors, fmt.Sprintf("[CoinKimchiPremiumEvent] PresentPrice 시, 에러 발생. %s", err))
 				return
 			}
-			dp, err := e.rt.PresentPrice(m.ForeignCoin, a.Code)
+			dq, err := e.qc.Quote(m.ForeignCoin, a.Code, 0)
 			if err != nil {
 				e.lg.Error().Err(err).Msg("[CoinKimchiPremiumEvent] PresentPrice 시, 에러 발생")
-				e.ms.SendMessage(0, fmt.Sprintf("[CoinKimchiPremiumEvent] PresentPrice 시, 에러 발생. %s", err))
+				e.ms.SendMessage(notify.Errors, fmt.Sprintf("[CoinKimchiPremiumEvent] PresentPrice 시, 에러 발생. %s", err))
 				return
 			}
 
 			ex := e.dp.ExchageRate()
-			cp := dp * ex // converted price
+			cp := dq.Price * ex // converted price
 
-			kPrm := 100 * (kp - cp) / cp // k-premium
+			kPrm := 100 * (kq.Price - cp) / cp // k-premium
+			if err := e.stg.SavePremium(a.ID, kPrm); err != nil {
+				e.lg.Error().Err(err).Msg("[CoinKimchiPremiumEvent] SavePremium 시, 에러 발생")
+			}
 
 			if kPrm >= 10 {
-				e.ms.SendMessage(0, fmt.Sprintf("[매도] %s 김치 프리미엄 10프로 이상. 현재 프리미엄: %.2f", a.Name, kPrm))
+				e.ms.SendMessage(notify.Alerts, fmt.Sprintf("[매도] %s 김치 프리미엄 10프로 이상. 현재 프리미엄: %.2f", a.Name, kPrm))
 			} else if kPrm >= 5 {
-				e.ms.SendMessage(0, fmt.Sprintf("[알림] %s 김치 프리미엄 5프로 이상. 현재 프리미엄: %.2f", a.Name, kPrm))
+				e.ms.SendMessage(notify.Alerts, fmt.Sprintf("[알림] %s 김치 프리미엄 5프로 이상. 현재 프리미엄: %.2f", a.Name, kPrm))
 			} else if kPrm < -2 {
-				e.ms.SendMessage(0, fmt.Sprintf("[매수] %s - 김치 프리미엄 2프로 초과. 현재 프리미엄: %.2f", a.Name, kPrm))
+				e.ms.SendMessage(notify.Alerts, fmt.Sprintf("[매수] %s - 김치 프리미엄 2프로 초과. 현재 프리미엄: %.2f", a.Name, kPrm))
 			} else if isManual {
-				e.ms.SendMessage(0, fmt.Sprintf("[알림] %s 현재 프리미엄: %.2f", a.Name, kPrm))
+				e.ms.SendMessage(notify.Reports, fmt.Sprintf("[알림] %s 현재 프리미엄: %.2f", a.Name, kPrm))
 			}
 
 		}
@@ -696,7 +870,7 @@ func (e InvestIndicator) runGoldKimchiPremium(isManual WayOfLaunch) {
 		assets, err := e.stg.RetrieveTotalAssets()
 		if err != nil {
 			e.lg.Error().Err(err).Msg("[goldKimchiPremium] RetrieveAssetList 시, 에러 발생")
-			e.ms.SendMessage(0, err.Error())
+			e.ms.SendMessage(notify.Errors, err.Error())
 			return
 		}
 
@@ -711,14 +885,14 @@ func (e InvestIndicator) runGoldKimchiPremium(isManual WayOfLaunch) {
 	goldAsset, err := e.stg.RetrieveAsset(goldId)
 	if err != nil {
 		e.lg.Error().Err(err).Msg("[goldKimchiPremium] RetrieveAsset 시, 에러 발생")
-		e.ms.SendMessage(0, err.Error())
+		e.ms.SendMessage(notify.Errors, err.Error())
 	}
 
-	kp, err := e.rt.PresentPrice(goldAsset.Category, goldAsset.Code) // kimchi price
+	kq, err := e.qc.Quote(goldAsset.Category, goldAsset.Code, 0, goldAsset.ProviderChain()...) // kimchi price
 	if err != nil {
 		e.lg.Error().Err(err).Msg("[goldKimchiPremium] PresentPrice 시, 에러 발생")
 		//todo log
-		e.ms.SendMessage(0, fmt.Sprintf("금 한국 가격 조회 시 오류. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("금 한국 가격 조회 시 오류. %s", err.Error()))
 		return
 	}
 
@@ -726,24 +900,27 @@ func (e InvestIndicator) runGoldKimchiPremium(isManual WayOfLaunch) {
 	if err != nil {
 		e.lg.Error().Err(err).Msg("[goldKimchiPremium] GoldPriceDollar 시, 에러 발생")
 		//todo log
-		e.ms.SendMessage(0, fmt.Sprintf("금 달러 가격 조회 시 오류. %s", err.Error()))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("금 달러 가격 조회 시 오류. %s", err.Error()))
 		return
 	}
 	ex := e.dp.ExchageRate() // exchange rate
 	cp := dp * ex            // converted price
 
-	kPrm := 100 * (kp - cp) / cp // k-premium
+	kPrm := 100 * (kq.Price - cp) / cp // k-premium
+	if err := e.stg.SavePremium(goldAsset.ID, kPrm); err != nil {
+		e.lg.Error().Err(err).Msg("[goldKimchiPremium] SavePremium 시, 에러 발생")
+	}
 
 	if kPrm > 10 {
-		e.ms.SendMessage(0, fmt.Sprintf("[매도] 금 김치 프리미엄 10프로 초과. 현재 프리미엄: %.2f", kPrm))
+		e.ms.SendMessage(notify.Alerts, fmt.Sprintf("[매도] 금 김치 프리미엄 10프로 초과. 현재 프리미엄: %.2f", kPrm))
 	} else if kPrm > 5 {
-		e.ms.SendMessage(0, fmt.Sprintf("[알림] 금 김치 프리미엄 5프로 초과. 현재 프리미엄: %.2f", kPrm))
+		e.ms.SendMessage(notify.Alerts, fmt.Sprintf("[알림] 금 김치 프리미엄 5프로 초과. 현재 프리미엄: %.2f", kPrm))
 	} else if kPrm < -2 {
-		e.ms.SendMessage(0, fmt.Sprintf("[매수] 금 역 김치 프리미엄 2프로 초과. 현재 프리미엄: %.2f", kPrm))
+		e.ms.SendMessage(notify.Alerts, fmt.Sprintf("[매수] 금 역 김치 프리미엄 2프로 초과. 현재 프리미엄: %.2f", kPrm))
 	}
 
 	if isManual {
-		e.ms.SendMessage(0, fmt.Sprintf("[알림] 현재 프리미엄: %.2f", kPrm))
+		e.ms.SendMessage(notify.Reports, fmt.Sprintf("[알림] 현재 프리미엄: %.2f", kPrm))
 	}
 }
 
@@ -780,13 +957,13 @@ func (e InvestIndicator) runAvaxDexEvent(isManual WayOfLaunch) {
 	// todo. 상태 가져오기
 
 	if isManual {
-		e.ms.SendMessage(0, fmt.Sprintf("[ManageAvaxDex] 현재 단계 %d. 범위: %.2f ~ %.2f", currentPhase, dexRange[0], dexRange[1]))
+		e.ms.SendMessage(notify.Reports, fmt.Sprintf("[ManageAvaxDex] 현재 단계 %d. 범위: %.2f ~ %.2f", currentPhase, dexRange[0], dexRange[1]))
 	}
 
 	assets, err := e.stg.RetrieveAssetList()
 	if err != nil {
 		e.lg.Error().Err(err).Msg("[ManageAvaxDex] RetrieveAssetList 시, 에러 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("[ManageAvaxDex] RetrieveAssetList 시, 에러 발생. %s", err))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("[ManageAvaxDex] RetrieveAssetList 시, 에러 발생. %s", err))
 		return
 	}
 
@@ -801,13 +978,13 @@ func (e InvestIndicator) runAvaxDexEvent(isManual WayOfLaunch) {
 
 	avaxInfo, err := e.stg.RetrieveAsset(avaxId)
 	if err != nil {
-		e.ms.SendMessage(0, fmt.Sprintf("[ManageAvaxDex] RetrieveAsset 시, 에러 발생. %s", err))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("[ManageAvaxDex] RetrieveAsset 시, 에러 발생. %s", err))
 		return
 	}
 
 	cp, err := e.rt.PresentPrice(m.ForeignCoin, avaxInfo.Code)
 	if err != nil {
-		e.ms.SendMessage(0, fmt.Sprintf("[ManageAvaxDex] PresentPrice 시, 에러 발생. %s", err))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("[ManageAvaxDex] PresentPrice 시, 에러 발생. %s", err))
 		return
 	}
 
@@ -823,7 +1000,7 @@ func (e InvestIndicator) runAvaxDexEvent(isManual WayOfLaunch) {
 		// todo 컨트랙트 조회로 수정 필요
 		invests, err := e.stg.RetreiveFundSummaryByAssetId(avaxId)
 		if err != nil {
-			e.ms.SendMessage(0, fmt.Sprintf("[ManageAvaxDex] RetreiveFundSummaryByAssetId 시, 에러 발생. %s", err))
+			e.ms.SendMessage(notify.Errors, fmt.Sprintf("[ManageAvaxDex] RetreiveFundSummaryByAssetId 시, 에러 발생. %s", err))
 			return
 		}
 		for _, invest := range invests {
@@ -836,20 +1013,20 @@ func (e InvestIndicator) runAvaxDexEvent(isManual WayOfLaunch) {
 		switch currentPhase {
 		case empty, full:
 			if currentPhase == empty {
-				e.ms.SendMessage(0, "[AVAX DEX Management] 현재 Phase EMPTY. 행동 필요. 아래 구간 진입 필요")
+				e.ms.SendMessage(notify.Alerts, "[AVAX DEX Management] 현재 Phase EMPTY. 행동 필요. 아래 구간 진입 필요")
 			} else {
-				e.ms.SendMessage(0, "[AVAX DEX Management] 헌재 Phase Full. 행동 필요. 전체 회수 및 아래 구간 진입 필요")
+				e.ms.SendMessage(notify.Alerts, "[AVAX DEX Management] 헌재 Phase Full. 행동 필요. 전체 회수 및 아래 구간 진입 필요")
 			}
-			e.ms.SendMessage(0, fmt.Sprintf("PUT %.0f Avax AND %.0f USDC", amount/3, amount/3*cp))
-			e.ms.SendMessage(0, fmt.Sprintf("%.2f", dexRange[0]))
-			e.ms.SendMessage(0, fmt.Sprintf("%.2f", dexRange[1]))
+			e.ms.SendMessage(notify.Alerts, fmt.Sprintf("PUT %.0f Avax AND %.0f USDC", amount/3, amount/3*cp))
+			e.ms.SendMessage(notify.Alerts, fmt.Sprintf("%.2f", dexRange[0]))
+			e.ms.SendMessage(notify.Alerts, fmt.Sprintf("%.2f", dexRange[1]))
 			inputedAvax = 2 * math.Round(amount/3)
 			currentPhase = twoThird
 		case twoThird:
-			e.ms.SendMessage(0, "[AVAX DEX Management] 헌재 Phase 2/3. 행동 필요. 아래 구간 진입 필요")
-			e.ms.SendMessage(0, fmt.Sprintf("PUT %.0f Avax AND %.0f USDC", amount-inputedAvax, (amount-inputedAvax)*cp))
-			e.ms.SendMessage(0, fmt.Sprintf("%.2f", dexRange[0]))
-			e.ms.SendMessage(0, fmt.Sprintf("%.2f", dexRange[1]))
+			e.ms.SendMessage(notify.Alerts, "[AVAX DEX Management] 헌재 Phase 2/3. 행동 필요. 아래 구간 진입 필요")
+			e.ms.SendMessage(notify.Alerts, fmt.Sprintf("PUT %.0f Avax AND %.0f USDC", amount-inputedAvax, (amount-inputedAvax)*cp))
+			e.ms.SendMessage(notify.Alerts, fmt.Sprintf("%.2f", dexRange[0]))
+			e.ms.SendMessage(notify.Alerts, fmt.Sprintf("%.2f", dexRange[1]))
 			currentPhase = full
 			inputedAvax = amount
 		}
@@ -865,14 +1042,14 @@ func (e InvestIndicator) runNewlyOpenedAirdropEvent(isManual WayOfLaunch) {
 	_ = isManual // no diff between manual or auto
 	upEvents, upUrls, err := e.rt.AirdropEventUpbit()
 	if err != nil {
-		e.ms.SendMessage(0, fmt.Sprintf("[runNewlyOpenedAirdropEvent] AirdropEventUpbit 시, 에러 발생. %s", err))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("[runNewlyOpenedAirdropEvent] AirdropEventUpbit 시, 에러 발생. %s", err))
 		goto bithumb
 	}
 
 	for i, event := range upEvents {
 		isExist, _ := e.stg.GetCache("upbit" + upUrls[i]).Bool()
 		if !isExist {
-			e.ms.SendMessage(0, fmt.Sprintf("[New Upbit Event] %s", event))
+			e.ms.SendMessage(notify.Alerts, fmt.Sprintf("[New Upbit Event] %s", event))
 			e.stg.SetCache("upbit"+upUrls[i], true, time.Hour*24*30*3)
 		}
 	}
@@ -880,14 +1057,14 @@ func (e InvestIndicator) runNewlyOpenedAirdropEvent(isManual WayOfLaunch) {
 bithumb:
 	bitEvents, bitUrls, err := e.rt.AirdropEventBithumb()
 	if err != nil {
-		e.ms.SendMessage(0, fmt.Sprintf("[runNewlyOpenedAirdropEvent] AirdropEventBithumb 시, 에러 발생. %s", err))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("[runNewlyOpenedAirdropEvent] AirdropEventBithumb 시, 에러 발생. %s", err))
 		return
 	}
 
 	for i, event := range bitEvents {
 		isExist, _ := e.stg.GetCache("bithumb" + bitUrls[i]).Bool()
 		if !isExist {
-			e.ms.SendMessage(0, fmt.Sprintf("[New Bithumb Event] %s", event))
+			e.ms.SendMessage(notify.Alerts, fmt.Sprintf("[New Bithumb Event] %s", event))
 			e.stg.SetCache("bithumb"+bitUrls[i], true, time.Hour*24*30*3)
 		}
 	}
@@ -901,11 +1078,11 @@ func (e InvestIndicator) runAvalancheSwap10TxEvent(isManual WayOfLaunch) {
 	for i := 0; i < 10; i++ {
 		err := e.bt.SwapUsdtUsdc(isUsdcIn)
 		if err != nil {
-			e.ms.SendMessage(0, err.Error())
+			e.ms.SendMessage(notify.Errors, err.Error())
 		}
 		isUsdcIn = !isUsdcIn
 	}
-	// e.ms.SendMessage(0,"AvalancheSwap10TxEvent 수행 완료")
+	// e.ms.SendMessage(notify.Alerts,"AvalancheSwap10TxEvent 수행 완료")
 }
 
 func (e InvestIndicator) runBlackholeDexStrategy() { // todo. 이벤트 등록
@@ -913,11 +1090,12 @@ func (e InvestIndicator) runBlackholeDexStrategy() { // todo. 이벤트 등록
 	c := make(chan string)
 	go func() {
 		err := e.bt.RunBlackholeDexStrategy(c)
-		e.ms.SendMessage(0, "RunBlackholeDexStrategy Shutdown. "+err.Error())
+		e.ms.SendMessage(notify.Errors, "RunBlackholeDexStrategy Shutdown. "+err.Error())
 	}()
 
 	for update := range c {
-		e.ms.SendMessage(1, update) // blackhole dex 전략 업데이트는 1번 채널로 전송
+		e.ms.SendMessage(notify.Dex, update) // blackhole dex 전략 업데이트는 dex route로 전송
+		e.publish(m.TopicReport, 0, update)
 	}
 }
 
@@ -949,6 +1127,7 @@ func calculateEma(oldEma *m.EmaHist, cp float64) (newEma *m.EmaHist) {
 		AssetID: oldEma.AssetID,
 		Ema:     ema,
 		NDays:   nDays,
+		Price:   cp,
 	}
 
 	return newEma
@@ -959,21 +1138,26 @@ func (e InvestIndicator) updateAsset(priceMap map[uint]float64, ivsmLi *[]m.Inve
 	assetList, err := e.stg.RetrieveAssetList()
 	if err != nil {
 		e.lg.Error().Err(err).Msg("[assetUpdate] RetrieveAssetList 시, 에러 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("[assetUpdate] RetrieveAssetList 시, 에러 발생. %s", err))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("[assetUpdate] RetrieveAssetList 시, 에러 발생. %s", err))
 		return
 	}
 
+	pm, summary := e.fetchPrices(assetList)
+	if summary.hasFailure() {
+		e.ms.SendMessage(notify.Errors, "[assetUpdate] "+summary.String())
+	}
+	for id, pp := range pm {
+		priceMap[id] = pp
+	}
+
 	// 등록 자산 매수/매도 기준 충족 시, 채널로 메시지 전달
-	for _, a := range assetList {
-		time.Sleep(300 * time.Millisecond) // memo. 초당 거래건수 초과 방지
-		msg, err := e.buySellMsg(a.ID, priceMap)
-		if err != nil {
-			e.lg.Error().Err(err).Msg("[assetUpdate] buySellMsg시, 에러 발생")
-			e.ms.SendMessage(0, fmt.Sprintf("[assetUpdate] buySellMsg시, 에러 발생. %s", err))
-			return
+	for i := range assetList {
+		pp, ok := priceMap[assetList[i].ID]
+		if !ok {
+			continue
 		}
-		if msg != "" {
-			e.ms.SendMessage(0, msg)
+		if msg := e.buySellMsg(&assetList[i], pp); msg != "" {
+			e.ms.SendMessage(notify.Alerts, msg)
 		}
 	}
 
@@ -981,7 +1165,7 @@ func (e InvestIndicator) updateAsset(priceMap map[uint]float64, ivsmLi *[]m.Inve
 	*ivsmLi, err = e.stg.RetreiveFundsSummaryOrderByFundId()
 	if err != nil {
 		e.lg.Error().Err(err).Msg("[assetUpdate] RetreiveFundsSummaryOrderByFundId 시, 에러 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("[assetUpdate] RetreiveFundsSummaryOrderByFundId 시, 에러 발생. %s", err))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("[assetUpdate] RetreiveFundsSummaryOrderByFundId 시, 에러 발생. %s", err))
 		return
 	}
 	if len(*ivsmLi) == 0 {
@@ -992,48 +1176,43 @@ func (e InvestIndicator) updateAsset(priceMap map[uint]float64, ivsmLi *[]m.Inve
 	err = e.updateFundSummarys(*ivsmLi, priceMap)
 	if err != nil {
 		e.lg.Error().Err(err).Msg("[assetUpdate] updateFundSummary 시, 에러 발생")
-		e.ms.SendMessage(0, fmt.Sprintf("[assetUpdate] updateFundSummary 시, 에러 발생. %s", err))
+		e.ms.SendMessage(notify.Errors, fmt.Sprintf("[assetUpdate] updateFundSummary 시, 에러 발생. %s", err))
 		return
 	}
 }
 
-func (e InvestIndicator) buySellMsg(assetId uint, pm map[uint]float64) (msg string, err error) {
-
-	// 자산 정보 조회
-	a, err := e.stg.RetrieveAsset(assetId)
-	if err != nil {
-		e.lg.Error().Err(err).Msg("[buySellMsg] RetrieveAsset 시, 에러 발생")
-		return "", fmt.Errorf("[buySellMsg] RetrieveAsset 시, 에러 발생. %w", err)
-	}
-
-	// 자산별 현재 가격 조회
-	pp, err := e.rt.PresentPrice(a.Category, a.Code)
-	if err != nil {
-		e.lg.Error().Err(err).Msg("[buySellMsg] PresentPrice 시, 에러 발생")
-		return "", fmt.Errorf("[buySellMsg] PresentPrice 시, 에러 발생. %w", err)
-	}
-
-	pm[assetId] = pp
+// buySellMsg 조회된 현재가를 자산의 매도/매수 기준과 비교하여 알림 메시지 생성. 최고가/최저가도 함께 갱신
+func (e InvestIndicator) buySellMsg(a *m.Asset, pp float64) (msg string) {
 
 	// 자산 매도/매수 기준 비교 및 알림 여부 판단. (알림 전송)
-	if a.BuyPrice >= pp && !cache.HasMsgCache(a.ID, false, a.BuyPrice) {
+	if a.BuyPrice >= pp && !e.ac.Has(cache.AssetBuy, a.ID, a.BuyPrice) {
 		msg = fmt.Sprintf("BUY %s. ID : %d. LOWER BOUND : %.2f. CURRENT PRICE :%.2f", a.Name, a.ID, a.BuyPrice, pp)
-		cache.SetMsgCache(a.ID, false, a.BuyPrice)
-	} else if a.SellPrice != 0 && a.SellPrice <= pp && e.isOwnedAsset(a.ID) && !cache.HasMsgCache(a.ID, true, a.SellPrice) {
+		e.ac.Set(cache.AssetBuy, a.ID, a.BuyPrice)
+		e.publish(m.TopicAlert, 0, m.PriceAlert{AssetID: a.ID, Name: a.Name, Side: m.Buy, Bound: a.BuyPrice, Price: pp})
+	} else if a.SellPrice != 0 && a.SellPrice <= pp && e.isOwnedAsset(a.ID) && !e.ac.Has(cache.AssetSell, a.ID, a.SellPrice) {
 		msg = fmt.Sprintf("SELL %s. ID : %d. UPPER BOUND : %.2f. CURRENT PRICE :%.2f", a.Name, a.ID, a.SellPrice, pp)
-		cache.SetMsgCache(a.ID, true, a.SellPrice)
+		e.ac.Set(cache.AssetSell, a.ID, a.SellPrice)
+		e.publish(m.TopicAlert, 0, m.PriceAlert{AssetID: a.ID, Name: a.Name, Side: m.Sell, Bound: a.SellPrice, Price: pp})
 	}
 
 	// 최고가/최저가 갱신 여부 판단
+	// memo. 시세 수신마다 갱신될 수 있어 변경 이력(audit) 미기록
+	// memo. a는 주기적으로 조회한 목록의 값이라 다른 필드는 최신이 아닐 수 있음. 최고가/최저가만 갱신
+	updated := false
 	if a.Top < pp {
 		a.Top = pp
-		e.stg.UpdateAssetInfo(*a)
+		updated = true
 	} else if a.Bottom > pp {
 		a.Bottom = pp
-		e.stg.UpdateAssetInfo(*a)
+		updated = true
+	}
+	if updated {
+		if err := e.stg.UpdateAssetTopBottom(a.ID, a.Top, a.Bottom); err != nil {
+			e.lg.Error().Err(err).Uint("asset", a.ID).Msg("[buySellMsg] UpdateAssetTopBottom 시, 에러 발생")
+		}
 	}
 
-	return
+	return msg
 }
 
 func (e InvestIndicator) isOwnedAsset(id uint) bool {
@@ -1057,7 +1236,11 @@ func (e InvestIndicator) isOwnedAsset(id uint) bool {
 func (e InvestIndicator) updateFundSummarys(list []m.InvestSummary, pm map[uint]float64) (err error) {
 	for i := range len(list) {
 		is := &list[i]
-		is.Sum = pm[is.AssetID] * float64(is.Count)
+		pp, ok := pm[is.AssetID]
+		if !ok { // 가격 조회 실패 자산은 기존 총액 유지
+			continue
+		}
+		is.Sum = pp * float64(is.Count)
 
 		err = e.stg.UpdateInvestSummarySum(is.FundID, is.AssetID, is.Sum)
 		if err != nil {
@@ -1131,11 +1314,12 @@ func (e InvestIndicator) genPortfolioMsg(ivsmLi []m.InvestSummary, pm map[uint]f
 		}
 
 		r := volatile[k] / (volatile[k] + stable[k])
-		if cache.HasPortCache(k) || (r > marketLevel.MinVolatileAssetRate() && r < marketLevel.MaxVolatileAssetRate()) { // 캐시가 있거나, 범주안에 있으면 스킵
-			e.lg.Info().Bool("cache", cache.HasPortCache(k)).Float64("rate", r).Msg("포트폴리오 행동 메시지 범주 제외")
+		hasCache := e.ac.Has(cache.Portfolio, k, 0)
+		if hasCache || (r > marketLevel.MinVolatileAssetRate() && r < marketLevel.MaxVolatileAssetRate()) { // 캐시가 있거나, 범주안에 있으면 스킵
+			e.lg.Info().Bool("cache", hasCache).Float64("rate", r).Msg("포트폴리오 행동 메시지 범주 제외")
 			continue
 		}
-		cache.SetPortCache(k)
+		e.ac.Set(cache.Portfolio, k, 0)
 
 		os := make([]priority, 0, len(ivsmLi)) // ordered slice
 		err = e.loadOrderSlice(&os, pm)
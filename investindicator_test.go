@@ -1,12 +1,18 @@
 package investind
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"investindicator/internal/cache"
 	m "investindicator/internal/model"
+	"investindicator/notify"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/robfig/cron"
 )
 
@@ -18,17 +24,11 @@ func TestEventbuySellMsg(t *testing.T) {
 
 	evt := NewInvestIndicator(stg, scrp, dp, nil, nil)
 
-	pm := make(map[uint]float64)
-
 	t.Run("buySellMsgTest-Buy", func(t *testing.T) {
 		stg.assets = []m.Asset{
 			{ID: 1, Name: "종목1", Category: m.DomesticStock, Code: "code", Currency: "WON", SellPrice: 480, BuyPrice: 450},
 		}
-		scrp.pp = 400
-		msg, err := evt.buySellMsg(1, pm)
-		if err != nil {
-			t.Error(err)
-		}
+		msg := evt.buySellMsg(&stg.assets[0], 400)
 		if strings.Contains(msg, "BUY") {
 			t.Log(msg)
 		} else {
@@ -40,11 +40,10 @@ func TestEventbuySellMsg(t *testing.T) {
 		stg.assets = []m.Asset{
 			{ID: 1, Name: "종목1", Category: m.DomesticStock, Code: "code", Currency: "WON", SellPrice: 480, BuyPrice: 450},
 		}
-		scrp.pp = 490
-		msg, err := evt.buySellMsg(1, pm)
-		if err != nil {
-			t.Error(err)
+		stg.ivsm = []m.InvestSummary{
+			{FundID: 1, AssetID: 1, Count: 10},
 		}
+		msg := evt.buySellMsg(&stg.assets[0], 490)
 		if strings.Contains(msg, "SELL") {
 			t.Log(msg)
 		} else {
@@ -56,17 +55,65 @@ func TestEventbuySellMsg(t *testing.T) {
 		stg.assets = []m.Asset{
 			{ID: 1, Name: "종목1", Category: m.DomesticStock, Code: "code", Currency: "WON", SellPrice: 480, BuyPrice: 450},
 		}
-		scrp.pp = 470
-		msg, err := evt.buySellMsg(1, pm)
-		if err != nil {
-			t.Error(err)
-		}
+		msg := evt.buySellMsg(&stg.assets[0], 470)
 		if msg == "" {
 			t.Log(msg)
 		} else {
 			t.Error(msg)
 		}
 	})
+
+	t.Run("buySellMsgTest-TopBottom", func(t *testing.T) {
+		stg.tb = make(map[uint][2]float64)
+		stg.assets = []m.Asset{
+			{ID: 1, Name: "종목1", Category: m.DomesticStock, Code: "code", Currency: "WON", SellPrice: 480, BuyPrice: 450, Top: 500, Bottom: 460},
+		}
+		evt.buySellMsg(&stg.assets[0], 470)
+		if len(stg.tb) != 0 {
+			t.Error(stg.tb)
+		}
+		evt.buySellMsg(&stg.assets[0], 510)
+		if stg.tb[1] != [2]float64{510, 460} {
+			t.Error(stg.tb)
+		}
+	})
+}
+
+func TestFetchPrices(t *testing.T) {
+
+	stg := &StorageMock{}
+	scrp := &RtPollerMock{
+		pp: 1000,
+		errs: map[string]error{
+			"fail1": errors.New("provider outage"),
+			"fail2": errors.New("provider outage"),
+		},
+	}
+	dp := &DailyPollerMock{}
+
+	evt := NewInvestIndicator(stg, scrp, dp, nil, nil)
+
+	assets := make([]m.Asset, 0)
+	for i := 1; i <= 20; i++ {
+		assets = append(assets, m.Asset{ID: uint(i), Name: fmt.Sprintf("종목%d", i), Category: m.DomesticStock, Code: fmt.Sprintf("code%d", i)})
+	}
+	assets = append(assets,
+		m.Asset{ID: 21, Name: "실패1", Category: m.DomesticCoin, Code: "fail1"},
+		m.Asset{ID: 22, Name: "실패2", Category: m.ForeignCoin, Code: "fail2"},
+	)
+
+	pm, summary := evt.fetchPrices(assets)
+
+	if len(pm) != 20 {
+		t.Error(len(pm))
+	}
+	if !summary.hasFailure() || len(summary.failures) != 2 || summary.total != 22 {
+		t.Error(summary)
+	}
+	if !strings.Contains(summary.String(), "실패1") || !strings.Contains(summary.String(), "실패2") {
+		t.Error(summary.String())
+	}
+	t.Log(summary.String())
 }
 
 func TestEventportfolioMsg(t *testing.T) {
@@ -134,16 +181,146 @@ func TestEventportfolioMsg(t *testing.T) {
 
 }
 
+func TestDigestMsg(t *testing.T) {
+
+	from := time.Now().Add(-time.Hour)
+	before := from.Add(-24 * time.Hour)
+
+	stg := &StorageMock{
+		market: &m.Market{Status: uint(m.BULL)},
+		assets: []m.Asset{
+			{ID: 1, Name: "비트코인", Category: m.DomesticCoin, BuyPrice: 900},
+			{ID: 2, Name: "원화", Category: m.Won},
+		},
+		navs: []m.FundNav{{FundID: 1, Amount: 10000, CreatedAt: before}},
+		emas: []m.EmaHist{{AssetID: 1, Date: before, Price: 1000}},
+		idx: []m.DailyIndex{
+			{CreatedAt: before, FearGreedIndex: 40, NasDaq: 100, Sp500: 100},
+			{CreatedAt: from.Add(time.Minute), FearGreedIndex: 50, NasDaq: 110, Sp500: 100},
+		},
+	}
+	evt := NewInvestIndicator(stg, &RtPollerMock{}, &DailyPollerMock{}, nil, nil)
+	evt.ac.Set(cache.AssetBuy, 1, 900)
+
+	ivsmLi := []m.InvestSummary{
+		{FundID: 1, Fund: m.Fund{Name: "개인"}, AssetID: 1, Asset: stg.assets[0], Count: 1, Sum: 800},
+		{FundID: 1, Fund: m.Fund{Name: "개인"}, AssetID: 2, Asset: stg.assets[1], Count: 10000, Sum: 10000},
+		{FundID: 2, Fund: m.Fund{Name: "미대상", IsExcept: true}, AssetID: 2, Asset: stg.assets[1], Count: 1, Sum: 1},
+	}
+	pm := map[uint]float64{1: 800, 2: 1}
+
+	msg, err := evt.genDigestMsg("일간", from, ivsmLi, pm, 1300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(msg)
+
+	for _, want := range []string{
+		"- 1 개인 : 10,800원 (+8.00%, +800원)",
+		"현금 92.6% | 코인 7.4%",
+		"- 비트코인 -20.00% (800)",
+		"BUY 비트코인 @ 900.00",
+		"- 공포탐욕 40 → 50 (+10)",
+		"- 나스닥 110.00 (+10.00%)",
+		"- 자금 1 변동 자산 비중 7.4% (BULL 기준 25~30%)",
+		"- 매수 기준 도달 비트코인",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Error(want)
+		}
+	}
+	if strings.Contains(msg, "미대상") {
+		t.Error("미대상 자금 포함")
+	}
+}
+
+func TestComma(t *testing.T) {
+	for v, want := range map[float64]string{0: "0", 999: "999", 1000: "1,000", 1234567.6: "1,234,568", -1234: "-1,234"} {
+		if got := comma(v); got != want {
+			t.Error(v, got)
+		}
+	}
+}
+
 func TestEnrolledEventLaunch(t *testing.T) {
 	testF := func(WayOfLaunch) {
 		fmt.Println("HELLO EVENT")
 	}
 
 	event := EnrolledEvent{
+		Id:    99,
+		Title: "test",
 		Event: testF,
 	}
 
 	event.Event(true)
+
+	InvestIndicator{}.launch(&event, Manual)
+	if n := testutil.ToFloat64(eventRuns.WithLabelValues("99", "test", "manual")); n != 1 {
+		t.Error(n)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	e := InvestIndicator{
+		hs:             newHealthState(),
+		enrolledEvents: []*EnrolledEvent{{Id: 1, Title: "test", Event: func(WayOfLaunch) {}}},
+	}
+
+	e.hs.streamUp("StreamCoinOrders")
+	e.hs.streamUp("StreamStockOrders")
+	e.hs.streamDown("StreamStockOrders", errors.New("websocket closed"))
+	e.launch(e.enrolledEvents[0], Auto)
+
+	h := e.Health(context.Background())
+	if len(h) != 3 {
+		t.Fatal(h)
+	}
+	if h[0].Name != "stream:StreamCoinOrders" || h[0].Status != m.HealthUp {
+		t.Error(h[0])
+	}
+	if h[1].Status != m.HealthDown || h[1].Error != "websocket closed" {
+		t.Error(h[1])
+	}
+	if h[2].Name != "event:1" || h[2].LastRun.IsZero() || !h[2].LastSuccess.IsZero() {
+		t.Error(h[2])
+	}
+}
+
+func TestRecordInvest(t *testing.T) {
+	var ivs []m.Invest
+	e := InvestIndicator{stg: StorageMock{ivs: &ivs}}
+
+	// 원화/달러 id 캐시 미존재 시 이력도 미저장
+	err := e.RecordInvest(m.Invest{FundID: 1, AssetID: 1, Price: 1000, Count: 1})
+	if err == nil {
+		t.Error("error expected")
+	}
+	if len(ivs) != 0 {
+		t.Error(ivs)
+	}
+}
+
+func TestRecordFills(t *testing.T) {
+	var ivs []m.Invest
+	stg := StorageMock{ivs: &ivs, cache: map[string]string{m.KRW.String(): "1", m.USD.String(): "2"}}
+	e := InvestIndicator{stg: stg, dp: &DailyPollerMock{}}
+
+	fc := make(chan m.Fill, 3)
+	for i := range 3 {
+		fc <- m.Fill{FundID: 1, AssetID: 3, Code: "BTC", Price: 1000, Count: float64(i + 1)}
+	}
+	close(fc)
+	e.recordFills(fc)
+
+	if len(ivs) != 3 {
+		t.Fatal(ivs)
+	}
+	for i, iv := range ivs {
+		if iv.FundID != 1 || iv.Count != float64(i+1) {
+			t.Error("체결 순서대로 기록", iv)
+		}
+	}
 }
 
 func TestRunNewlyOpenedAirdropEvent(t *testing.T) {
@@ -196,3 +373,56 @@ func TestRunNewlyOpenedAirdropEvent(t *testing.T) {
 		time.Sleep(1 * time.Minute)
 	})
 }
+
+type messengerMock struct {
+	c chan string
+}
+
+func (m messengerMock) SendMessage(route string, msg string) {
+	m.c <- msg
+}
+
+func (m messengerMock) Ask(route string, q notify.Question) (string, error) {
+	return q.Default, nil
+}
+
+func TestConsumeTicks(t *testing.T) {
+
+	stg := &StorageMock{
+		assets: []m.Asset{
+			{ID: 1, Name: "비트코인", Category: m.DomesticCoin, Code: "BTC", BuyPrice: 1000, Top: 2000, Bottom: 900},
+			{ID: 2, Name: "ETF", Category: m.DomesticETF, Code: "069500", BuyPrice: 100, Top: 200, Bottom: 90},
+		},
+	}
+	ms := messengerMock{c: make(chan string, 10)}
+	evt := NewInvestIndicator(stg, &RtPollerMock{}, &DailyPollerMock{}, nil, ms)
+
+	go evt.consumeTicks()
+	time.Sleep(100 * time.Millisecond) // 구독 대기
+
+	evt.pb.Publish(m.Tick{Category: m.DomesticCoin, Code: "BTC", Quote: m.Quote{Price: 950, Source: m.Upbit, FetchedAt: time.Now()}})
+	evt.pb.Publish(m.Tick{Category: m.DomesticStock, Code: "069500", Quote: m.Quote{Price: 95, Source: m.KIS, FetchedAt: time.Now()}}) // ETF도 코드로 매칭
+
+	for range 2 {
+		select {
+		case msg := <-ms.c:
+			if !strings.Contains(msg, "BUY") {
+				t.Error(msg)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("tick 수신 후 알림 미전송")
+		}
+	}
+
+	q, err := evt.qc.Quote(m.DomesticCoin, "BTC", time.Minute)
+	if err != nil || q.Price != 950 || q.Source != m.Upbit {
+		t.Error(q, err)
+	}
+
+	t.Run("Stream Targets", func(t *testing.T) {
+		targets := newStreamTargets(stg.assets)
+		if !slices.Equal(targets.coins, []string{"BTC"}) || !slices.Equal(targets.domestic, []string{"069500"}) || len(targets.overseas) != 0 {
+			t.Error(targets)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package investind
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	eventRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "event_runs_total",
+		Help: "event별 실행 횟수",
+	}, []string{"event", "title", "way"})
+
+	eventDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "event_run_duration_seconds",
+		Help:    "event별 실행 시간",
+		Buckets: []float64{.1, .5, 1, 5, 10, 30, 60, 180, 600},
+	}, []string{"event", "title"})
+
+	streamReconnects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "stream_reconnects_total",
+		Help: "websocket stream 재연결 횟수",
+	}, []string{"stream"})
+)
+
+func observeEvent(ev *EnrolledEvent, way WayOfLaunch, d time.Duration) {
+	id := strconv.FormatUint(uint64(ev.Id), 10)
+	eventRuns.WithLabelValues(id, ev.Title, way.String()).Inc()
+	eventDuration.WithLabelValues(id, ev.Title).Observe(d.Seconds())
+}
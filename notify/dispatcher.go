@@ -0,0 +1,255 @@
+package notify
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Severity 알림 중요도. 중요도에 따라 즉시 전송/제한/요약 여부 결정
+type Severity uint8
+
+const (
+	Info  Severity = iota + 1 // key별 전송 빈도 제한. 초과분은 버림
+	Alert                     // 제한 없이 즉시 전송
+	Error                     // key별 첫 오류만 즉시 전송, 반복 오류는 digest로 요약
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Alert:
+		return "alert"
+	case Error:
+		return "error"
+	}
+	return ""
+}
+
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "info":
+		return Info, nil
+	case "alert":
+		return Alert, nil
+	case "error":
+		return Error, nil
+	}
+	return 0, fmt.Errorf("존재하지 않는 severity. 입력 값 :%s", s)
+}
+
+const (
+	defaultDigestInterval   = 10 * time.Minute
+	defaultThrottleInterval = time.Minute
+	digestSampleLen         = 300 // digest에 포함할 마지막 오류 메시지 최대 길이
+)
+
+// route별 기본 severity. 미등록 route는 Info
+var defaultSeverities = map[string]Severity{
+	Alerts:  Alert,
+	Errors:  Error,
+	Dex:     Alert,
+	Reports: Info,
+}
+
+type errorStat struct {
+	first   time.Time
+	last    time.Time
+	repeats int // 즉시 전송 이후 반복 횟수
+	sample  string
+}
+
+// Dispatcher Router 앞단에서 severity에 따라 전송 제한 및 오류 요약 수행
+type Dispatcher struct {
+	r          *Router
+	severities map[string]Severity
+	digest     time.Duration
+	throttle   time.Duration
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	dropped  map[string]int
+	errs     map[string]*errorStat
+}
+
+type DispatcherOption func(*Dispatcher)
+
+func WithSeverity(route string, s Severity) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.severities[route] = s
+	}
+}
+
+func WithDigestInterval(i time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		if i > 0 {
+			d.digest = i
+		}
+	}
+}
+
+func WithThrottleInterval(i time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		if i > 0 {
+			d.throttle = i
+		}
+	}
+}
+
+func NewDispatcher(r *Router, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		r:          r,
+		severities: make(map[string]Severity),
+		digest:     defaultDigestInterval,
+		throttle:   defaultThrottleInterval,
+		limiters:   make(map[string]*rate.Limiter),
+		dropped:    make(map[string]int),
+		errs:       make(map[string]*errorStat),
+	}
+	for route, s := range defaultSeverities {
+		d.severities[route] = s
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Run digest 주기마다 반복 오류 요약 전송
+func (d *Dispatcher) Run() {
+	ticker := time.NewTicker(d.digest)
+	defer ticker.Stop()
+	for range ticker.C {
+		d.Flush()
+	}
+}
+
+func (d *Dispatcher) Severity(route string) Severity {
+	if s, ok := d.severities[route]; ok {
+		return s
+	}
+	return Info
+}
+
+func (d *Dispatcher) SendMessage(route string, msg string) {
+	d.Send(route, d.Severity(route), msg)
+}
+
+// Send severity를 직접 지정하여 전송
+func (d *Dispatcher) Send(route string, s Severity, msg string) {
+	switch s {
+	case Alert:
+		d.r.SendMessage(route, msg)
+	case Error:
+		if d.recordError(route, msg) {
+			d.r.SendMessage(route, msg)
+		}
+	default:
+		if d.allow(route, msg) {
+			d.r.SendMessage(route, msg)
+		}
+	}
+}
+
+func (d *Dispatcher) SendButtonsAndGetResult(route string, prompt string, options ...string) (string, error) {
+	return d.r.SendButtonsAndGetResult(route, prompt, options...)
+}
+
+func (d *Dispatcher) Ask(route string, q Question) (string, error) {
+	return d.r.Ask(route, q)
+}
+
+// allow Info 메시지 key별 전송 빈도 제한
+func (d *Dispatcher) allow(route, msg string) bool {
+	k := route + "|" + Key(msg)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	l, ok := d.limiters[k]
+	if !ok {
+		l = rate.NewLimiter(rate.Every(d.throttle), 1)
+		d.limiters[k] = l
+	}
+	if l.Allow() {
+		return true
+	}
+	d.dropped[k]++
+	return false
+}
+
+// recordError 오류 집계. digest 주기 내 첫 오류면 true(즉시 전송)
+func (d *Dispatcher) recordError(route, msg string) bool {
+	k := route + "|" + Key(msg)
+	now := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	st, ok := d.errs[k]
+	if !ok {
+		d.errs[k] = &errorStat{first: now, last: now}
+		return true
+	}
+	st.repeats++
+	st.last = now
+	st.sample = msg
+	return false
+}
+
+// Flush 반복 오류 및 제한된 메시지 요약을 route별로 전송하고 집계 초기화
+func (d *Dispatcher) Flush() {
+	d.mu.Lock()
+	errs := d.errs
+	dropped := d.dropped
+	d.errs = make(map[string]*errorStat)
+	d.dropped = make(map[string]int)
+	d.mu.Unlock()
+
+	digests := make(map[string][]string)
+	for k, st := range errs {
+		if st.repeats == 0 {
+			continue
+		}
+		route, key, _ := strings.Cut(k, "|")
+		sample := []rune(st.sample)
+		if len(sample) > digestSampleLen {
+			sample = append(sample[:digestSampleLen], []rune("...")...)
+		}
+		digests[route] = append(digests[route], fmt.Sprintf("- %s : %d회 반복 (%s ~ %s)\n  마지막 : %s",
+			key, st.repeats, st.first.Format("15:04:05"), st.last.Format("15:04:05"), string(sample)))
+	}
+	for k, n := range dropped {
+		route, key, _ := strings.Cut(k, "|")
+		digests[route] = append(digests[route], fmt.Sprintf("- %s : %d건 전송 제한", key, n))
+	}
+
+	routes := make([]string, 0, len(digests))
+	for route := range digests {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	for _, route := range routes {
+		lines := digests[route]
+		sort.Strings(lines)
+		d.r.SendMessage(route, fmt.Sprintf("[알림 요약] 최근 %s\n%s", d.digest, strings.Join(lines, "\n")))
+	}
+}
+
+var digitsRe = regexp.MustCompile(`[0-9]+(\.[0-9]+)?`)
+
+/*
+Key 메시지 그룹핑 key. 첫 줄의 첫 문장에서 숫자를 제거한 값
+ex) "[EmaUpdateEvent] ClosingPrice 시, 에러 발생. dial tcp ..." => "[EmaUpdateEvent] ClosingPrice 시, 에러 발생"
+*/
+func Key(msg string) string {
+	line, _, _ := strings.Cut(msg, "\n")
+	if i := strings.Index(line, ". "); i > 0 {
+		line = line[:i]
+	}
+	return digitsRe.ReplaceAllString(strings.TrimSpace(line), "#")
+}
@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+)
+
+type EmailConfig struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	From     string
+	To       []string
+	Subject  string // 미지정 시 기본 제목 사용
+}
+
+type Email struct {
+	name string
+	conf EmailConfig
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewEmail(name string, conf EmailConfig) (*Email, error) {
+	if conf.Host == "" || conf.Port == 0 {
+		return nil, errors.New("smtp host/port 미존재")
+	}
+	if len(conf.To) == 0 {
+		return nil, errors.New("수신자 미존재")
+	}
+	if conf.From == "" {
+		conf.From = conf.User
+	}
+	if conf.Subject == "" {
+		conf.Subject = "[InvestIndicator] 알림"
+	}
+	return &Email{
+		name: name,
+		conf: conf,
+		send: smtp.SendMail,
+	}, nil
+}
+
+func (e *Email) Name() string { return e.name }
+
+func (e *Email) Send(msg string) error {
+	var auth smtp.Auth
+	if e.conf.User != "" {
+		auth = smtp.PlainAuth("", e.conf.User, e.conf.Password, e.conf.Host)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("From: " + e.conf.From + "\r\n")
+	sb.WriteString("To: " + strings.Join(e.conf.To, ", ") + "\r\n")
+	sb.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", e.conf.Subject) + "\r\n")
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	sb.WriteString(msg)
+
+	addr := fmt.Sprintf("%s:%d", e.conf.Host, e.conf.Port)
+	if err := e.send(addr, auth, e.conf.From, e.conf.To, []byte(sb.String())); err != nil {
+		return fmt.Errorf("email 전송 시 오류 발생. %w", err)
+	}
+	return nil
+}
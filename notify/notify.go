@@ -0,0 +1,186 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog"
+)
+
+// 이벤트 코드에서 사용하는 route 이름. 실제 전송 채널은 config의 routes로 지정
+const (
+	Alerts  = "alerts"  // 매수/매도, 김치 프리미엄 등 알림
+	Errors  = "errors"  // 이벤트 수행 중 오류
+	Dex     = "dex"     // blackhole dex 전략 업데이트
+	Reports = "reports" // 지표 조회 등 정보성 메시지. 미설정 시 alerts 채널로 전송
+)
+
+// Channel 알림 전송 채널. telegram, slack, discord, email, webhook 등
+type Channel interface {
+	Name() string
+	Send(msg string) error
+}
+
+// Prompter 버튼 선택지를 보낼 수 있는 채널. 응답은 Prompts.AnswerIndex로 전달. 현재는 telegram만 지원
+type Prompter interface {
+	SendPrompt(p Prompt) error
+}
+
+// Router route 이름으로 채널 목록을 찾아 전송. 미등록 route는 기본 route(alerts)로 전송
+type Router struct {
+	channels map[string]Channel
+	routes   map[string][]Channel
+	prompts  *Prompts
+	lg       zerolog.Logger
+}
+
+type RouterOption func(*Router)
+
+// WithPromptTimeout 선택지 요청 기본 응답 대기 시간
+func WithPromptTimeout(d time.Duration) RouterOption {
+	return func(r *Router) {
+		r.prompts = NewPrompts(d)
+	}
+}
+
+func NewRouter(opts ...RouterOption) *Router {
+	r := &Router{
+		channels: make(map[string]Channel),
+		routes:   make(map[string][]Channel),
+		prompts:  NewPrompts(defaultPromptTimeout),
+		lg:       zerolog.New(os.Stdout).With().Str("Module", "Notify").Timestamp().Logger(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Prompts 응답 대기 중인 요청 목록. REST 응답 및 telegram callback 연결에 사용
+func (r *Router) Prompts() *Prompts {
+	return r.prompts
+}
+
+func (r *Router) AddChannel(c Channel) error {
+	if _, ok := r.channels[c.Name()]; ok {
+		return fmt.Errorf("중복된 채널 이름. %s", c.Name())
+	}
+	r.channels[c.Name()] = c
+	return nil
+}
+
+// SetRoute route에 채널 이름 목록 지정
+func (r *Router) SetRoute(route string, names ...string) error {
+	chs := make([]Channel, 0, len(names))
+	for _, name := range names {
+		c, ok := r.channels[name]
+		if !ok {
+			return fmt.Errorf("route %s의 채널 %s 미존재", route, name)
+		}
+		chs = append(chs, c)
+	}
+	r.routes[route] = chs
+	return nil
+}
+
+func (r *Router) Routes() map[string][]string {
+	rtn := make(map[string][]string, len(r.routes))
+	for route, chs := range r.routes {
+		for _, c := range chs {
+			rtn[route] = append(rtn[route], c.Name())
+		}
+	}
+	return rtn
+}
+
+func (r *Router) resolve(route string) []Channel {
+	if chs, ok := r.routes[route]; ok {
+		return chs
+	}
+	return r.routes[Alerts]
+}
+
+// SendMessage route의 모든 채널로 전송. 일부 채널 실패 시 로그만 남기고 나머지 채널은 계속 전송
+func (r *Router) SendMessage(route string, msg string) {
+	chs := r.resolve(route)
+	if len(chs) == 0 {
+		r.lg.Warn().Str("route", route).Str("msg", msg).Msg("전송 채널 미존재")
+		return
+	}
+	for _, c := range chs {
+		if err := c.Send(msg); err != nil {
+			r.lg.Error().Err(err).Str("route", route).Str("channel", c.Name()).Msg("알림 전송 실패")
+		}
+	}
+}
+
+// SendButtonsAndGetResult timeout 시 기본 응답 없이 선택지 요청
+func (r *Router) SendButtonsAndGetResult(route string, prompt string, options ...string) (string, error) {
+	return r.Ask(route, Question{Prompt: prompt, Options: options})
+}
+
+/*
+Ask route에서 버튼 응답을 지원하는 첫 채널로 선택지 전송 후 응답 대기
+  - 요청마다 ID를 부여하여 동시에 여러 요청이 있어도 응답이 섞이지 않음
+  - 채널 버튼 외에 Prompts.Answer(REST)로도 응답 가능
+  - timeout 시 Default 응답을 사용하고 route로 안내 메시지 전송
+*/
+func (r *Router) Ask(route string, q Question) (string, error) {
+	var p Prompter
+	for _, c := range r.resolve(route) {
+		if pc, ok := c.(Prompter); ok {
+			p = pc
+			break
+		}
+	}
+	if p == nil {
+		return "", errors.New("버튼 응답을 지원하는 채널 미존재. route : " + route)
+	}
+
+	answer, timedOut, err := r.prompts.Ask(route, q, p.SendPrompt)
+	if timedOut {
+		msg := fmt.Sprintf("[응답 시간 초과] %s", q.Prompt)
+		if err == nil {
+			msg += fmt.Sprintf("\n기본값 '%s' 적용", answer)
+		}
+		r.SendMessage(route, msg)
+	}
+	return answer, err
+}
+
+// Split limit 글자 수 이하로 메시지 분할. 가능하면 줄 단위로 나누고, 한 줄이 limit을 넘으면 글자 수로 자름
+func Split(msg string, limit int) []string {
+	if limit <= 0 || utf8.RuneCountInString(msg) <= limit {
+		return []string{msg}
+	}
+
+	parts := make([]string, 0)
+	var sb strings.Builder
+	n := 0
+	flush := func() {
+		if n > 0 {
+			parts = append(parts, strings.TrimSuffix(sb.String(), "\n"))
+			sb.Reset()
+			n = 0
+		}
+	}
+	for _, line := range strings.SplitAfter(msg, "\n") {
+		rs := []rune(line)
+		for len(rs) > limit {
+			flush()
+			parts = append(parts, string(rs[:limit]))
+			rs = rs[limit:]
+		}
+		if n+len(rs) > limit {
+			flush()
+		}
+		sb.WriteString(string(rs))
+		n += len(rs)
+	}
+	flush()
+	return parts
+}
@@ -0,0 +1,252 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type channelMock struct {
+	name string
+	msgs []string
+	err  error
+}
+
+func (c *channelMock) Name() string { return c.name }
+
+func (c *channelMock) Send(msg string) error {
+	c.msgs = append(c.msgs, msg)
+	return c.err
+}
+
+func TestRouter(t *testing.T) {
+
+	tg := &channelMock{name: "telegram-0"}
+	slack := &channelMock{name: "slack", err: errors.New("slack down")}
+	mail := &channelMock{name: "mail"}
+
+	r := NewRouter()
+	for _, c := range []Channel{tg, slack, mail} {
+		assert.NoError(t, r.AddChannel(c))
+	}
+	assert.Error(t, r.AddChannel(&channelMock{name: "mail"}))
+	assert.NoError(t, r.SetRoute(Alerts, "telegram-0", "slack"))
+	assert.NoError(t, r.SetRoute(Errors, "mail"))
+	assert.Error(t, r.SetRoute(Dex, "unknown"))
+
+	t.Run("Fan Out", func(t *testing.T) {
+		r.SendMessage(Alerts, "BUY")
+		assert.Equal(t, []string{"BUY"}, tg.msgs)
+		assert.Equal(t, []string{"BUY"}, slack.msgs) // 실패한 채널이 있어도 나머지 전송
+		assert.Empty(t, mail.msgs)
+	})
+
+	t.Run("Route", func(t *testing.T) {
+		r.SendMessage(Errors, "에러 발생")
+		assert.Equal(t, []string{"에러 발생"}, mail.msgs)
+	})
+
+	t.Run("Default Route", func(t *testing.T) {
+		r.SendMessage(Dex, "dex update")
+		assert.Equal(t, "dex update", tg.msgs[len(tg.msgs)-1])
+	})
+
+	t.Run("No Prompter", func(t *testing.T) {
+		_, err := r.SendButtonsAndGetResult(Errors, "prompt", "1", "2")
+		assert.Error(t, err)
+	})
+}
+
+func TestWebhook(t *testing.T) {
+
+	var body map[string]string
+	var header string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Token")
+		json.NewDecoder(r.Body).Decode(&body)
+		if strings.HasSuffix(r.URL.Path, "/fail") {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("Slack", func(t *testing.T) {
+		assert.NoError(t, NewSlack("slack", srv.URL).Send("hello"))
+		assert.Equal(t, "hello", body["text"])
+	})
+
+	t.Run("Discord", func(t *testing.T) {
+		assert.NoError(t, NewDiscord("discord", srv.URL).Send(strings.Repeat("가", discordMaxLen+10)))
+		assert.Equal(t, discordMaxLen, len([]rune(body["content"])))
+	})
+
+	t.Run("Webhook", func(t *testing.T) {
+		assert.NoError(t, NewWebhook("hook", srv.URL, map[string]string{"X-Token": "secret"}).Send("hello"))
+		assert.Equal(t, "secret", header)
+		assert.Error(t, NewWebhook("hook", srv.URL+"/fail", nil).Send("hello"))
+	})
+}
+
+func TestEmail(t *testing.T) {
+
+	_, err := NewEmail("mail", EmailConfig{Host: "smtp.example.com", Port: 587})
+	assert.Error(t, err)
+
+	e, err := NewEmail("mail", EmailConfig{Host: "smtp.example.com", Port: 587, User: "me@example.com", To: []string{"you@example.com"}})
+	assert.NoError(t, err)
+
+	var sent string
+	e.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, "smtp.example.com:587", addr)
+		assert.Equal(t, "me@example.com", from)
+		sent = string(msg)
+		return nil
+	}
+	assert.NoError(t, e.Send("알림 본문"))
+	assert.Contains(t, sent, "To: you@example.com")
+	assert.True(t, strings.HasSuffix(sent, "알림 본문"))
+}
+
+func TestDispatcher(t *testing.T) {
+
+	tg := &channelMock{name: "telegram-0"}
+	r := NewRouter()
+	assert.NoError(t, r.AddChannel(tg))
+	assert.NoError(t, r.SetRoute(Alerts, "telegram-0"))
+
+	d := NewDispatcher(r, WithThrottleInterval(time.Hour))
+
+	t.Run("Alert", func(t *testing.T) {
+		d.SendMessage(Alerts, "BUY")
+		d.SendMessage(Alerts, "BUY")
+		assert.Equal(t, []string{"BUY", "BUY"}, tg.msgs)
+		tg.msgs = nil
+	})
+
+	t.Run("Error Digest", func(t *testing.T) {
+		for i := range 3 {
+			d.SendMessage(Errors, fmt.Sprintf("[Event] PresentPrice 시, 에러 발생. timeout %d", i))
+		}
+		assert.Len(t, tg.msgs, 1) // 반복 오류는 즉시 전송하지 않음
+
+		d.Flush()
+		assert.Len(t, tg.msgs, 2)
+		assert.Contains(t, tg.msgs[1], "[알림 요약]")
+		assert.Contains(t, tg.msgs[1], "2회 반복")
+		assert.Contains(t, tg.msgs[1], "timeout 2")
+
+		d.Flush() // 집계 초기화 이후 반복 오류가 없으면 전송하지 않음
+		assert.Len(t, tg.msgs, 2)
+		tg.msgs = nil
+	})
+
+	t.Run("Info Throttle", func(t *testing.T) {
+		d.SendMessage(Reports, "[알림] BTC 현재 프리미엄: 3.12")
+		d.SendMessage(Reports, "[알림] BTC 현재 프리미엄: 3.15")
+		d.SendMessage(Reports, "[알림] ETH 현재 프리미엄: 2.01")
+		assert.Len(t, tg.msgs, 2)
+
+		d.Flush()
+		assert.Contains(t, tg.msgs[2], "1건 전송 제한")
+	})
+
+	t.Run("Severity", func(t *testing.T) {
+		assert.Equal(t, Error, d.Severity(Errors))
+		assert.Equal(t, Info, d.Severity("unknown"))
+		_, err := ParseSeverity("fatal")
+		assert.Error(t, err)
+	})
+}
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, "[EmaUpdateEvent] ClosingPrice 시, 에러 발생", Key("[EmaUpdateEvent] ClosingPrice 시, 에러 발생. dial tcp 10.0.0.1:443"))
+	assert.Equal(t, "[알림] BTC 현재 프리미엄: #", Key("[알림] BTC 현재 프리미엄: 3.12\n상세"))
+}
+
+func TestSplit(t *testing.T) {
+	assert.Equal(t, []string{"abc"}, Split("abc", 10))
+	assert.Equal(t, []string{"ab\ncd", "ef"}, Split("ab\ncd\nef", 6))
+	assert.Equal(t, []string{"가나다", "라마", "a"}, Split("가나다라마\na", 3)) // limit을 넘는 줄은 글자 수로 자름
+}
+
+type prompterMock struct {
+	channelMock
+	c chan Prompt
+}
+
+func (p *prompterMock) SendPrompt(pr Prompt) error {
+	p.c <- pr
+	return nil
+}
+
+func TestAsk(t *testing.T) {
+
+	tg := &prompterMock{channelMock: channelMock{name: "telegram-0"}, c: make(chan Prompt, 10)}
+	r := NewRouter(WithPromptTimeout(time.Second))
+	assert.NoError(t, r.AddChannel(tg))
+	assert.NoError(t, r.SetRoute(Alerts, "telegram-0"))
+
+	t.Run("Correlation", func(t *testing.T) {
+		answers := make(chan string, 2)
+		for _, prompt := range []string{"A", "B"} {
+			go func() {
+				ans, err := r.Ask(Alerts, Question{Prompt: prompt, Options: []string{"1", "2"}})
+				assert.NoError(t, err)
+				answers <- prompt + ans
+			}()
+		}
+		p1, p2 := <-tg.c, <-tg.c
+		assert.Len(t, r.Prompts().Pending(), 2)
+
+		// 요청 순서와 반대로 응답해도 각 요청에 맞는 응답 전달
+		_, err := r.Prompts().AnswerIndex(p2.ID, 1)
+		assert.NoError(t, err)
+		assert.NoError(t, r.Prompts().Answer(p1.ID, "1"))
+
+		got := []string{<-answers, <-answers}
+		assert.ElementsMatch(t, []string{p1.Prompt + "1", p2.Prompt + "2"}, got)
+		assert.Empty(t, r.Prompts().Pending())
+
+		assert.ErrorIs(t, r.Prompts().Answer(p1.ID, "1"), ErrPromptNotFound)
+	})
+
+	t.Run("Invalid Answer", func(t *testing.T) {
+		go r.Ask(Alerts, Question{Prompt: "C", Options: []string{"1"}})
+		p := <-tg.c
+		assert.Error(t, r.Prompts().Answer(p.ID, "3"))
+		_, err := r.Prompts().AnswerIndex(p.ID, 5)
+		assert.Error(t, err)
+		assert.NoError(t, r.Prompts().Cancel(p.ID))
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		ans, err := r.Ask(Alerts, Question{Prompt: "D", Options: []string{"1", "skip"}, Default: "skip", Timeout: 10 * time.Millisecond})
+		<-tg.c
+		assert.NoError(t, err)
+		assert.Equal(t, "skip", ans)
+		assert.Contains(t, tg.msgs[len(tg.msgs)-1], "기본값 'skip' 적용")
+
+		_, err = r.Ask(Alerts, Question{Prompt: "E", Options: []string{"1"}, Timeout: 10 * time.Millisecond})
+		<-tg.c
+		assert.ErrorIs(t, err, ErrPromptTimeout)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		errs := make(chan error)
+		go func() {
+			_, err := r.Ask(Alerts, Question{Prompt: "F", Options: []string{"1"}})
+			errs <- err
+		}()
+		p := <-tg.c
+		assert.NoError(t, r.Prompts().Cancel(p.ID))
+		assert.ErrorIs(t, <-errs, ErrPromptCancelled)
+	})
+}
@@ -0,0 +1,205 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const defaultPromptTimeout = 10 * time.Minute
+
+var (
+	ErrPromptTimeout   = errors.New("응답 시간 초과")
+	ErrPromptCancelled = errors.New("요청 취소")
+	ErrPromptNotFound  = errors.New("존재하지 않거나 이미 종료된 요청")
+)
+
+/*
+Question 선택지 요청
+  - Timeout이 0 이하면 Prompts 기본 timeout 사용
+  - Default가 비어있으면 timeout 시 ErrPromptTimeout 반환, 있으면 Default를 응답으로 사용
+*/
+type Question struct {
+	Prompt  string
+	Options []string
+	Default string
+	Timeout time.Duration
+}
+
+// Prompt 응답 대기 중인 요청. ID는 telegram callback data 등에 포함되어 응답과 요청을 매칭하는 데 사용
+type Prompt struct {
+	Question
+	ID        string
+	Route     string
+	CreatedAt time.Time
+	Deadline  time.Time
+}
+
+type promptResult struct {
+	answer string
+	err    error
+}
+
+type pendingPrompt struct {
+	Prompt
+	ch chan promptResult // buffer 1. 응답/취소는 한 번만 전달
+}
+
+// Prompts 응답 대기 중인 요청 목록. 요청별 채널로 응답을 전달하므로 동시에 여러 요청이 있어도 응답이 섞이지 않음
+type Prompts struct {
+	timeout time.Duration
+	mu      sync.Mutex
+	pending map[string]*pendingPrompt
+}
+
+func NewPrompts(timeout time.Duration) *Prompts {
+	if timeout <= 0 {
+		timeout = defaultPromptTimeout
+	}
+	return &Prompts{
+		timeout: timeout,
+		pending: make(map[string]*pendingPrompt),
+	}
+}
+
+// Pending 응답 대기 중인 요청 목록. 생성 순
+func (p *Prompts) Pending() []Prompt {
+	p.mu.Lock()
+	li := make([]Prompt, 0, len(p.pending))
+	for _, pp := range p.pending {
+		li = append(li, pp.Prompt)
+	}
+	p.mu.Unlock()
+
+	slices.SortFunc(li, func(a, b Prompt) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return li
+}
+
+func (p *Prompts) Get(id string) (Prompt, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pp, ok := p.pending[id]
+	if !ok {
+		return Prompt{}, false
+	}
+	return pp.Prompt, true
+}
+
+// Answer 선택지 중 하나로 응답. REST 등 선택지 값을 직접 받는 경우 사용
+func (p *Prompts) Answer(id string, answer string) error {
+	p.mu.Lock()
+	pp, ok := p.pending[id]
+	if ok && !slices.Contains(pp.Options, answer) {
+		p.mu.Unlock()
+		return fmt.Errorf("선택지에 없는 응답. 입력 값 :%s", answer)
+	}
+	delete(p.pending, id)
+	p.mu.Unlock()
+
+	if !ok {
+		return ErrPromptNotFound
+	}
+	pp.ch <- promptResult{answer: answer}
+	return nil
+}
+
+// AnswerIndex 선택지 index로 응답. telegram callback처럼 data 길이 제한이 있는 경우 사용
+func (p *Prompts) AnswerIndex(id string, idx int) (string, error) {
+	p.mu.Lock()
+	pp, ok := p.pending[id]
+	p.mu.Unlock()
+	if !ok {
+		return "", ErrPromptNotFound
+	}
+	if idx < 0 || idx >= len(pp.Options) {
+		return "", fmt.Errorf("선택지 index 오류. %d", idx)
+	}
+
+	answer := pp.Options[idx]
+	return answer, p.Answer(id, answer)
+}
+
+// Cancel 응답 대기 중단. 대기 중인 호출 측은 ErrPromptCancelled 반환
+func (p *Prompts) Cancel(id string) error {
+	p.mu.Lock()
+	pp, ok := p.pending[id]
+	delete(p.pending, id)
+	p.mu.Unlock()
+
+	if !ok {
+		return ErrPromptNotFound
+	}
+	pp.ch <- promptResult{err: ErrPromptCancelled}
+	return nil
+}
+
+// Ask 요청 등록 후 send로 전송하고 응답 대기. 전송 실패 시 요청 제거 후 오류 반환
+func (p *Prompts) Ask(route string, q Question, send func(Prompt) error) (answer string, timedOut bool, err error) {
+	pp := p.open(route, q)
+	if err := send(pp.Prompt); err != nil {
+		p.remove(pp.ID)
+		return "", false, fmt.Errorf("선택지 전송 시 오류 발생. %w", err)
+	}
+	return p.wait(pp)
+}
+
+func (p *Prompts) open(route string, q Question) *pendingPrompt {
+	if q.Timeout <= 0 {
+		q.Timeout = p.timeout
+	}
+	now := time.Now()
+	pp := &pendingPrompt{
+		Prompt: Prompt{
+			Question:  q,
+			ID:        uuid.NewString(),
+			Route:     route,
+			CreatedAt: now,
+			Deadline:  now.Add(q.Timeout),
+		},
+		ch: make(chan promptResult, 1),
+	}
+
+	p.mu.Lock()
+	p.pending[pp.ID] = pp
+	p.mu.Unlock()
+	return pp
+}
+
+// remove 전송 실패 등으로 대기 없이 종료할 때 사용
+func (p *Prompts) remove(id string) {
+	p.mu.Lock()
+	delete(p.pending, id)
+	p.mu.Unlock()
+}
+
+// wait 응답, 취소, timeout 중 먼저 발생한 결과 반환. timeout 시 Default가 있으면 Default 응답
+func (p *Prompts) wait(pp *pendingPrompt) (answer string, timedOut bool, err error) {
+	timer := time.NewTimer(time.Until(pp.Deadline))
+	defer timer.Stop()
+
+	select {
+	case res := <-pp.ch:
+		return res.answer, false, res.err
+	case <-timer.C:
+	}
+
+	p.mu.Lock()
+	_, ok := p.pending[pp.ID]
+	delete(p.pending, pp.ID)
+	p.mu.Unlock()
+	if !ok { // timeout 직전에 응답/취소된 경우
+		res := <-pp.ch
+		return res.answer, false, res.err
+	}
+
+	if pp.Default == "" {
+		return "", true, ErrPromptTimeout
+	}
+	return pp.Default, true, nil
+}
@@ -0,0 +1,36 @@
+package notify
+
+// telegram 메시지 최대 길이
+const telegramMaxLen = 4096
+
+// memo. bot.TeleBot이 구현
+type teleBot interface {
+	SendMessage(msg string)
+	SendPrompt(id string, prompt string, options ...string) error
+}
+
+type Telegram struct {
+	name string
+	bot  teleBot
+}
+
+func NewTelegram(name string, bot teleBot) *Telegram {
+	return &Telegram{
+		name: name,
+		bot:  bot,
+	}
+}
+
+func (t *Telegram) Name() string { return t.name }
+
+// Send 최대 길이를 넘는 메시지는 나누어 전송
+func (t *Telegram) Send(msg string) error {
+	for _, part := range Split(msg, telegramMaxLen) {
+		t.bot.SendMessage(part)
+	}
+	return nil
+}
+
+func (t *Telegram) SendPrompt(p Prompt) error {
+	return t.bot.SendPrompt(p.ID, p.Prompt, p.Options...)
+}
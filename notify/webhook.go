@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// Webhook 임의의 http endpoint로 json 전송. body 형식 : {"text": msg}
+type Webhook struct {
+	name    string
+	url     string
+	headers map[string]string
+	body    func(msg string) any
+}
+
+func NewWebhook(name, url string, headers map[string]string) *Webhook {
+	return &Webhook{
+		name:    name,
+		url:     url,
+		headers: headers,
+		body: func(msg string) any {
+			return map[string]string{"text": msg}
+		},
+	}
+}
+
+// NewSlack slack incoming webhook. body 형식 : {"text": msg}
+func NewSlack(name, url string) *Webhook {
+	return NewWebhook(name, url, nil)
+}
+
+// discord 메시지 최대 길이
+const discordMaxLen = 2000
+
+// NewDiscord discord webhook. body 형식 : {"content": msg}
+func NewDiscord(name, url string) *Webhook {
+	w := NewWebhook(name, url, nil)
+	w.body = func(msg string) any {
+		if r := []rune(msg); len(r) > discordMaxLen {
+			msg = string(r[:discordMaxLen])
+		}
+		return map[string]string{"content": msg}
+	}
+	return w
+}
+
+func (w *Webhook) Name() string { return w.name }
+
+func (w *Webhook) Send(msg string) error {
+	b, err := json.Marshal(w.body(msg))
+	if err != nil {
+		return fmt.Errorf("webhook body 변환 시 오류 발생. %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("webhook request 생성 시 오류 발생. %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook 전송 시 오류 발생. %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("webhook 응답 오류. status : %d, body : %s", res.StatusCode, body)
+	}
+	return nil
+}
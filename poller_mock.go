@@ -1,6 +1,7 @@
 package investind
 
 import (
+	"context"
 	md "investindicator/internal/model"
 )
 
@@ -8,15 +9,29 @@ type RtPollerMock struct {
 	pp     float64
 	estate string
 	err    error
+	errs   map[string]error // 종목 코드별 오류
 }
 
-func (m RtPollerMock) PresentPrice(category md.Category, code string) (float64, error) {
+func (m RtPollerMock) PresentPrice(category md.Category, code string, providers ...md.Provider) (float64, error) {
 	if m.err != nil {
 		return 0, m.err
 	}
+	if err := m.errs[code]; err != nil {
+		return 0, err
+	}
 	return m.pp, nil
 }
 
+func (m RtPollerMock) StreamCoinTickers(ctx context.Context, codes []string, c chan<- md.Tick) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (m RtPollerMock) StreamStockTickers(ctx context.Context, domestic []string, overseas []string, c chan<- md.Tick) error {
+	<-ctx.Done()
+	return nil
+}
+
 func (m RtPollerMock) RealEstateStatus() (string, error) {
 	if m.err != nil {
 		return "", m.err
@@ -74,7 +89,7 @@ func (m DailyPollerMock) CliIdx() (float64, error) {
 	return 0, nil
 }
 
-func (m DailyPollerMock) ClosingPrice(category md.Category, code string) (float64, error) {
+func (m DailyPollerMock) ClosingPrice(category md.Category, code string, providers ...md.Provider) (float64, error) {
 	return 0, nil
 }
 func (m DailyPollerMock) HighYieldSpread() (date string, spread float64, err error) {
@@ -7,7 +7,7 @@ import (
 
 const bithumbUrlForm = "https://api.bithumb.com/v1/candles/days?market=%s&count=1"
 
-func (s Scraper) bithumbApi(sym string) (float64, float64, error) {
+func (s *Scraper) bithumbApi(sym string) (float64, float64, error) {
 
 	url := fmt.Sprintf(bithumbUrlForm, "KRW-"+sym)
 
@@ -0,0 +1,28 @@
+package scrape
+
+import (
+	"context"
+	m "investindicator/internal/model"
+	"time"
+)
+
+// healthTTL KIS 토큰 확인 결과 재사용 시간. 만료 시 재발급을 시도하므로 상태 확인 요청마다 호출하지 않도록 제한
+const healthTTL = 30 * time.Second
+
+// Health KIS 접근 토큰 유효 여부. KIS 미설정 시 보고 대상 없음
+func (s *Scraper) Health(ctx context.Context) []m.ComponentHealth {
+
+	if s.kis == nil {
+		return nil
+	}
+
+	return []m.ComponentHealth{s.kisHealth.Get(func() m.ComponentHealth {
+		expiry, err := s.kis.CheckToken()
+		if err != nil {
+			return m.FailedComponent("kis_token", false, err)
+		}
+		h := m.HealthyComponent("kis_token", false)
+		h.Detail = "만료 시각 " + expiry.Format("2006-01-02 15:04:05")
+		return h
+	})}
+}
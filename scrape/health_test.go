@@ -0,0 +1,32 @@
+package scrape
+
+import (
+	m "investindicator/internal/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth(t *testing.T) {
+
+	s, err := NewScraper(transmitterMock{})
+	assert.NoError(t, err)
+	assert.Empty(t, s.Health(t.Context()), "KIS 미설정")
+
+	s.kis = NewKis("key", "secret", "account", "")
+	s.kis.tokenIssued = time.Now()
+	h := s.Health(t.Context())
+	if assert.Len(t, h, 1) {
+		assert.Equal(t, m.HealthDown, h[0].Status, "토큰 미발급. 1분 이내 재발급 요청 없음")
+	}
+
+	s.kis.SetAccessToken("token")
+	h = s.Health(t.Context())
+	assert.Equal(t, m.HealthDown, h[0].Status, "ttl 이내 확인 결과 재사용")
+
+	s.kisHealth = m.NewHealthCache(healthTTL)
+	h = s.Health(t.Context())
+	assert.Equal(t, m.HealthUp, h[0].Status)
+	assert.Contains(t, h[0].Detail, "만료 시각")
+}
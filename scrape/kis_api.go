@@ -30,6 +30,8 @@ type Kis struct {
 	accessToken  string
 	htsId        string
 	tokenExpired string
+	tokenIssued  time.Time  // 마지막 토큰 발급 요청 시각
+	tokenMutex   sync.Mutex // 동시 조회 시 토큰 중복 발급 방지 (KIS 토큰 발급은 분당 1회 제한)
 	account      string
 	isMock       bool // true for mock/test environment
 	lg           zerolog.Logger
@@ -63,11 +65,15 @@ func NewKis(appKey, appSecret, account, htsId string) *Kis {
 
 // SetAccessToken sets the access token and expiration time
 func (k *Kis) SetAccessToken(token string) {
+	k.tokenMutex.Lock()
+	defer k.tokenMutex.Unlock()
 	k.accessToken = token
 	k.tokenExpired = time.Now().Add(time.Duration(1) * time.Hour).Format("2006-01-02 15:04:05")
 }
 
 func (k *Kis) KisToken() (string, error) {
+	k.tokenMutex.Lock()
+	defer k.tokenMutex.Unlock()
 
 	endpoint := "/oauth2/tokenP"
 	url := k.getBaseURL() + endpoint
@@ -76,6 +82,7 @@ func (k *Kis) KisToken() (string, error) {
 		return k.accessToken, nil
 	}
 
+	k.tokenIssued = time.Now()
 	var token TokenResponse
 	err := sendRequest(url, http.MethodPost, nil, map[string]string{
 		"grant_type": "client_credentials",
@@ -93,6 +100,39 @@ func (k *Kis) KisToken() (string, error) {
 	return token.AccessToken, nil
 }
 
+/*
+CheckToken 접근 토큰 만료 시각 확인. 미발급, 만료 시 재발급 시도
+  - KIS 토큰 발급은 분당 1회 제한이라 마지막 발급 요청 후 1분 이내면 재발급하지 않고 오류 반환
+*/
+func (k *Kis) CheckToken() (time.Time, error) {
+
+	k.tokenMutex.Lock()
+	expiry, _ := time.ParseInLocation("2006-01-02 15:04:05", k.tokenExpired, time.Local)
+	valid := k.accessToken != "" && time.Now().Before(expiry)
+	recent := time.Since(k.tokenIssued) < time.Minute
+	k.tokenMutex.Unlock()
+
+	if valid {
+		return expiry, nil
+	}
+	if recent {
+		return expiry, errors.New("토큰 미발급 또는 만료. 발급 요청 1분 후 재시도")
+	}
+
+	token, err := k.KisToken()
+	if err != nil {
+		return expiry, fmt.Errorf("KisToken 시 오류 발생. %w", err)
+	}
+	if token == "" {
+		return expiry, errors.New("토큰 발급 실패. 응답에 토큰 미존재")
+	}
+
+	k.tokenMutex.Lock()
+	defer k.tokenMutex.Unlock()
+	expiry, _ = time.ParseInLocation("2006-01-02 15:04:05", k.tokenExpired, time.Local)
+	return expiry, nil
+}
+
 func (k *Kis) DomesticStockPrice(code string) (StockPrice, error) {
 
 	endpoint := "/uapi/domestic-stock/v1/quotations/inquire-price"
@@ -0,0 +1,37 @@
+package scrape
+
+import (
+	m "investindicator/internal/model"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 시세 제공처(registry) 외 조회 대상. 조회 metric의 provider label
+const (
+	naver   m.Provider = "naver"
+	fred    m.Provider = "fred"
+	goldApi m.Provider = "goldapi"
+)
+
+var (
+	scrapeDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "scrape_duration_seconds",
+		Help:    "제공처별 조회 시간",
+		Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"provider"})
+
+	scrapeErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "scrape_errors_total",
+		Help: "제공처별 조회 실패 수",
+	}, []string{"provider"})
+)
+
+// observe 제공처 조회 시간, 실패 집계. 호출 한도 대기 시간은 제외하도록 대기 이후 시각을 start로 전달
+func observe(p m.Provider, start time.Time, err error) {
+	scrapeDuration.WithLabelValues(string(p)).Observe(time.Since(start).Seconds())
+	if err != nil {
+		scrapeErrors.WithLabelValues(string(p)).Inc()
+	}
+}
@@ -0,0 +1,338 @@
+package scrape
+
+import (
+	"errors"
+	"fmt"
+	m "investindicator/internal/model"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PriceProvider 시세 조회 제공처. 제공처 추가 시 이 인터페이스를 구현하고 WithProvider로 등록
+type PriceProvider interface {
+	Name() m.Provider
+	Supports(category m.Category) bool
+	PresentPrice(category m.Category, code string) (float64, error)
+}
+
+// 종가, 최고/최저가, 평균가는 지원하는 제공처만 구현
+type closingProvider interface {
+	ClosingPrice(category m.Category, code string) (float64, error)
+}
+
+type topBottomProvider interface {
+	TopBottomPrice(category m.Category, code string) (hp float64, lp float64, err error)
+}
+
+type avgProvider interface {
+	AvgPrice(category m.Category, code string) (float64, uint, error)
+}
+
+const (
+	unhealthyThreshold = 3               // 연속 실패 시 unhealthy 전환 횟수
+	unhealthyCooldown  = 5 * time.Minute // unhealthy 유지 시간. 이후 다시 우선 순위 복귀
+)
+
+var ErrNoProvider = errors.New("조회 가능한 제공처 미존재")
+
+// errUnsupported 제공처가 해당 카테고리의 기능을 지원하지 않음. 상태(health) 집계에서 제외
+var errUnsupported = errors.New("미지원 기능")
+
+type providerHealth struct {
+	failures    int
+	lastErr     error
+	lastSuccess time.Time
+	lastFailure time.Time
+}
+
+func (h providerHealth) healthy(now time.Time) bool {
+	return h.failures < unhealthyThreshold || now.Sub(h.lastFailure) > unhealthyCooldown
+}
+
+type registry struct {
+	mu        sync.RWMutex
+	providers map[m.Provider]PriceProvider
+	health    map[m.Provider]*providerHealth
+}
+
+func newRegistry() *registry {
+	return &registry{
+		providers: make(map[m.Provider]PriceProvider),
+		health:    make(map[m.Provider]*providerHealth),
+	}
+}
+
+func (r *registry) register(p PriceProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[p.Name()] = p
+	if _, ok := r.health[p.Name()]; !ok {
+		r.health[p.Name()] = &providerHealth{}
+	}
+}
+
+func (r *registry) has(name m.Provider) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.providers[name]
+	return ok
+}
+
+// candidates 체인 순서대로 카테고리를 지원하는 제공처 목록. healthy 제공처를 앞에, unhealthy 제공처는 마지막 수단으로 뒤에 배치
+func (r *registry) candidates(category m.Category, chain []m.Provider) []PriceProvider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	healthy := make([]PriceProvider, 0, len(chain))
+	unhealthy := make([]PriceProvider, 0)
+	for _, name := range chain {
+		p, ok := r.providers[name]
+		if !ok || !p.Supports(category) {
+			continue
+		}
+		if r.health[name].healthy(now) {
+			healthy = append(healthy, p)
+		} else {
+			unhealthy = append(unhealthy, p)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (r *registry) report(name m.Provider, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, ok := r.health[name]
+	if !ok {
+		return
+	}
+	if err != nil {
+		h.failures++
+		h.lastErr = err
+		h.lastFailure = time.Now()
+		return
+	}
+	h.failures = 0
+	h.lastErr = nil
+	h.lastSuccess = time.Now()
+}
+
+func (r *registry) status() []m.ProviderStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	rtn := make([]m.ProviderStatus, 0, len(r.health))
+	for name, h := range r.health {
+		st := m.ProviderStatus{
+			Provider:    name,
+			Healthy:     h.healthy(now),
+			Failures:    h.failures,
+			LastSuccess: h.lastSuccess,
+			LastFailure: h.lastFailure,
+		}
+		if h.lastErr != nil {
+			st.LastError = h.lastErr.Error()
+		}
+		rtn = append(rtn, st)
+	}
+	sort.Slice(rtn, func(i, j int) bool {
+		return rtn[i].Provider < rtn[j].Provider
+	})
+	return rtn
+}
+
+// WithProvider 제공처 등록. 같은 이름의 기본 제공처가 있으면 대체
+func WithProvider(p PriceProvider) Option {
+	return func(s *Scraper) error {
+		if p == nil {
+			return errors.New("provider 미존재")
+		}
+		s.registry.register(p)
+		return nil
+	}
+}
+
+// registerDefaultProviders 옵션으로 등록되지 않은 기본 제공처 등록. KIS는 설정된 경우에만 등록
+func (s *Scraper) registerDefaultProviders() {
+	defaults := []PriceProvider{
+		bithumbProvider{s},
+		upbitProvider{s},
+		alpacaProvider{},
+	}
+	if s.kis != nil {
+		defaults = append(defaults, kisProvider{s.kis})
+	}
+	for _, p := range defaults {
+		if !s.registry.has(p.Name()) {
+			s.registry.register(p)
+		}
+	}
+}
+
+// ProviderStatus 제공처별 상태 조회
+func (s *Scraper) ProviderStatus() []m.ProviderStatus {
+	return s.registry.status()
+}
+
+/*
+fetch 체인 순서대로 제공처 호출. 실패 시 다음 제공처로 넘어가며 모든 제공처 실패 시 제공처별 오류를 모아서 반환
+  - call: 제공처가 해당 기능을 지원하면 호출 함수, 미지원이면 nil. 미지원 제공처는 호출 한도 대기 없이 건너뜀
+  - 조회에 성공한 제공처 이름을 함께 반환
+*/
+func fetch[T any](s *Scraper, category m.Category, chain []m.Provider, call func(PriceProvider) func() (T, error)) (T, m.Provider, error) {
+	var zero T
+	if len(chain) == 0 {
+		chain = category.DefaultProviders()
+	}
+
+	errs := make([]error, 0)
+	for _, p := range s.registry.candidates(category, chain) {
+		do := call(p)
+		if do == nil { // 해당 기능 미지원 제공처
+			continue
+		}
+		s.wait(p.Name())
+		start := time.Now()
+		v, err := do()
+		if errors.Is(err, errUnsupported) { // 해당 종목 미지원 제공처
+			continue
+		}
+		observe(p.Name(), start, err)
+		s.registry.report(p.Name(), err)
+		if err == nil {
+			if len(errs) > 0 {
+				s.lg.Warn().Errs("errors", errs).Str("provider", string(p.Name())).Msg("fallback 제공처로 조회 성공")
+			}
+			return v, p.Name(), nil
+		}
+		s.lg.Warn().Err(err).Str("provider", string(p.Name())).Msgf("%s 조회 실패", p.Name())
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+	}
+
+	if len(errs) == 0 {
+		return zero, m.NoProvider, ErrNoProvider
+	}
+	return zero, m.NoProvider, errors.Join(errs...)
+}
+
+/***************************** providers ***********************************/
+
+type kisProvider struct {
+	k *Kis
+}
+
+func (p kisProvider) Name() m.Provider { return m.KIS }
+
+func (p kisProvider) Supports(category m.Category) bool {
+	return m.KIS.Supports(category)
+}
+
+func (p kisProvider) PresentPrice(category m.Category, code string) (float64, error) {
+	switch category {
+	case m.DomesticStock, m.Gold:
+		stock, err := p.k.DomesticStockPrice(code)
+		return stock.pp, err
+	case m.DomesticETF, m.DomesticGoldETF:
+		stock, err := p.k.DomesticEtfPrice(code)
+		return stock.pp, err
+	case m.ForeignStock, m.ForeignETF:
+		pp, _, err := p.k.ForeignPrice(code)
+		return pp, err
+	}
+	return 0, errUnsupported
+}
+
+func (p kisProvider) ClosingPrice(category m.Category, code string) (float64, error) {
+	switch category {
+	case m.DomesticStock, m.Gold:
+		stock, err := p.k.DomesticStockPrice(code)
+		return stock.op, err
+	case m.DomesticETF, m.DomesticGoldETF:
+		stock, err := p.k.DomesticEtfPrice(code)
+		return stock.op, err
+	case m.ForeignStock, m.ForeignETF:
+		_, cp, err := p.k.ForeignPrice(code)
+		return cp, err
+	}
+	return 0, errUnsupported
+}
+
+func (p kisProvider) TopBottomPrice(category m.Category, code string) (float64, float64, error) {
+	switch category {
+	case m.DomesticStock:
+		stock, err := p.k.DomesticStockPrice(code)
+		return stock.hp, stock.lp, err
+	case m.DomesticETF, m.DomesticGoldETF:
+		stock, err := p.k.DomesticEtfPrice(code)
+		return stock.hp, stock.lp, err
+	}
+	return 0, 0, errUnsupported
+}
+
+func (p kisProvider) AvgPrice(category m.Category, code string) (float64, uint, error) {
+	switch category {
+	case m.DomesticStock:
+		stock, err := p.k.DomesticStockPrice(code)
+		return stock.ap, 200, err
+	case m.ForeignStock:
+		ap, n, err := p.k.ForeignAvg(code)
+		return ap, uint(n), err
+	}
+	return 0, 0, errUnsupported
+}
+
+type bithumbProvider struct {
+	s *Scraper
+}
+
+func (p bithumbProvider) Name() m.Provider { return m.Bithumb }
+
+func (p bithumbProvider) Supports(category m.Category) bool {
+	return m.Bithumb.Supports(category)
+}
+
+func (p bithumbProvider) PresentPrice(category m.Category, code string) (float64, error) {
+	pp, _, err := p.s.bithumbApi(code)
+	return pp, err
+}
+
+func (p bithumbProvider) ClosingPrice(category m.Category, code string) (float64, error) {
+	_, cp, err := p.s.bithumbApi(code)
+	return cp, err
+}
+
+type upbitProvider struct {
+	s *Scraper
+}
+
+func (p upbitProvider) Name() m.Provider { return m.Upbit }
+
+func (p upbitProvider) Supports(category m.Category) bool {
+	return m.Upbit.Supports(category)
+}
+
+func (p upbitProvider) PresentPrice(category m.Category, code string) (float64, error) {
+	pp, _, err := p.s.upbitApi(code)
+	return pp, err
+}
+
+func (p upbitProvider) ClosingPrice(category m.Category, code string) (float64, error) {
+	_, cp, err := p.s.upbitApi(code)
+	return cp, err
+}
+
+type alpacaProvider struct{}
+
+func (p alpacaProvider) Name() m.Provider { return m.Alpaca }
+
+func (p alpacaProvider) Supports(category m.Category) bool {
+	return m.Alpaca.Supports(category)
+}
+
+func (p alpacaProvider) PresentPrice(category m.Category, code string) (float64, error) {
+	return alpacaCrypto(code)
+}
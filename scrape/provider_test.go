@@ -0,0 +1,134 @@
+package scrape
+
+import (
+	"errors"
+	m "investindicator/internal/model"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type providerMock struct {
+	name  m.Provider
+	pp    float64
+	err   error
+	calls int
+}
+
+func (p *providerMock) Name() m.Provider { return p.name }
+
+func (p *providerMock) Supports(category m.Category) bool {
+	return category == m.DomesticCoin
+}
+
+func (p *providerMock) PresentPrice(category m.Category, code string) (float64, error) {
+	p.calls++
+	return p.pp, p.err
+}
+
+func TestProviderFallback(t *testing.T) {
+
+	bithumb := &providerMock{name: m.Bithumb, err: errors.New("bithumb down")}
+	upbit := &providerMock{name: m.Upbit, pp: 1000}
+
+	s, err := NewScraper(transmitterMock{}, WithProvider(bithumb), WithProvider(upbit))
+	assert.NoError(t, err)
+
+	t.Run("Fallback", func(t *testing.T) {
+		pp, err := s.PresentPrice(m.DomesticCoin, "BTC")
+		assert.NoError(t, err)
+		assert.Equal(t, 1000.0, pp)
+		assert.Equal(t, 1, bithumb.calls)
+	})
+
+	t.Run("Unhealthy", func(t *testing.T) {
+		for range unhealthyThreshold {
+			s.PresentPrice(m.DomesticCoin, "BTC")
+		}
+		calls := bithumb.calls
+
+		// unhealthy 제공처는 후순위로 밀려 호출되지 않음
+		pp, err := s.PresentPrice(m.DomesticCoin, "BTC")
+		assert.NoError(t, err)
+		assert.Equal(t, 1000.0, pp)
+		assert.Equal(t, calls, bithumb.calls)
+
+		for _, st := range s.ProviderStatus() {
+			if st.Provider == m.Bithumb {
+				assert.False(t, st.Healthy)
+				assert.Equal(t, "bithumb down", st.LastError)
+			}
+		}
+	})
+
+	t.Run("Asset Chain", func(t *testing.T) {
+		a := m.Asset{Category: m.DomesticCoin, Code: "BTC", Providers: "bithumb"}
+		_, err := s.PresentPrice(a.Category, a.Code, a.ProviderChain()...)
+		assert.Error(t, err)
+	})
+
+	t.Run("Unsupported Chain", func(t *testing.T) {
+		// 카테고리를 조회할 수 없는 체인은 카테고리 기본값 사용
+		a := m.Asset{Category: m.DomesticCoin, Code: "BTC", Providers: "alpaca"}
+		assert.Equal(t, m.DomesticCoin.DefaultProviders(), a.ProviderChain())
+		pp, err := s.PresentPrice(a.Category, a.Code, a.ProviderChain()...)
+		assert.NoError(t, err)
+		assert.Equal(t, 1000.0, pp)
+	})
+
+	t.Run("All Failed", func(t *testing.T) {
+		upbit.err = errors.New("upbit down")
+		_, err := s.PresentPrice(m.DomesticCoin, "BTC")
+		assert.ErrorContains(t, err, "bithumb down")
+		assert.ErrorContains(t, err, "upbit down")
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		assert.Equal(t, float64(bithumb.calls), testutil.ToFloat64(scrapeErrors.WithLabelValues(string(m.Bithumb))))
+		assert.Equal(t, 1.0, testutil.ToFloat64(scrapeErrors.WithLabelValues(string(m.Upbit))))
+	})
+
+	t.Run("No Provider", func(t *testing.T) {
+		_, err := s.PresentPrice(m.ShortTermBond, "")
+		assert.ErrorIs(t, err, ErrNoProvider)
+	})
+}
+
+func TestProviderUnsupported(t *testing.T) {
+
+	bithumb := &providerMock{name: m.Bithumb, pp: 1000}
+	s, err := NewScraper(transmitterMock{}, WithProvider(bithumb), WithRateLimits(map[m.Provider]float64{m.Bithumb: 0.001}))
+	assert.NoError(t, err)
+
+	_, _, err = s.TopBottomPrice(m.DomesticCoin, "BTC")
+	assert.ErrorIs(t, err, ErrNoProvider)
+	_, _, err = s.AvgPrice(m.DomesticCoin, "BTC")
+	assert.ErrorIs(t, err, ErrNoProvider)
+	assert.InDelta(t, 1.0, s.limiters[m.Bithumb].Tokens(), 0.01, "미지원 기능은 호출 한도 미소모")
+}
+
+func TestParseKisTicks(t *testing.T) {
+
+	t.Run("Domestic", func(t *testing.T) {
+		rec := "005930^093354^71900^5^-100"
+		ticks := parseKisTicks("0|H0STCNT0|002|"+rec+"^"+strings.Replace(rec, "005930", "000660", 1), nil)
+		assert.Len(t, ticks, 2)
+		assert.Equal(t, "000660", ticks[1].Code)
+		assert.Equal(t, 71900.0, ticks[0].Price)
+		assert.Equal(t, m.DomesticStock, ticks[0].Category)
+	})
+
+	t.Run("Overseas", func(t *testing.T) {
+		rec := "DNASAAPL^AAPL^4^20250101^20250101^093000^20250101^233000^190^192^189^191.5^1^0.5"
+		ticks := parseKisTicks("0|HDFSCNT0|001|"+rec, map[string]string{"AAPL": "NAS-AAPL"})
+		assert.Len(t, ticks, 1)
+		assert.Equal(t, "NAS-AAPL", ticks[0].Code)
+		assert.Equal(t, 191.5, ticks[0].Price)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		assert.Empty(t, parseKisTicks(`{"header":{"tr_id":"PINGPONG"}}`, nil))
+	})
+}
@@ -0,0 +1,54 @@
+package scrape
+
+import (
+	"context"
+	m "investindicator/internal/model"
+
+	"golang.org/x/time/rate"
+)
+
+/*
+제공처별 초당 호출 한도. token bucket으로 관리하며 한도 초과 시 호출이 대기함
+  - KIS : 실전 계좌 초당 20건. 여유를 두고 15건
+  - Bithumb : Public API 초당 150건
+  - Upbit : 시세 조회 API 초당 10건
+  - Alpaca : 무료 플랜 분당 200건
+*/
+var defaultRateLimits = map[m.Provider]float64{
+	m.KIS:     15,
+	m.Bithumb: 100,
+	m.Upbit:   8,
+	m.Alpaca:  200.0 / 60,
+}
+
+func newLimiters(limits map[m.Provider]float64) map[m.Provider]*rate.Limiter {
+	limiters := make(map[m.Provider]*rate.Limiter, len(limits))
+	for p, perSec := range limits {
+		limiters[p] = rate.NewLimiter(rate.Limit(perSec), 1)
+	}
+	return limiters
+}
+
+// WithRateLimits 제공처별 초당 호출 한도 변경. 미지정 제공처는 기본값 유지
+func WithRateLimits(limits map[m.Provider]float64) Option {
+	return func(s *Scraper) error {
+		for p, perSec := range limits {
+			if perSec <= 0 {
+				continue
+			}
+			s.limiters[p] = rate.NewLimiter(rate.Limit(perSec), 1)
+		}
+		return nil
+	}
+}
+
+// wait 제공처 호출 한도 내에서 호출 가능할 때까지 대기
+func (s *Scraper) wait(p m.Provider) {
+	l, ok := s.limiters[p]
+	if !ok {
+		return
+	}
+	if err := l.Wait(context.Background()); err != nil {
+		s.lg.Warn().Err(err).Str("provider", string(p)).Msg("rate limiter 대기 실패")
+	}
+}
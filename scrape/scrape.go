@@ -11,12 +11,14 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/alpacahq/alpaca-trade-api-go/v3/marketdata"
 	"github.com/gofiber/fiber/v2/log"
 	"github.com/rs/zerolog"
+	"golang.org/x/time/rate"
 )
 
 type Scraper struct {
@@ -24,12 +26,16 @@ type Scraper struct {
 		Rate float64
 		Date time.Time
 	}
-	kis   *Kis
-	upbit struct {
+	exMu      sync.Mutex
+	kis       *Kis
+	kisHealth *m.HealthCache
+	upbit     struct {
 		token string
 	}
-	lg zerolog.Logger
-	t  transmitter // todo. 이거 제거 하자. 다 그냥 필드로 들고 있는 것으로.
+	limiters map[m.Provider]*rate.Limiter
+	registry *registry
+	lg       zerolog.Logger
+	t        transmitter // todo. 이거 제거 하자. 다 그냥 필드로 들고 있는 것으로.
 }
 
 type transmitter interface {
@@ -41,14 +47,18 @@ type Option func(*Scraper) error
 // Functional Option Pattern
 func NewScraper(t transmitter, options ...Option) (*Scraper, error) {
 	s := &Scraper{
-		t:  t,
-		lg: zerolog.New(os.Stdout).With().Str("Module", "Scraper").Timestamp().Logger(),
+		t:         t,
+		limiters:  newLimiters(defaultRateLimits),
+		registry:  newRegistry(),
+		kisHealth: m.NewHealthCache(healthTTL),
+		lg:        zerolog.New(os.Stdout).With().Str("Module", "Scraper").Timestamp().Logger(),
 	}
 	for _, opt := range options {
 		if err := opt(s); err != nil {
 			return nil, fmt.Errorf("failed to create Scraper %w", err)
 		}
 	}
+	s.registerDefaultProviders()
 	return s, nil
 }
 
@@ -104,89 +114,99 @@ func WithKisToken(token string) Option {
 종목 이름 - 타입/심볼을 어디에 저장해 둘 것인가 => DB
 */
 
-func (s *Scraper) PresentPrice(category m.Category, code string) (pp float64, err error) {
+// PresentPrice 현재가 조회. providers 미지정 시 카테고리 기본 제공처 체인 사용
+func (s *Scraper) PresentPrice(category m.Category, code string, providers ...m.Provider) (float64, error) {
+	q, err := s.Quote(category, code, providers...)
+	return q.Price, err
+}
+
+// Quote 현재가와 조회 제공처, 조회 시각
+func (s *Scraper) Quote(category m.Category, code string, providers ...m.Provider) (m.Quote, error) {
 	s.lg.Info().Msgf("Starting PresentPrice with category: %v, code: %s", category, code)
 	switch category {
 	case m.Won:
-		return 1, nil
+		return m.Quote{Price: 1, FetchedAt: time.Now()}, nil
 	case m.Dollar:
-		return s.ExchageRate(), nil
-	case m.DomesticStock, m.Gold:
-		stock, err := s.kis.DomesticStockPrice(code)
-		return stock.pp, err
-	case m.DomesticETF, m.DomesticGoldETF:
-		stock, err := s.kis.DomesticEtfPrice(code)
-		return stock.pp, err
-	case m.DomesticCoin:
-		pp, _, err := s.bithumbApi(code)
-		return pp, err
-	case m.ForeignCoin:
-		pp, err := alpacaCrypto(code)
-		return pp, err
-	case m.ForeignStock, m.ForeignETF:
-		pp, _, err := s.kis.ForeignPrice(code)
-		return pp, err
+		return m.Quote{Price: s.ExchageRate(), FetchedAt: time.Now()}, nil
 	}
 
-	s.lg.Error().Err(err).Msg("Error in PresentPrice")
-	return 0, errors.New("미분류된 종목")
+	pp, src, err := fetch(s, category, providers, func(p PriceProvider) func() (float64, error) {
+		return func() (float64, error) { return p.PresentPrice(category, code) }
+	})
+	if err != nil {
+		s.lg.Error().Err(err).Msg("Error in PresentPrice")
+		return m.Quote{}, err
+	}
+	return m.Quote{Price: pp, Source: src, FetchedAt: time.Now()}, nil
 }
 
 func (s *Scraper) TopBottomPrice(category m.Category, code string) (hp float64, lp float64, err error) {
 	s.lg.Info().Msgf("Starting TopBottomPrice with category: %v, code: %s", category, code)
-	switch category {
-	case m.DomesticStock:
-		stock, err := s.kis.DomesticStockPrice(code)
-		return stock.hp, stock.lp, err
-	case m.DomesticETF, m.DomesticGoldETF:
-		stock, err := s.kis.DomesticEtfPrice(code)
-		return stock.hp, stock.lp, err
-		// case model.DomesticCoin:
-		// 	return 0, 0, nil
-	}
-
-	s.lg.Error().Err(err).Msg("Error in TopBottomPrice")
-	return 0, 0, errors.New("최고/최저 호출 API 미존재")
+
+	type hl struct{ hp, lp float64 }
+	v, _, err := fetch(s, category, nil, func(p PriceProvider) func() (hl, error) {
+		tp, ok := p.(topBottomProvider)
+		if !ok {
+			return nil
+		}
+		return func() (hl, error) {
+			hp, lp, err := tp.TopBottomPrice(category, code)
+			return hl{hp, lp}, err
+		}
+	})
+	if err != nil {
+		s.lg.Error().Err(err).Msg("Error in TopBottomPrice")
+		return 0, 0, fmt.Errorf("최고/최저가 조회 시 오류 발생. %w", err)
+	}
+	return v.hp, v.lp, nil
 }
 
 func (s *Scraper) AvgPrice(category m.Category, code string) (float64, uint, error) {
 	s.lg.Info().Msgf("Starting AvgPrice with category: %v, code: %s", category, code)
-	switch category {
-	case m.DomesticStock:
-		stock, err := s.kis.DomesticStockPrice(code)
-		return stock.ap, 200, err
-	case m.ForeignStock:
-		ap, n, err := s.kis.ForeignAvg(code)
-		return ap, uint(n), err
-	}
 
-	// s.lg.Error().Err(err).Msg("Error in AvgPrice")
-	return 0, 0, errors.New("평균 가격 호출 API 미존재")
+	type avg struct {
+		ap float64
+		n  uint
+	}
+	v, _, err := fetch(s, category, nil, func(p PriceProvider) func() (avg, error) {
+		ap, ok := p.(avgProvider)
+		if !ok {
+			return nil
+		}
+		return func() (avg, error) {
+			a, n, err := ap.AvgPrice(category, code)
+			return avg{a, n}, err
+		}
+	})
+	if err != nil {
+		s.lg.Error().Err(err).Msg("Error in AvgPrice")
+		return 0, 0, fmt.Errorf("평균 가격 조회 시 오류 발생. %w", err)
+	}
+	return v.ap, v.n, nil
 }
 
-func (s *Scraper) ClosingPrice(category m.Category, code string) (cp float64, err error) {
+// ClosingPrice 전일 종가 조회. providers 미지정 시 카테고리 기본 제공처 체인 사용
+func (s *Scraper) ClosingPrice(category m.Category, code string, providers ...m.Provider) (float64, error) {
 	s.lg.Info().Msgf("Starting ClosingPrice with category: %v, code: %s", category, code)
 	switch category {
 	case m.Won:
 		return 1, nil
 	case m.Dollar:
 		return s.ExchageRate(), nil
-	case m.DomesticStock, m.Gold:
-		stock, err := s.kis.DomesticStockPrice(code)
-		return stock.op, err
-	case m.DomesticCoin:
-		_, cp, err = s.bithumbApi(code)
-		return cp, err
-	case m.DomesticETF, m.DomesticGoldETF:
-		stock, err := s.kis.DomesticEtfPrice(code)
-		return stock.op, err
-	case m.ForeignStock, m.ForeignETF:
-		_, cp, err := s.kis.ForeignPrice(code)
-		return cp, err
 	}
 
-	s.lg.Error().Err(err).Msg("Error in ClosingPrice")
-	return 0, errors.New("미분류된 종목")
+	cp, _, err := fetch(s, category, providers, func(p PriceProvider) func() (float64, error) {
+		cpp, ok := p.(closingProvider)
+		if !ok {
+			return nil
+		}
+		return func() (float64, error) { return cpp.ClosingPrice(category, code) }
+	})
+	if err != nil {
+		s.lg.Error().Err(err).Msg("Error in ClosingPrice")
+		return 0, err
+	}
+	return cp, nil
 }
 
 const realEstateUrl = "https://www.ep.go.kr/www/contents.do?key=3763"
@@ -202,11 +222,15 @@ const exRateCssPath = "#main_pack > section.sc_new.cs_nexchangerate > div:nth-ch
 
 func (s *Scraper) ExchageRate() float64 {
 	s.lg.Info().Msg("Starting ExchageRate")
+	s.exMu.Lock()
+	defer s.exMu.Unlock()
 	if s.exchange.Rate != 0 && !s.exchange.Date.Before(time.Now().Add(-3*time.Hour)) {
 		return s.exchange.Rate
 	}
 
+	start := time.Now()
 	rtn, err := crawl(exRateUrl, exRateCssPath)
+	observe(naver, start, err)
 	if err != nil {
 		log.Error(err)
 	}
@@ -256,12 +280,20 @@ func (s *Scraper) FearGreedIndex() (uint, error) {
 
 func (s *Scraper) Nasdaq() (float64, error) {
 	s.lg.Info().Msg("Starting Nasdaq")
-	return s.kis.Index(Nasdaq)
+	s.wait(m.KIS)
+	start := time.Now()
+	idx, err := s.kis.Index(Nasdaq)
+	observe(m.KIS, start, err)
+	return idx, err
 }
 
 func (s *Scraper) Sp500() (float64, error) {
 	s.lg.Info().Msg("Starting Sp500")
-	return s.kis.Index(Sp500)
+	s.wait(m.KIS)
+	start := time.Now()
+	idx, err := s.kis.Index(Sp500)
+	observe(m.KIS, start, err)
+	return idx, err
 }
 
 // todo. 현재로는 크롤링/API 못 찾음
@@ -285,15 +317,16 @@ func (s *Scraper) GoldPriceDollar() (float64, error) {
 	}
 
 	var rtn map[string]interface{}
+	start := time.Now()
 	err := sendRequest(goldPriceDollarUrl, http.MethodGet, head, nil, &rtn)
+	if err == nil && rtn["error"] != nil {
+		err = fmt.Errorf("%s", rtn["error"])
+	}
+	observe(goldApi, start, err)
 	if err != nil {
 		return 0, err
 	}
 
-	if rtn["error"] != nil {
-		return 0, fmt.Errorf("%s", rtn["error"])
-	}
-
 	p := rtn["price_gram_24k"].(float64)
 
 	return p, nil
@@ -306,7 +339,9 @@ const (
 func (s *Scraper) HighYieldSpread() (date string, spread float64, err error) {
 	s.lg.Info().Msg("Starting HighYieldSpread")
 
+	start := time.Now()
 	resp, err := http.Get(highYieldSpreadUrl)
+	observe(fred, start, err)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,231 @@
+package scrape
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	m "investindicator/internal/model"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+/*
+실시간 시세 스트림. 연결이 끊기면 오류를 반환하고 종료하며, 재연결/재구독은 호출 측에서 담당
+ctx 종료 시에는 nil 반환
+*/
+
+const upbitWsUrl = "wss://api.upbit.com/websocket/v1"
+
+type upbitTicker struct {
+	Code       string  `json:"code"`
+	TradePrice float64 `json:"trade_price"`
+	Timestamp  int64   `json:"timestamp"`
+}
+
+// StreamCoinTickers 업비트 ticker 채널 구독. codes는 심볼(ex. BTC)
+func (s *Scraper) StreamCoinTickers(ctx context.Context, codes []string, c chan<- m.Tick) error {
+	if len(codes) == 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, upbitWsUrl, nil)
+	if err != nil {
+		return fmt.Errorf("upbit ticker connection 실패. %w", err)
+	}
+	defer conn.Close()
+	go closeOnDone(ctx, conn)
+
+	markets := make([]string, len(codes))
+	for i, code := range codes {
+		markets[i] = "KRW-" + code
+	}
+	message := []any{
+		map[string]any{"ticket": uuid.New().String()},
+		map[string]any{"type": "ticker", "codes": markets},
+	}
+	if err := conn.WriteJSON(message); err != nil {
+		return fmt.Errorf("upbit ticker 구독 요청 실패. %w", err)
+	}
+	s.lg.Info().Strs("codes", markets).Msg("upbit ticker 구독")
+
+	go keepPing(conn)
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("upbit ticker 수신 실패. %w", err)
+		}
+
+		var t upbitTicker
+		if err := json.Unmarshal(msg, &t); err != nil {
+			s.lg.Warn().Err(err).Str("message", string(msg)).Msg("upbit ticker 파싱 실패")
+			continue
+		}
+		code, _ := strings.CutPrefix(t.Code, "KRW-")
+		c <- m.Tick{
+			Category: m.DomesticCoin,
+			Code:     code,
+			Quote: m.Quote{
+				Price:     t.TradePrice,
+				Source:    m.Upbit,
+				FetchedAt: time.UnixMilli(t.Timestamp),
+			},
+		}
+	}
+}
+
+const (
+	kisDomesticQuoteTr = "H0STCNT0" // 국내주식 실시간체결가
+	kisOverseasQuoteTr = "HDFSCNT0" // 해외주식 실시간지연체결가
+)
+
+// StreamStockTickers KIS 실시간 체결가 구독. domestic은 종목코드, overseas는 NAS-AAPL 형식 코드
+// memo. 체결 통보(ConnectWebSocket)와 수신 루프가 다르므로 별도 connection 사용
+func (s *Scraper) StreamStockTickers(ctx context.Context, domestic []string, overseas []string, c chan<- m.Tick) error {
+	if s.kis == nil {
+		return errors.New("kis 미설정")
+	}
+	if len(domestic)+len(overseas) == 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	approval, err := s.kis.IssueWebSocketApprovalKey()
+	if err != nil {
+		return fmt.Errorf("WebSocket approval key 발급 실패. %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, s.kis.getWebSocketURL(), nil)
+	if err != nil {
+		return fmt.Errorf("kis 시세 connection 실패. %w", err)
+	}
+	defer conn.Close()
+	go closeOnDone(ctx, conn)
+
+	// 해외 tr_key는 D + 거래소 + 심볼. ex) DNASAAPL
+	overseasKeys := make(map[string]string, len(overseas)) // 심볼 -> 코드
+	subscribe := func(trID, trKey string) error {
+		return conn.WriteJSON(WebSocketSubscribeRequest{
+			Header: WebSocketSubscribeRequestHeader{
+				ApprovalKey: approval.ApprovalKey,
+				CustType:    "P",
+				TrType:      "1",
+				ContentType: "utf-8",
+			},
+			Body: WebSocketSubscribeRequestBody{
+				Input: WebSocketSubscribeRequestInput{TrID: trID, TrKey: trKey},
+			},
+		})
+	}
+	for _, code := range domestic {
+		if err := subscribe(kisDomesticQuoteTr, code); err != nil {
+			return fmt.Errorf("%s 구독 요청 실패. %w", code, err)
+		}
+	}
+	for _, code := range overseas {
+		market, sym, ok := strings.Cut(code, "-")
+		if !ok {
+			s.lg.Warn().Str("code", code).Msg("해외 종목 코드 형식 오류. 구독 제외")
+			continue
+		}
+		key := "D" + market + sym
+		overseasKeys[sym] = code
+		if err := subscribe(kisOverseasQuoteTr, key); err != nil {
+			return fmt.Errorf("%s 구독 요청 실패. %w", code, err)
+		}
+	}
+	s.lg.Info().Strs("domestic", domestic).Strs("overseas", overseas).Msg("kis 실시간 체결가 구독")
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("kis 시세 수신 실패. %w", err)
+		}
+
+		str := string(msg)
+		if strings.HasPrefix(str, "{") { // 구독 응답, PINGPONG 등 json 메시지
+			if strings.Contains(str, "PINGPONG") {
+				if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+					return fmt.Errorf("kis PINGPONG 응답 실패. %w", err)
+				}
+			}
+			continue
+		}
+
+		for _, t := range parseKisTicks(str, overseasKeys) {
+			c <- t
+		}
+	}
+}
+
+// parseKisTicks 실시간 메시지 파싱. ETF 여부는 구분하지 않음(국내는 DomesticStock, 해외는 ForeignStock)
+// 형식 : 암호화여부|TR_ID|건수|데이터(^ 구분, 건수만큼 반복)
+func parseKisTicks(msg string, overseasKeys map[string]string) []m.Tick {
+	parts := strings.SplitN(msg, "|", 4)
+	if len(parts) < 4 {
+		return nil
+	}
+	n, err := strconv.Atoi(parts[2])
+	if err != nil || n <= 0 {
+		return nil
+	}
+	fields := strings.Split(parts[3], "^")
+	size := len(fields) / n
+	if size == 0 {
+		return nil
+	}
+
+	ticks := make([]m.Tick, 0, n)
+	for i := range n {
+		rec := fields[i*size : (i+1)*size]
+		var t m.Tick
+		switch parts[1] {
+		case kisDomesticQuoteTr: // 0 : 종목코드, 2 : 현재가
+			if len(rec) < 3 {
+				continue
+			}
+			t.Category = m.DomesticStock
+			t.Code = rec[0]
+			t.Price, err = strconv.ParseFloat(rec[2], 64)
+		case kisOverseasQuoteTr: // 1 : 심볼, 11 : 현재가
+			if len(rec) < 12 {
+				continue
+			}
+			t.Category = m.ForeignStock
+			t.Code = overseasKeys[rec[1]]
+			t.Price, err = strconv.ParseFloat(rec[11], 64)
+		default:
+			continue
+		}
+		if err != nil || t.Code == "" {
+			continue
+		}
+		t.Source = m.KIS
+		t.FetchedAt = time.Now()
+		ticks = append(ticks, t)
+	}
+	return ticks
+}
+
+func closeOnDone(ctx context.Context, conn *websocket.Conn) {
+	<-ctx.Done()
+	conn.Close()
+}
@@ -32,7 +32,7 @@ func WithUpbitToken(accessKey string, secretKey string) Option {
 
 const upbitUrlForm = "https://api.upbit.com/v1/candles/days?market=%s&count=1"
 
-func (s Scraper) upbitApi(sym string) (float64, float64, error) {
+func (s *Scraper) upbitApi(sym string) (float64, float64, error) {
 
 	url := fmt.Sprintf(upbitUrlForm, "KRW-"+sym) // KRW-SYMBOL 형식
 
@@ -78,7 +78,7 @@ type UpbitMyOrders struct {
 	Error           error
 }
 
-func (s Scraper) upbitMyOrders(callback func(*UpbitMyOrders)) error {
+func (s *Scraper) upbitMyOrders(callback func(*UpbitMyOrders)) error {
 	headers := http.Header{}
 	headers.Add("Authorization", fmt.Sprintf("Bearer %s", s.upbit.token))
 
@@ -128,7 +128,6 @@ func (s Scraper) upbitMyOrders(callback func(*UpbitMyOrders)) error {
 		}
 		callback(&order)
 	}
-	return nil
 }
 
 func keepPing(conn *websocket.Conn) {
@@ -13,6 +13,13 @@ type StorageMock struct {
 	market *md.Market
 	assets []md.Asset
 	ivsm   []md.InvestSummary
+	navs   []md.FundNav
+	emas   []md.EmaHist
+	idx    []md.DailyIndex
+	hy     []md.HighYieldSpread
+	tb     map[uint][2]float64 // 자산별 갱신된 최고가/최저가
+	ivs    *[]md.Invest        // RecordInvests로 저장된 투자 이력
+	cache  map[string]string
 	err    error
 }
 
@@ -46,7 +53,10 @@ func (m StorageMock) RetrieveAsset(id uint) (*md.Asset, error) {
 	return &md.Asset{}, nil
 }
 
-func (m StorageMock) UpdateAssetInfo(md.Asset) error {
+func (m StorageMock) UpdateAssetTopBottom(id uint, top float64, bottom float64) error {
+	if m.tb != nil {
+		m.tb[id] = [2]float64{top, bottom}
+	}
 	return nil
 }
 
@@ -79,16 +89,12 @@ func (m StorageMock) UpdateInvestSummarySum(fundId uint, assetId uint, sum float
 	return nil
 }
 
-func (m StorageMock) UpdateInvestSummary(fundId uint, assetId uint, change float64, price float64) error {
+func (m StorageMock) RecordInvests(invests []md.Invest, changes []md.SummaryChange) error {
 	if m.err != nil {
 		return m.err
 	}
-	return nil
-}
-
-func (m StorageMock) SaveInvest(fundId uint, assetId uint, price float64, count float64) error {
-	if m.err != nil {
-		return m.err
+	if m.ivs != nil {
+		*m.ivs = append(*m.ivs, invests...)
 	}
 	return nil
 }
@@ -115,12 +121,54 @@ func (m StorageMock) SaveEmaHist(newEma *md.EmaHist) error {
 	return nil
 }
 
+func (m StorageMock) SaveFundNav(fundId uint, amount float64) error {
+	return nil
+}
+
+func (m StorageMock) SavePremium(assetId uint, premium float64) error {
+	return nil
+}
+
+func (m StorageMock) RetrieveFundNavs(fundId uint, since time.Time) ([]md.FundNav, error) {
+	rtn := make([]md.FundNav, 0)
+	for _, n := range m.navs {
+		if n.FundID == fundId {
+			rtn = append(rtn, n)
+		}
+	}
+	return rtn, nil
+}
+
+func (m StorageMock) RetrieveEmaHists(assetId uint, since time.Time) ([]md.EmaHist, error) {
+	rtn := make([]md.EmaHist, 0)
+	for _, h := range m.emas {
+		if h.AssetID == assetId {
+			rtn = append(rtn, h)
+		}
+	}
+	return rtn, nil
+}
+
+func (m StorageMock) RetrieveMarketIndicators(since time.Time) ([]md.DailyIndex, error) {
+	return m.idx, nil
+}
+
+func (m StorageMock) RetrieveHighYieldSpreads(since time.Time) ([]md.HighYieldSpread, error) {
+	return m.hy, nil
+}
+
 func (m StorageMock) RetrieveTotalAssets() ([]md.Asset, error) {
 	return m.assets, nil
 }
 
 func (m StorageMock) RetreiveFundSummaryByAssetId(id uint) ([]md.InvestSummary, error) {
-	return nil, nil
+	rtn := make([]md.InvestSummary, 0)
+	for _, s := range m.ivsm {
+		if s.AssetID == id {
+			rtn = append(rtn, s)
+		}
+	}
+	return rtn, nil
 }
 
 func (m StorageMock) RetreiveEventIsActive(eventId uint) bool {
@@ -148,7 +196,14 @@ func (m StorageMock) SetCache(key string, value interface{}, exp time.Duration)
 }
 
 func (m StorageMock) GetCache(key string) *redis.StringCmd {
-	return nil
+	if v, ok := m.cache[key]; ok {
+		return redis.NewStringResult(v, nil)
+	}
+	return redis.NewStringResult("", redis.Nil)
+}
+
+func (m StorageMock) CacheKeys(pattern string) ([]string, error) {
+	return nil, nil
 }
 
 func (m StorageMock) SaveSP500Entry(sp500 *m.SP500Company) error {
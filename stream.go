@@ -0,0 +1,158 @@
+package investind
+
+import (
+	"context"
+	"fmt"
+	m "investindicator/internal/model"
+	"investindicator/notify"
+	"slices"
+	"time"
+)
+
+const (
+	streamRefreshInterval = 10 * time.Minute // 자산 목록 재조회 주기. 구독 대상이 바뀌면 재구독
+	tickEvalInterval      = 10 * time.Second // 자산별 매수/매도 판단 최소 간격
+	reconnectMaxBackoff   = time.Minute
+	reconnectAlertAfter   = 5 // 연속 재연결 실패 시 알림 전송 횟수
+)
+
+// streamTargets 실시간 시세 구독 대상
+type streamTargets struct {
+	coins    []string
+	domestic []string
+	overseas []string
+}
+
+func newStreamTargets(assets []m.Asset) streamTargets {
+	var t streamTargets
+	for _, a := range assets {
+		if a.Code == "" {
+			continue
+		}
+		switch a.Category {
+		case m.DomesticCoin:
+			t.coins = append(t.coins, a.Code)
+		case m.DomesticStock, m.DomesticETF, m.DomesticGoldETF:
+			t.domestic = append(t.domestic, a.Code)
+		case m.ForeignStock, m.ForeignETF:
+			t.overseas = append(t.overseas, a.Code)
+		}
+	}
+	slices.Sort(t.coins)
+	slices.Sort(t.domestic)
+	slices.Sort(t.overseas)
+	return t
+}
+
+func (t streamTargets) equal(o streamTargets) bool {
+	return slices.Equal(t.coins, o.coins) && slices.Equal(t.domestic, o.domestic) && slices.Equal(t.overseas, o.overseas)
+}
+
+// runPriceStreamEvent 실시간 시세 스트림 구독 및 bus 전달. 연결이 끊기면 재연결하고, 자산 목록이 바뀌면 재구독
+func (e InvestIndicator) runPriceStreamEvent() {
+	tc := make(chan m.Tick, 256)
+	go e.pb.Forward(tc)
+	go e.consumeTicks()
+
+	var cur streamTargets
+	var cancel context.CancelFunc
+	for {
+		assets, err := e.stg.RetrieveAssetList()
+		if err != nil {
+			e.lg.Error().Err(err).Msg("[PriceStream] RetrieveAssetList 시, 에러 발생")
+		} else if next := newStreamTargets(assets); cancel == nil || !next.equal(cur) {
+			if cancel != nil {
+				cancel()
+			}
+			cur = next
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+
+			go e.reconnectLoop(ctx, "StreamCoinTickers", func(ctx context.Context) error {
+				return e.rt.StreamCoinTickers(ctx, next.coins, tc)
+			})
+			go e.reconnectLoop(ctx, "StreamStockTickers", func(ctx context.Context) error {
+				return e.rt.StreamStockTickers(ctx, next.domestic, next.overseas, tc)
+			})
+			e.lg.Info().Int("coins", len(cur.coins)).Int("domestic", len(cur.domestic)).Int("overseas", len(cur.overseas)).Msg("실시간 시세 구독 갱신")
+		}
+		time.Sleep(streamRefreshInterval)
+	}
+}
+
+// reconnectLoop 스트림 종료 시 backoff 후 재연결. 일정 시간 이상 유지된 연결이 끊기면 backoff 초기화
+func (e InvestIndicator) reconnectLoop(ctx context.Context, name string, stream func(context.Context) error) {
+	backoff := time.Second
+	failures := 0
+	for ctx.Err() == nil {
+		start := time.Now()
+		e.hs.streamUp(name)
+		err := stream(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		e.hs.streamDown(name, err)
+		if time.Since(start) > reconnectMaxBackoff {
+			backoff = time.Second
+			failures = 0
+		}
+		failures++
+		streamReconnects.WithLabelValues(name).Inc()
+		e.lg.Error().Err(err).Dur("backoff", backoff).Msgf("[%s] 연결 종료. 재연결 예정", name)
+		if failures == reconnectAlertAfter {
+			e.ms.SendMessage(notify.Errors, fmt.Sprintf("[%s] 재연결 %d회 연속 실패. 오류 내역: %s", name, failures, err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, reconnectMaxBackoff)
+	}
+}
+
+// consumeTicks bus로 들어온 tick을 시세 캐시에 반영하고 매수/매도 알림 및 최고/최저가 갱신
+func (e InvestIndicator) consumeTicks() {
+	ticks, _ := e.pb.Subscribe(256)
+
+	assets := make([]m.Asset, 0)
+	var loadedAt time.Time
+	lastEval := make(map[uint]time.Time)
+
+	for t := range ticks {
+		if time.Since(loadedAt) > time.Minute {
+			li, err := e.stg.RetrieveAssetList()
+			if err != nil {
+				e.lg.Error().Err(err).Msg("[consumeTicks] RetrieveAssetList 시, 에러 발생")
+			} else {
+				assets = li
+				loadedAt = time.Now()
+			}
+		}
+
+		for i := range assets {
+			a := &assets[i]
+			if !matchTick(*a, t) {
+				continue
+			}
+			e.qc.Put(a.Category, a.Code, t.Quote)
+
+			if time.Since(lastEval[a.ID]) < tickEvalInterval {
+				continue
+			}
+			lastEval[a.ID] = time.Now()
+			if msg := e.buySellMsg(a, t.Price); msg != "" {
+				e.ms.SendMessage(notify.Alerts, msg)
+			}
+		}
+	}
+}
+
+// matchTick tick과 자산 매칭. KIS 스트림은 ETF 여부를 구분하지 않으므로 같은 제공처 카테고리면 종목 코드로 매칭
+func matchTick(a m.Asset, t m.Tick) bool {
+	if a.Code != t.Code {
+		return false
+	}
+	return a.Category == t.Category || a.Category.Provider() == t.Category.Provider()
+}